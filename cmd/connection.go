@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/taigrr/teaqlite/internal/app"
+)
+
+// Connection flags, shared by every command that opens a database
+var connFlags struct {
+	pragmas     []string
+	busyTimeout time.Duration
+	foreignKeys bool
+	journalMode string
+	cacheSize   int
+}
+
+// journalModes are the values PRAGMA journal_mode accepts
+var journalModes = []string{"delete", "truncate", "persist", "memory", "wal", "off"}
+
+// connectionOptions collects the connection flags
+func connectionOptions(readOnly bool) (app.ConnectionOptions, error) {
+	opts := app.DefaultConnectionOptions()
+	opts.ReadOnly = readOnly
+	opts.BusyTimeout = connFlags.busyTimeout
+
+	if connFlags.foreignKeys {
+		opts.Pragmas = append(opts.Pragmas, app.Pragma{Name: "foreign_keys", Value: "on"})
+	}
+	if mode := strings.ToLower(connFlags.journalMode); mode != "" {
+		if !slices.Contains(journalModes, mode) {
+			return opts, fmt.Errorf("invalid journal mode %q (valid modes: %s)", connFlags.journalMode, strings.Join(journalModes, ", "))
+		}
+		opts.Pragmas = append(opts.Pragmas, app.Pragma{Name: "journal_mode", Value: mode})
+	}
+	if connFlags.cacheSize != 0 {
+		opts.Pragmas = append(opts.Pragmas, app.Pragma{Name: "cache_size", Value: strconv.Itoa(connFlags.cacheSize)})
+	}
+	for _, s := range connFlags.pragmas {
+		p, err := app.ParsePragma(s)
+		if err != nil {
+			return opts, err
+		}
+		opts.Pragmas = append(opts.Pragmas, p)
+	}
+	return opts, nil
+}
+
+// openDatabase opens a database file or file: URI with the connection
+// flags and the attachments, and returns the data source name it used
+func openDatabase(path string, readOnly bool, attachments ...app.Attachment) (*sql.DB, string, error) {
+	opts, err := connectionOptions(readOnly)
+	if err != nil {
+		return nil, "", err
+	}
+	opts.Attachments = attachments
+	dsn, err := app.DataSource(path, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	db, err := app.OpenDatabase(path, opts)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open database: %w", err)
+	}
+	return db, dsn, nil
+}
+
+// databaseExists reports whether the file behind a path or URI exists. In
+// memory databases always exist.
+func databaseExists(path string) bool {
+	file := app.DatabaseFile(path)
+	if file == "" {
+		return true
+	}
+	_, err := os.Stat(file)
+	return !os.IsNotExist(err)
+}
+
+// uriReadOnly reports whether a file: URI opens the database with mode=ro
+func uriReadOnly(path string) bool {
+	_, query, ok := strings.Cut(path, "?")
+	if !ok || !strings.HasPrefix(path, "file:") {
+		return false
+	}
+	params, err := url.ParseQuery(query)
+	return err == nil && params.Get("mode") == "ro"
+}
+
+// openExisting opens a database file that must already exist
+func openExisting(path string) (*sql.DB, error) {
+	if !databaseExists(path) {
+		return nil, fmt.Errorf("database file '%s' does not exist", app.DatabaseFile(path))
+	}
+	db, _, err := openDatabase(path, false)
+	return db, err
+}
+
+func init() {
+	flags := rootCmd.PersistentFlags()
+	flags.StringArrayVar(&connFlags.pragmas, "pragma", nil, "Run PRAGMA name=value on every connection (repeatable)")
+	flags.DurationVar(&connFlags.busyTimeout, "busy-timeout", app.DefaultBusyTimeout, "How long to wait for a lock held by another connection")
+	flags.BoolVar(&connFlags.foreignKeys, "foreign-keys", false, "Enforce foreign key constraints")
+	flags.StringVar(&connFlags.journalMode, "journal-mode", "", "Journal mode: "+strings.Join(journalModes, ", "))
+	flags.IntVar(&connFlags.cacheSize, "cache-size", 0, "Page cache size in pages, or in KiB when negative")
+}
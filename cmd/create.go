@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"bufio"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/taigrr/teaqlite/internal/app"
+)
+
+// confirmCreate asks on the terminal whether a missing database should be
+// created. Without a terminal there is no one to ask and the answer is no.
+func confirmCreate(cmd *cobra.Command, path string) bool {
+	if stdinIsPiped() || len(commands) > 0 {
+		return false
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Database file '%s' does not exist. Create it? [y/N] ", path)
+	answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// initDatabase sets up a new database, running the schema file when one
+// is given. SQLite only writes the file header once something is stored,
+// so an empty database is vacuumed to leave a valid file behind.
+func initDatabase(db *sql.DB, schema string) error {
+	if schema == "" {
+		_, err := db.Exec("VACUUM")
+		return err
+	}
+	file, err := os.Open(schema)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if _, err := app.Restore(db, file); err != nil {
+		return fmt.Errorf("schema %s: %w", schema, err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/taigrr/teaqlite/internal/app"
+)
+
+var dumpFlags struct {
+	output     string
+	dataOnly   bool
+	schemaOnly bool
+}
+
+var dumpCmd = &cobra.Command{
+	Use:   "dump <database.db> [table...]",
+	Short: "Write the database as an SQL script",
+	Long: `Dump writes the schema and rows of the database as SQL statements, like the
+sqlite3 .dump command: tables in foreign key order with their rows, then indexes,
+triggers and views. Naming tables limits the dump to them and their indexes and
+triggers. Load the dump with teaqlite restore.`,
+	Example: `  teaqlite dump shop.db > shop.sql
+  teaqlite dump shop.db customers orders -o subset.sql
+  teaqlite dump shop.db --schema-only`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f := dumpFlags
+		if f.dataOnly && f.schemaOnly {
+			return errors.New("--data-only and --schema-only cannot be combined")
+		}
+		db, err := openExisting(args[0])
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		var w io.Writer = cmd.OutOrStdout()
+		var file *os.File
+		if f.output != "" && f.output != "-" {
+			if file, err = os.Create(f.output); err != nil {
+				return err
+			}
+			defer file.Close()
+			w = file
+		}
+
+		opts := app.DumpOptions{Tables: args[1:], DataOnly: f.dataOnly, SchemaOnly: f.schemaOnly}
+		if err := app.Dump(db, w, opts); err != nil {
+			if file != nil {
+				file.Close()
+				os.Remove(f.output)
+			}
+			return fmt.Errorf("dump failed: %w", err)
+		}
+		if file != nil {
+			return file.Close()
+		}
+		return nil
+	},
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore <dump.sql> <new.db>",
+	Short: "Create a database from an SQL dump",
+	Long: `Restore runs an SQL script, such as one written by teaqlite dump or sqlite3
+.dump, to create a new database. Use - to read the script from standard input.
+The database file must not exist yet; it is removed again if the script fails.`,
+	Example: `  teaqlite restore shop.sql shop-copy.db
+  teaqlite dump shop.db | teaqlite restore - shop-copy.db`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		source, target := args[0], args[1]
+		if _, err := os.Stat(target); err == nil {
+			return fmt.Errorf("database file '%s' already exists", target)
+		}
+
+		var r io.Reader = cmd.InOrStdin()
+		if source != "-" {
+			file, err := os.Open(source)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			r = file
+		}
+
+		db, _, err := openDatabase(target, false)
+		if err != nil {
+			return err
+		}
+		n, err := app.Restore(db, r)
+		if closeErr := db.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(target)
+			return fmt.Errorf("restore failed: %w", err)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "restored %s from %d statements\n", target, n)
+		return nil
+	},
+}
+
+func init() {
+	flags := dumpCmd.Flags()
+	flags.StringVarP(&dumpFlags.output, "output", "o", "", "File to write (default: standard output)")
+	flags.BoolVar(&dumpFlags.dataOnly, "data-only", false, "Only write the rows")
+	flags.BoolVar(&dumpFlags.schemaOnly, "schema-only", false, "Only write the schema")
+	rootCmd.AddCommand(dumpCmd, restoreCmd)
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+
+	"github.com/taigrr/teaqlite/internal/app"
+)
+
+var exportFlags struct {
+	query        string
+	output       string
+	format       string
+	noHeader     bool
+	delimiter    string
+	null         string
+	blob         string
+	table        string
+	noColumnList bool
+	batch        int
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export <database.db> [table]",
+	Short: "Export a table or query result",
+	Long: `Export writes a table, or the result of --query, as CSV, TSV, JSON, NDJSON,
+SQL INSERT statements, or a Markdown, ASCII or HTML table. The format is taken
+from --format, or else from the extension of --output.`,
+	Example: `  teaqlite export shop.db orders -o orders.csv
+  teaqlite export shop.db -q "SELECT * FROM orders WHERE total > 100" -f markdown
+  teaqlite export shop.db orders -f sql --batch 100 > seed.sql`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f := exportFlags
+		if (len(args) == 2) == (f.query != "") {
+			return errors.New("give either a table or --query")
+		}
+
+		opts, err := exportOptions()
+		if err != nil {
+			return err
+		}
+
+		db, err := openExisting(args[0])
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		var w io.Writer = cmd.OutOrStdout()
+		var file *os.File
+		if f.output != "" && f.output != "-" {
+			if file, err = os.Create(f.output); err != nil {
+				return err
+			}
+			defer file.Close()
+			w = file
+		}
+
+		var n int
+		if f.query != "" {
+			n, err = app.ExportQuery(db, w, f.query, opts)
+		} else {
+			n, err = app.ExportTable(db, w, args[1], opts)
+		}
+		if err != nil {
+			if file != nil {
+				file.Close()
+				os.Remove(f.output)
+			}
+			return fmt.Errorf("export failed: %w", err)
+		}
+		if file != nil {
+			if err := file.Close(); err != nil {
+				os.Remove(f.output)
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "exported %d rows to %s\n", n, f.output)
+		}
+		return nil
+	},
+}
+
+// exportOptions builds the export options from the flags
+func exportOptions() (app.ExportOptions, error) {
+	f := exportFlags
+	opts := app.DefaultExportOptions()
+
+	format := f.format
+	if format == "" {
+		format = "csv"
+		if ext := filepath.Ext(f.output); ext != "" {
+			format = ext
+		}
+	}
+	var err error
+	if opts.Format, err = app.ParseExportFormat(format); err != nil {
+		return opts, err
+	}
+
+	if utf8.RuneCountInString(f.delimiter) != 1 {
+		return opts, fmt.Errorf("the delimiter must be a single character, got %q", f.delimiter)
+	}
+	opts.Delimiter, _ = utf8.DecodeRuneInString(f.delimiter)
+
+	switch blob := app.BlobEncoding(f.blob); blob {
+	case app.BlobHex, app.BlobBase64, app.BlobText:
+		opts.Blob = blob
+	default:
+		return opts, fmt.Errorf("unknown BLOB encoding %q, use hex, base64 or text", f.blob)
+	}
+
+	if f.batch < 1 {
+		return opts, fmt.Errorf("the batch size must be at least 1")
+	}
+	opts.Header = !f.noHeader
+	opts.Null = f.null
+	opts.Table = f.table
+	opts.ColumnList = !f.noColumnList
+	opts.Batch = f.batch
+	return opts, nil
+}
+
+func init() {
+	flags := exportCmd.Flags()
+	flags.StringVarP(&exportFlags.query, "query", "q", "", "Export the result of this query instead of a table")
+	flags.StringVarP(&exportFlags.output, "output", "o", "", "File to write (default: standard output)")
+	flags.StringVarP(&exportFlags.format, "format", "f", "", "csv, tsv, json, ndjson, sql, markdown, ascii or html (default: from the --output extension, else csv)")
+	flags.BoolVar(&exportFlags.noHeader, "no-header", false, "Leave out the header line of CSV, TSV, ASCII and HTML")
+	flags.StringVar(&exportFlags.delimiter, "delimiter", ",", "CSV field delimiter")
+	flags.StringVar(&exportFlags.null, "null", "", "Text written for NULL values")
+	flags.StringVar(&exportFlags.blob, "blob", "hex", "BLOB encoding: hex, base64 or text")
+	flags.StringVar(&exportFlags.table, "table", "", "Table the INSERT statements write to (default: the exported table)")
+	flags.BoolVar(&exportFlags.noColumnList, "no-column-list", false, "Leave out the column list of INSERT statements")
+	flags.IntVar(&exportFlags.batch, "batch", 1, "Rows per INSERT statement")
+	rootCmd.AddCommand(exportCmd)
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"database/sql"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+
+	_ "modernc.org/sqlite"
+)
+
+// runExport runs the export command with the flags reset to their defaults
+func runExport(t *testing.T, args ...string) error {
+	t.Helper()
+	exportCmd.LocalFlags().VisitAll(func(f *pflag.Flag) {
+		f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+	rootCmd.SetArgs(append([]string{"export"}, args...))
+	rootCmd.SetOut(io.Discard)
+	rootCmd.SetErr(io.Discard)
+	return rootCmd.Execute()
+}
+
+func TestExportCommand(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "shop.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`CREATE TABLE orders(id INTEGER, total REAL);
+		INSERT INTO orders VALUES (1, 12.5), (2, 3)`); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	tests := []struct {
+		name    string
+		args    []string
+		output  string
+		want    string
+		wantErr bool
+	}{
+		{"table", []string{path, "orders"}, "orders.csv", "id,total\n1,12.5\n2,3\n", false},
+		{"format from extension", []string{path, "-q", "SELECT id FROM orders WHERE id = 2"}, "orders.ndjson", `{"id":2}` + "\n", false},
+		{"format flag", []string{path, "orders", "-f", "tsv", "--no-header"}, "orders.txt", "1\t12.5\n2\t3\n", false},
+		{"failing query", []string{path, "-q", "SELECT * FROM missing"}, "missing.csv", "", true},
+		{"missing table", []string{path, "missing"}, "missing.sql", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := filepath.Join(dir, tt.output)
+			err := runExport(t, append(tt.args, "-o", output)...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error: %v", err, tt.wantErr)
+			}
+			got, readErr := os.ReadFile(output)
+			if tt.wantErr {
+				if readErr == nil {
+					t.Errorf("failed export left %s behind", tt.output)
+				}
+				return
+			}
+			if readErr != nil {
+				t.Fatal(readErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
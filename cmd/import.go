@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+
+	"github.com/taigrr/teaqlite/internal/app"
+)
+
+var importFlags struct {
+	table     string
+	format    string
+	noHeader  bool
+	delimiter string
+	null      string
+	mappings  []string
+	preview   bool
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import <database.db> <file>",
+	Short: "Import a CSV, TSV, JSON or NDJSON file into a table",
+	Long: `Import loads the rows of a file into a table in one transaction. A table that
+does not exist is created with column types inferred from the data; the columns
+of an existing table are matched by name, or mapped with --map. Rows that do not
+fit their columns or break a constraint are rejected and listed; the other rows
+are imported, and the command exits with a non-zero status.`,
+	Example: `  teaqlite import shop.db customers.csv
+  teaqlite import shop.db export.json --table orders --map id=order_id --map note=
+  teaqlite import shop.db data.tsv --preview`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f := importFlags
+		opts, err := importOptions(args[1])
+		if err != nil {
+			return err
+		}
+
+		db, err := openExisting(args[0])
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		file, err := os.Open(args[1])
+		if err != nil {
+			return err
+		}
+		data, err := app.ReadImport(file, opts)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", args[1], err)
+		}
+
+		table := f.table
+		if table == "" {
+			base := filepath.Base(args[1])
+			table = strings.TrimSuffix(base, filepath.Ext(base))
+		}
+		plan, err := app.PlanImport(db, data, table)
+		if err != nil {
+			return err
+		}
+		for _, mapping := range f.mappings {
+			source, target, ok := strings.Cut(mapping, "=")
+			if !ok {
+				return fmt.Errorf("invalid mapping %q, use <file column>=<table column>", mapping)
+			}
+			if err := plan.Map(source, target); err != nil {
+				return err
+			}
+		}
+
+		if f.preview {
+			return previewImport(cmd, data, plan)
+		}
+
+		result, err := app.Import(db, data, plan)
+		if err != nil {
+			return fmt.Errorf("import failed: %w", err)
+		}
+		out := cmd.ErrOrStderr()
+		for _, rejected := range result.Rejected {
+			fmt.Fprintf(out, "%s %d rejected: %v\n", result.Unit, rejected.Line, rejected.Err)
+		}
+		fmt.Fprintf(out, "imported %d rows into %s\n", result.Inserted, plan.Table)
+		if len(result.Rejected) > 0 {
+			return fmt.Errorf("rejected %d of %d rows", len(result.Rejected), len(data.Rows))
+		}
+		return nil
+	},
+}
+
+// importOptions builds the import options from the flags and the file name
+func importOptions(path string) (app.ImportOptions, error) {
+	f := importFlags
+	opts := app.DefaultImportOptions()
+
+	format := f.format
+	if format == "" {
+		format = filepath.Ext(path)
+	}
+	var err error
+	if opts.Format, err = app.ParseImportFormat(format); err != nil {
+		return opts, err
+	}
+	if utf8.RuneCountInString(f.delimiter) != 1 {
+		return opts, fmt.Errorf("the delimiter must be a single character, got %q", f.delimiter)
+	}
+	opts.Delimiter, _ = utf8.DecodeRuneInString(f.delimiter)
+	opts.Header = !f.noHeader
+	opts.Null = f.null
+	return opts, nil
+}
+
+// previewImport prints the column mapping and the first rows of the file
+func previewImport(cmd *cobra.Command, data *app.ImportData, plan *app.ImportPlan) error {
+	out := cmd.OutOrStdout()
+	action := "into existing table"
+	if plan.Create {
+		action = "into new table"
+	}
+	fmt.Fprintf(out, "%d rows %s %s\n\n", len(data.Rows), action, plan.Table)
+	for _, col := range plan.Columns {
+		switch {
+		case col.Target == "":
+			fmt.Fprintf(out, "  %s → (skipped)\n", col.Source)
+		case plan.Create:
+			fmt.Fprintf(out, "  %s → %s %s\n", col.Source, col.Target, col.Type)
+		default:
+			fmt.Fprintf(out, "  %s → %s\n", col.Source, col.Target)
+		}
+	}
+	fmt.Fprintln(out)
+	return data.Preview(out, 10)
+}
+
+func init() {
+	flags := importCmd.Flags()
+	flags.StringVarP(&importFlags.table, "table", "t", "", "Table to import into (default: the file name without extension)")
+	flags.StringVarP(&importFlags.format, "format", "f", "", "csv, tsv, json or ndjson (default: from the file extension)")
+	flags.BoolVar(&importFlags.noHeader, "no-header", false, "The CSV or TSV file has no header line")
+	flags.StringVar(&importFlags.delimiter, "delimiter", ",", "CSV field delimiter")
+	flags.StringVar(&importFlags.null, "null", "", "CSV and TSV text read as NULL")
+	flags.StringArrayVar(&importFlags.mappings, "map", nil, "Map a file column to a table column as file=table; an empty table column skips it (repeatable)")
+	flags.BoolVar(&importFlags.preview, "preview", false, "Show the column mapping and the first rows without importing")
+	rootCmd.AddCommand(importCmd)
+}
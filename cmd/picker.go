@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"database/sql"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/taigrr/teaqlite/internal/app"
+)
+
+// runPicker shows the database picker and opens the chosen database in the
+// same program, so the TUI starts without a restart
+func runPicker(session *tuiSession) error {
+	var db *sql.DB
+	defer func() {
+		if db != nil {
+			db.Close()
+		}
+	}()
+
+	open := func(path string) (tea.Model, error) {
+		ro := readOnly || uriReadOnly(path)
+		opened, dsn, err := openDatabase(path, ro)
+		if err != nil {
+			return nil, err
+		}
+		m, err := session.model(opened, path, dsn, ro)
+		if err != nil {
+			opened.Close()
+			return nil, err
+		}
+		db = opened
+		return m, nil
+	}
+
+	picker := app.NewPickerModel(open,
+		app.WithPickerConfig(session.config),
+		app.WithRecentDatabases(session.recent),
+	)
+	return runProgram(picker)
+}
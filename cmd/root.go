@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 
@@ -15,45 +16,203 @@ import (
 )
 
 var (
-	dbPath string
+	configPath   string
+	commands     []string
+	outputFormat string
+	createDB     bool
+	schemaPath   string
+	readOnly     bool
+	safeMode     bool
 )
 
 var rootCmd = &cobra.Command{
-	Use:   "teaqlite [database.db]",
+	Use:   "teaqlite [database.db [[alias=]other.db...]]",
 	Short: "A TUI for SQLite databases",
-	Long:  `TeaQLite is a terminal user interface for browsing and editing SQLite databases.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `TeaQLite is a terminal user interface for browsing and editing SQLite databases.
+
+Without a database, teaqlite starts with a picker that offers the recently
+used databases and a file browser listing SQLite files.
+
+With -c, or with an SQL script on standard input, teaqlite runs the statements
+instead of starting the TUI, prints the rows they return and exits with a
+non-zero status when a statement fails.
+
+A database that does not exist is created with --create, optionally from a
+schema file given with --schema; in a terminal teaqlite asks first.
+
+--read-only opens the database so that nothing can change it. --safe asks
+before running UPDATE or DELETE without WHERE, DROP and ALTER, and refuses
+such statements in scripts.
+
+Further databases are attached to the first one, under the alias given as
+alias=file or else under their file name without extension, so queries can
+use their tables as alias.table.`,
+	Example: `  teaqlite
+  teaqlite shop.db
+  teaqlite --create --schema schema.sql new.db
+  teaqlite --read-only prod-snapshot.db
+  teaqlite shop.db old=backup/shop.db
+  teaqlite shop.db -c "SELECT count(*) FROM orders" -f csv
+  teaqlite shop.db -f json < report.sql`,
+	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		dbPath = args[0]
+		if len(args) == 0 {
+			if len(commands) > 0 || stdinIsPiped() {
+				return errors.New("a database is required to run SQL")
+			}
+			// Report bad connection flags now rather than after picking
+			if _, err := connectionOptions(readOnly); err != nil {
+				return err
+			}
+			session, err := loadSession()
+			if err != nil {
+				return err
+			}
+			return runPicker(session)
+		}
+
+		dbPath := args[0]
+		readOnly = readOnly || uriReadOnly(dbPath)
+		file := app.DatabaseFile(dbPath)
+		var attachments []app.Attachment
+		for _, arg := range args[1:] {
+			attachments = append(attachments, app.ParseAttachment(arg))
+		}
 
-		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
-			return fmt.Errorf("database file '%s' does not exist", dbPath)
+		create := false
+		if !databaseExists(dbPath) {
+			if readOnly {
+				return fmt.Errorf("database file '%s' does not exist", file)
+			}
+			if !createDB && schemaPath == "" && !confirmCreate(cmd, file) {
+				return fmt.Errorf("database file '%s' does not exist (use --create to create it)", file)
+			}
+			create = true
+		} else if schemaPath != "" {
+			return fmt.Errorf("database file '%s' already exists; --schema only initialises new databases", file)
 		}
 
-		db, err := sql.Open("sqlite", dbPath)
+		db, dsn, err := openDatabase(dbPath, readOnly, attachments...)
 		if err != nil {
-			return fmt.Errorf("failed to open database: %w", err)
+			if create && file != "" {
+				os.Remove(file)
+			}
+			return err
 		}
 		defer db.Close()
 
-		m := app.InitialModel(db)
-		if m.Err() != nil {
-			return m.Err()
+		if create {
+			if err := initDatabase(db, schemaPath); err != nil {
+				db.Close()
+				if file != "" {
+					os.Remove(file)
+				}
+				return fmt.Errorf("failed to create database: %w", err)
+			}
 		}
 
-		p := tea.NewProgram(m, tea.WithAltScreen())
-		if _, err := p.Run(); err != nil {
-			return fmt.Errorf("failed to run TUI: %w", err)
+		if len(commands) > 0 || stdinIsPiped() {
+			return runScript(cmd, db)
 		}
 
-		return nil
+		session, err := loadSession()
+		if err != nil {
+			return err
+		}
+		m, err := session.model(db, dbPath, dsn, readOnly)
+		if err != nil {
+			return err
+		}
+		return runProgram(m)
 	},
 }
 
+// tuiSession holds what the TUI loads besides the database
+type tuiSession struct {
+	config       *app.Config
+	savedQueries *app.SavedQueries
+	recent       *app.RecentDatabases
+}
+
+// loadSession reads the config file, the saved queries and the recently
+// used databases
+func loadSession() (*tuiSession, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if safeMode {
+		config.Settings.SafeMode = true
+	}
+	s := &tuiSession{config: config, recent: app.NewRecentDatabases()}
+	if path, err := app.DefaultSavedQueriesPath(); err == nil {
+		if s.savedQueries, err = app.LoadSavedQueries(path); err != nil {
+			return nil, fmt.Errorf("failed to load saved queries: %w", err)
+		}
+	}
+	if path, err := app.DefaultRecentDatabasesPath(); err == nil {
+		if s.recent, err = app.LoadRecentDatabases(path); err != nil {
+			return nil, fmt.Errorf("failed to load recent databases: %w", err)
+		}
+	}
+	return s, nil
+}
+
+// model builds the TUI for an open database and records the database as
+// recently used
+func (s *tuiSession) model(db *sql.DB, path, dsn string, readOnly bool) (*app.Model, error) {
+	opts := []app.Option{app.WithConfig(s.config), app.WithDataSource(dsn)}
+	if readOnly {
+		opts = append(opts, app.WithReadOnlyDatabase())
+	}
+	if s.savedQueries != nil {
+		opts = append(opts, app.WithSavedQueries(s.savedQueries))
+	}
+
+	m := app.InitialModel(db, opts...)
+	if m.Err() != nil {
+		return nil, m.Err()
+	}
+	// The recent list is a convenience; failing to write it is not fatal
+	_ = s.recent.Add(path)
+	return m, nil
+}
+
+// runProgram runs the TUI until it quits
+func runProgram(m tea.Model) error {
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion(), tea.WithOutput(app.Output))
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("failed to run TUI: %w", err)
+	}
+	return nil
+}
+
+// loadConfig reads the file given with --config, or the default config
+// file when it exists
+func loadConfig() (*app.Config, error) {
+	path := configPath
+	if path == "" {
+		var err error
+		if path, err = app.DefaultConfigPath(); err != nil {
+			return app.DefaultConfig(), nil
+		}
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			return app.DefaultConfig(), nil
+		}
+	}
+	return app.LoadConfig(path)
+}
+
 func Execute() error {
 	return fang.Execute(context.Background(), rootCmd)
 }
 
 func init() {
-	rootCmd.Flags().StringVarP(&dbPath, "database", "d", "", "Path to SQLite database file")
+	rootCmd.Flags().StringArrayVarP(&commands, "command", "c", nil, "Run SQL statements and print the results instead of starting the TUI (repeatable)")
+	rootCmd.Flags().StringVarP(&outputFormat, "format", "f", "table", "Output format of -c and scripts: table, csv, tsv, json, ndjson, markdown or html")
+	rootCmd.Flags().BoolVar(&createDB, "create", false, "Create the database file if it does not exist")
+	rootCmd.Flags().StringVar(&schemaPath, "schema", "", "SQL file to initialise a new database with (implies --create)")
+	rootCmd.Flags().BoolVar(&readOnly, "read-only", false, "Open the database read-only; edits and writing statements are refused")
+	rootCmd.Flags().BoolVar(&safeMode, "safe", false, "Ask before UPDATE or DELETE without WHERE, DROP and ALTER; scripts refuse them")
+	rootCmd.Flags().StringVar(&configPath, "config", "", "Path to the config file (default: teaqlite/config.toml in the user config directory)")
 }
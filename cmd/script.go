@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/taigrr/teaqlite/internal/app"
+)
+
+// stdinIsPiped reports whether standard input is a file or pipe rather
+// than a terminal, in which case it holds a script to run
+func stdinIsPiped() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice == 0
+}
+
+// runScript runs the -c statements, or the script on standard input, and
+// prints their results
+func runScript(cmd *cobra.Command, db *sql.DB) error {
+	format, err := app.ParseOutputFormat(outputFormat)
+	if err != nil {
+		return err
+	}
+	opts := app.DefaultExportOptions()
+	opts.Format = format
+	if format == app.FormatASCII {
+		opts.Null = app.NullText
+	}
+
+	script := strings.Join(commands, ";\n")
+	if len(commands) == 0 {
+		input, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return err
+		}
+		script = string(input)
+	}
+	if safeMode {
+		// There is no one to confirm risky statements
+		if risks := app.RiskyStatements(script); len(risks) > 0 {
+			return fmt.Errorf("safe mode: refusing to run %s", strings.Join(risks, "; "))
+		}
+	}
+	return app.RunScript(db, cmd.OutOrStdout(), script, opts)
+}
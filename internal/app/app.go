@@ -8,8 +8,8 @@ import (
 	"sync/atomic"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 	_ "modernc.org/sqlite" // Import SQLite driver
 )
 
@@ -31,6 +31,22 @@ type AppKeyMap struct {
 	Quit       key.Binding
 	Suspend    key.Binding
 	ToggleHelp key.Binding
+	Dismiss    key.Binding
+	MessageLog key.Binding
+	Back       key.Binding
+	Forward    key.Binding
+	NewTab     key.Binding
+	CloseTab   key.Binding
+	NextTab    key.Binding
+	PrevTab    key.Binding
+	RenameTab  key.Binding
+	GoToTab    key.Binding
+	// Split layout
+	ToggleSplit  key.Binding
+	NextPane     key.Binding
+	ToggleSchema key.Binding
+	// Commands
+	Palette key.Binding
 }
 
 // DefaultAppKeyMap returns the default keybindings
@@ -48,13 +64,70 @@ func DefaultAppKeyMap() AppKeyMap {
 			key.WithKeys("ctrl+g"),
 			key.WithHelp("ctrl+g", "toggle help"),
 		),
+		Dismiss: key.NewBinding(
+			key.WithKeys("ctrl+x"),
+			key.WithHelp("ctrl+x", "dismiss notifications"),
+		),
+		MessageLog: key.NewBinding(
+			key.WithKeys("ctrl+l"),
+			key.WithHelp("ctrl+l", "message log"),
+		),
+		// alt+arrows are left to the editors for word motion
+		Back: key.NewBinding(
+			key.WithKeys("alt+h"),
+			key.WithHelp("alt+h", "back"),
+		),
+		Forward: key.NewBinding(
+			key.WithKeys("alt+l"),
+			key.WithHelp("alt+l", "forward"),
+		),
+		NewTab: key.NewBinding(
+			key.WithKeys("alt+t"),
+			key.WithHelp("alt+t", "new tab"),
+		),
+		CloseTab: key.NewBinding(
+			key.WithKeys("alt+w"),
+			key.WithHelp("alt+w", "close tab"),
+		),
+		NextTab: key.NewBinding(
+			key.WithKeys("ctrl+pgdown", "alt+]"),
+			key.WithHelp("alt+]", "next tab"),
+		),
+		PrevTab: key.NewBinding(
+			key.WithKeys("ctrl+pgup", "alt+["),
+			key.WithHelp("alt+[", "previous tab"),
+		),
+		RenameTab: key.NewBinding(
+			key.WithKeys("alt+r"),
+			key.WithHelp("alt+r", "rename tab"),
+		),
+		GoToTab: key.NewBinding(
+			key.WithKeys("alt+1", "alt+2", "alt+3", "alt+4", "alt+5", "alt+6", "alt+7", "alt+8", "alt+9"),
+			key.WithHelp("alt+1…9", "go to tab"),
+		),
+		ToggleSplit: key.NewBinding(
+			key.WithKeys("f2"),
+			key.WithHelp("f2", "toggle split layout"),
+		),
+		NextPane: key.NewBinding(
+			key.WithKeys("f3"),
+			key.WithHelp("f3", "next pane"),
+		),
+		ToggleSchema: key.NewBinding(
+			key.WithKeys("f4"),
+			key.WithHelp("f4", "row details/schema"),
+		),
+		Palette: key.NewBinding(
+			key.WithKeys("alt+x"),
+			key.WithHelp("alt+x", "command palette"),
+		),
 	}
 }
 
 // Custom message types
 type (
 	SwitchToTableListMsg          struct{}
-	SwitchToTableListClearMsg     struct{} // Switch to table list and clear any filter
+	SwitchToTableListClearMsg     struct{} // Return to the table list and clear the history
 	SwitchToTableDataMsg          struct{ TableIndex int }
 	SwitchToRowDetailMsg          struct{ RowIndex int }
 	SwitchToRowDetailFromQueryMsg struct{ RowIndex int }
@@ -67,23 +140,59 @@ type (
 		RowIndex, ColIndex int
 		Value              string
 	}
-	ExecuteQueryMsg   struct{ Query string }
-	QueryCompletedMsg struct {
+	UpdateCellFailedMsg struct {
+		RowIndex, ColIndex int
+		Value              string
+		Err                error
+	}
+	FollowForeignKeyMsg struct{ RowIndex, ColIndex int }
+	OpenReferencesMsg   struct{ Reference RowReference }
+	NavigateBackMsg     struct{}
+	NavigateForwardMsg  struct{}
+	ExecuteQueryMsg     struct{ Query string }
+	QueryCompletedMsg   struct {
+		ID      int // ID of the query view that ran the query
 		Results [][]string
 		Columns []string
+		Types   []string // Declared types of the columns
 		Error   error
 	}
 )
 
 // Model is the main application model
 type Model struct {
-	db          *sql.DB
-	currentView tea.Model
-	width       int
-	height      int
-	err         error
-	keyMap      AppKeyMap
-	focused     bool
+	db       *sql.DB
+	width    int
+	height   int
+	err      error
+	keyMap   AppKeyMap
+	focused  bool
+	notifier Notifier
+	// Open tabs; the active one is embedded
+	*tab
+	tabs        []*tab
+	activeTab   int
+	renaming    bool
+	renameInput textinput.Model
+	// Split layout with a table list sidebar and a detail pane
+	split      bool
+	focus      pane
+	sidebar    *TableListModel
+	detail     tea.Model
+	detailKey  string
+	showSchema bool
+	mousePane  pane // pane that received the last mouse press
+	// Commands
+	commands     []Command
+	settings     *Settings
+	savedQueries *SavedQueries
+	bindings     map[string]string // key to command line
+	cmdLine      commandLine
+	palette      *palette
+	// The database was opened read-only, so read-only mode stays on
+	readOnlyDB bool
+	// Data source name the database was opened with
+	dataSource string
 }
 
 // Option is a functional option for configuring the Model
@@ -96,6 +205,36 @@ func WithKeyMap(km AppKeyMap) Option {
 	}
 }
 
+// WithSettings sets the user preferences
+func WithSettings(s *Settings) Option {
+	return func(m *Model) {
+		m.settings = s
+	}
+}
+
+// WithReadOnlyDatabase tells the model the database was opened read-only,
+// which turns read-only mode on for good
+func WithReadOnlyDatabase() Option {
+	return func(m *Model) {
+		m.readOnlyDB = true
+	}
+}
+
+// WithDataSource records the data source name the database was opened
+// with, which the status view shows
+func WithDataSource(dsn string) Option {
+	return func(m *Model) {
+		m.dataSource = dsn
+	}
+}
+
+// WithSavedQueries sets the store for named queries
+func WithSavedQueries(q *SavedQueries) Option {
+	return func(m *Model) {
+		m.savedQueries = q
+	}
+}
+
 // WithDimensions sets the initial dimensions
 func WithDimensions(width, height int) Option {
 	return func(m *Model) {
@@ -104,15 +243,34 @@ func WithDimensions(width, height int) Option {
 	}
 }
 
+// SortOrder orders the rows of a table by one column
+type SortOrder struct {
+	Column string
+	Desc   bool
+}
+
+// orderBy builds the SQL ordering term for the sort order
+func (o *SortOrder) orderBy() string {
+	if o.Desc {
+		return quoteIdent(o.Column) + " DESC"
+	}
+	return quoteIdent(o.Column)
+}
+
 // SharedData that all models need access to
 type SharedData struct {
 	DB             *sql.DB
-	Tables         []string
+	Schemas        []string // main and the attached databases
+	Tables         []string // qualified with the schema outside main, see qualifyTable
 	FilteredTables []string
 	TableData      [][]string
 	FilteredData   [][]string
 	Columns        []string
+	ColumnInfo     []ColumnInfo
 	PrimaryKeys    []string
+	ForeignKeys    []ForeignKey
+	Filter         *RowFilter // Restricts the loaded rows, e.g. when following a foreign key
+	Sort           *SortOrder // Orders the loaded rows, set by clicking a column header
 	SelectedTable  int
 	TotalRows      int
 	CurrentPage    int
@@ -120,7 +278,12 @@ type SharedData struct {
 	Height         int
 	// Query result context
 	IsQueryResult  bool
-	QueryTableName string // For simple queries, store the source table
+	QueryTableName string   // For simple queries, store the source table
+	ResultTypes    []string // Declared types of the query result columns
+	Settings       *Settings
+	completer      *Completer
+	// Cells edited in this session, see cellKey
+	modified map[string]bool
 }
 
 func NewSharedData(db *sql.DB) *SharedData {
@@ -130,30 +293,67 @@ func NewSharedData(db *sql.DB) *SharedData {
 		FilteredData:   [][]string{},
 		Width:          80,
 		Height:         24,
+		Settings:       DefaultSettings(),
+		modified:       map[string]bool{},
 	}
 }
 
+// LoadTables lists the tables of the main database followed by those of
+// each attached database
 func (s *SharedData) LoadTables() error {
-	query := `SELECT name FROM sqlite_master WHERE type='table' ORDER BY name`
-	rows, err := s.DB.Query(query)
+	schemas, err := loadSchemas(s.DB)
 	if err != nil {
 		return err
 	}
-	defer rows.Close()
-
+	s.Schemas = schemas
 	s.Tables = []string{}
-	for rows.Next() {
-		var name string
-		if err := rows.Scan(&name); err != nil {
+	for _, schema := range schemas {
+		tables, err := s.loadSchemaTables(schema)
+		if err != nil {
 			return err
 		}
-		s.Tables = append(s.Tables, name)
+		s.Tables = append(s.Tables, tables...)
 	}
 	s.FilteredTables = make([]string, len(s.Tables))
 	copy(s.FilteredTables, s.Tables)
+
+	// Schema may have changed, so drop cached completions
+	if s.completer != nil {
+		s.completer.Invalidate()
+		s.completer.SetTables(s.Tables)
+	}
 	return nil
 }
 
+// loadSchemaTables returns the tables of one schema, qualified
+func (s *SharedData) loadSchemaTables(schema string) ([]string, error) {
+	query := fmt.Sprintf(`SELECT name FROM %s.sqlite_master WHERE type='table' ORDER BY name`, quoteIdent(schema))
+	rows, err := s.DB.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		tables = append(tables, qualifyTable(schema, name))
+	}
+	return tables, rows.Err()
+}
+
+// Completer returns the SQL completer, creating it on first use
+func (s *SharedData) Completer() *Completer {
+	if s.completer == nil {
+		s.completer = NewCompleter(s.DB)
+		s.completer.SetTables(s.Tables)
+	}
+	return s.completer
+}
+
 func (s *SharedData) LoadTableData() error {
 	if s.SelectedTable >= len(s.FilteredTables) {
 		return fmt.Errorf("invalid table selection")
@@ -162,41 +362,51 @@ func (s *SharedData) LoadTableData() error {
 	tableName := s.FilteredTables[s.SelectedTable]
 
 	// Get column info and primary keys
-	rows, err := s.DB.Query(fmt.Sprintf("PRAGMA table_info(%s)", tableName))
+	columnInfo, err := loadColumnInfo(s.DB, tableName)
 	if err != nil {
 		return err
 	}
-	defer rows.Close()
 
+	s.ColumnInfo = columnInfo
 	s.Columns = []string{}
 	s.PrimaryKeys = []string{}
-	for rows.Next() {
-		var cid int
-		var name, dataType string
-		var notNull, pk int
-		var defaultValue sql.NullString
-
-		if err := rows.Scan(&cid, &name, &dataType, &notNull, &defaultValue, &pk); err != nil {
-			return err
-		}
-		s.Columns = append(s.Columns, name)
-		if pk == 1 {
-			s.PrimaryKeys = append(s.PrimaryKeys, name)
+	for _, col := range columnInfo {
+		s.Columns = append(s.Columns, col.Name)
+		if col.PrimaryKey == 1 {
+			s.PrimaryKeys = append(s.PrimaryKeys, col.Name)
 		}
 	}
 
+	s.ForeignKeys, err = loadForeignKeys(s.DB, tableName)
+	if err != nil {
+		return err
+	}
+
+	whereClause := ""
+	var args []any
+	if s.Filter != nil {
+		var where string
+		where, args = s.Filter.where()
+		whereClause = " WHERE " + where
+	}
+
 	// Get total row count
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s", tableName)
-	err = s.DB.QueryRow(countQuery).Scan(&s.TotalRows)
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s%s", quoteTable(tableName), whereClause)
+	err = s.DB.QueryRow(countQuery, args...).Scan(&s.TotalRows)
 	if err != nil {
 		return err
 	}
 
+	orderClause := ""
+	if s.Sort != nil {
+		orderClause = " ORDER BY " + s.Sort.orderBy()
+	}
+
 	// Get paginated data
-	offset := s.CurrentPage * PageSize
-	dataQuery := fmt.Sprintf("SELECT * FROM %s LIMIT %d OFFSET %d", tableName, PageSize, offset)
+	offset := s.CurrentPage * s.pageSize()
+	dataQuery := fmt.Sprintf("SELECT %s FROM %s%s%s LIMIT %d OFFSET %d", selectColumns(columnInfo), quoteTable(tableName), whereClause, orderClause, s.pageSize(), offset)
 
-	rows, err = s.DB.Query(dataQuery)
+	rows, err := s.DB.Query(dataQuery, args...)
 	if err != nil {
 		return err
 	}
@@ -216,11 +426,7 @@ func (s *SharedData) LoadTableData() error {
 
 		row := make([]string, len(s.Columns))
 		for i, val := range values {
-			if val == nil {
-				row[i] = "NULL"
-			} else {
-				row[i] = fmt.Sprintf("%v", val)
-			}
+			row[i] = formatValue(val)
 		}
 		s.TableData = append(s.TableData, row)
 	}
@@ -235,9 +441,34 @@ func (s *SharedData) LoadTableData() error {
 	return nil
 }
 
-func (s *SharedData) UpdateCell(rowIndex, colIndex int, newValue string) error {
+// cellUpdate is the statement that stores an edited cell
+type cellUpdate struct {
+	Table  string
+	Query  string
+	Args   []any
+	Stored StoredValue
+}
+
+// SQL returns the statement with its arguments written as literals
+func (u cellUpdate) SQL() string {
+	var b strings.Builder
+	args := u.Args
+	for _, r := range u.Query {
+		if r == '?' && len(args) > 0 {
+			b.WriteString(sqlLiteral(args[0]))
+			args = args[1:]
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String() + ";"
+}
+
+// prepareUpdate builds the UPDATE statement that sets a cell to newValue,
+// identifying the row by its primary key or, without one, by all values
+func (s *SharedData) prepareUpdate(rowIndex, colIndex int, newValue string) (cellUpdate, error) {
 	if rowIndex >= len(s.FilteredData) || colIndex >= len(s.Columns) {
-		return fmt.Errorf("invalid row or column index")
+		return cellUpdate{}, fmt.Errorf("invalid row or column index")
 	}
 
 	var tableName string
@@ -251,7 +482,7 @@ func (s *SharedData) UpdateCell(rowIndex, colIndex int, newValue string) error {
 			// Try to infer table from column names and data
 			tableName, err = s.inferTableFromQueryResult(rowIndex, colIndex)
 			if err != nil {
-				return fmt.Errorf("cannot determine source table for query result: %v", err)
+				return cellUpdate{}, fmt.Errorf("cannot determine source table for query result: %v", err)
 			}
 		}
 	} else {
@@ -264,9 +495,13 @@ func (s *SharedData) UpdateCell(rowIndex, colIndex int, newValue string) error {
 	// Get table info for the target table to find primary keys
 	tableColumns, tablePrimaryKeys, err := s.getTableInfo(tableName)
 	if err != nil {
-		return fmt.Errorf("failed to get table info for %s: %v", tableName, err)
+		return cellUpdate{}, fmt.Errorf("failed to get table info for %s: %v", tableName, err)
 	}
 
+	// Apply the column affinity so the value is stored with the right type
+	column := s.ColumnInfoFor(colIndex)
+	stored := convertValue(column, newValue)
+
 	// Build WHERE clause using primary keys or all columns if no primary key
 	var whereClause strings.Builder
 	var args []any
@@ -281,10 +516,14 @@ func (s *SharedData) UpdateCell(rowIndex, colIndex int, newValue string) error {
 			// Find the value for this primary key in our data
 			pkValue, err := s.findColumnValue(rowIndex, pkCol, tableColumns)
 			if err != nil {
-				return fmt.Errorf("failed to find primary key value for %s: %v", pkCol, err)
+				return cellUpdate{}, fmt.Errorf("failed to find primary key value for %s: %v", pkCol, err)
 			}
 
-			whereClause.WriteString(fmt.Sprintf("%s = ?", pkCol))
+			if pkValue == NullValue {
+				whereClause.WriteString(fmt.Sprintf("%s IS NULL", quoteIdent(pkCol)))
+				continue
+			}
+			whereClause.WriteString(fmt.Sprintf("%s = ?", quoteIdent(pkCol)))
 			args = append(args, pkValue)
 		}
 	} else {
@@ -296,24 +535,47 @@ func (s *SharedData) UpdateCell(rowIndex, colIndex int, newValue string) error {
 
 			colValue, err := s.findColumnValue(rowIndex, col, tableColumns)
 			if err != nil {
-				return fmt.Errorf("failed to find column value for %s: %v", col, err)
+				return cellUpdate{}, fmt.Errorf("failed to find column value for %s: %v", col, err)
 			}
 
-			whereClause.WriteString(fmt.Sprintf("%s = ?", col))
+			if colValue == NullValue {
+				whereClause.WriteString(fmt.Sprintf("%s IS NULL", quoteIdent(col)))
+				continue
+			}
+			whereClause.WriteString(fmt.Sprintf("%s = ?", quoteIdent(col)))
 			args = append(args, colValue)
 		}
 	}
 
-	// Execute UPDATE
-	updateQuery := fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s", tableName, columnName, whereClause.String())
-	args = append([]any{newValue}, args...)
+	updateQuery := fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s", quoteTable(tableName), quoteIdent(columnName), whereClause.String())
+	args = append([]any{stored.Arg}, args...)
+	return cellUpdate{Table: tableName, Query: updateQuery, Args: args, Stored: stored}, nil
+}
 
-	_, err = s.DB.Exec(updateQuery, args...)
+func (s *SharedData) UpdateCell(rowIndex, colIndex int, newValue string) error {
+	if s.readOnly() {
+		return errReadOnly
+	}
+
+	update, err := s.prepareUpdate(rowIndex, colIndex, newValue)
 	if err != nil {
 		return err
 	}
+	if err := s.checkCellConstraints(update.Table, rowIndex, s.ColumnInfoFor(colIndex), update.Stored); err != nil {
+		return err
+	}
+
+	// Execute UPDATE
+	result, err := s.DB.Exec(update.Query, update.Args...)
+	if err != nil {
+		return classifyConstraintError(err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("no row was updated; it may have been changed or deleted, refresh and try again")
+	}
 
 	// Update local data
+	newValue = update.Stored.Display
 	s.FilteredData[rowIndex][colIndex] = newValue
 	// Also update the original data if it exists
 	for i, row := range s.TableData {
@@ -331,37 +593,108 @@ func (s *SharedData) UpdateCell(rowIndex, colIndex int, newValue string) error {
 			}
 		}
 	}
+	if !s.IsQueryResult {
+		if s.modified == nil {
+			s.modified = map[string]bool{}
+		}
+		s.modified[s.cellKey(rowIndex, colIndex)] = true
+	}
 
 	return nil
 }
 
+// cellKey identifies a cell of the current table by the primary key of its
+// row, or by the other values of the row when the table has none, so the
+// mark survives reloads and sorting
+func (s *SharedData) cellKey(rowIndex, colIndex int) string {
+	hasKey := false
+	for j := range s.Columns {
+		if s.ColumnInfoFor(j).PrimaryKey > 0 {
+			hasKey = true
+			break
+		}
+	}
+	parts := []string{s.currentTableName(), s.Columns[colIndex]}
+	for j, value := range s.FilteredData[rowIndex] {
+		if hasKey && s.ColumnInfoFor(j).PrimaryKey > 0 || !hasKey && j != colIndex {
+			parts = append(parts, value)
+		}
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// isModified reports whether a cell of the current table was edited
+func (s *SharedData) isModified(rowIndex, colIndex int) bool {
+	if s.IsQueryResult || len(s.modified) == 0 || rowIndex >= len(s.FilteredData) || colIndex >= len(s.Columns) {
+		return false
+	}
+	return s.modified[s.cellKey(rowIndex, colIndex)]
+}
+
 // Helper function to get table info
 func (s *SharedData) getTableInfo(tableName string) ([]string, []string, error) {
-	rows, err := s.DB.Query(fmt.Sprintf("PRAGMA table_info(%s)", tableName))
+	info, err := loadColumnInfo(s.DB, tableName)
 	if err != nil {
 		return nil, nil, err
 	}
-	defer rows.Close()
 
 	var columns []string
 	var primaryKeys []string
 
-	for rows.Next() {
-		var cid int
-		var name, dataType string
-		var notNull, pk int
-		var defaultValue sql.NullString
+	for _, col := range info {
+		columns = append(columns, col.Name)
+		if col.PrimaryKey == 1 {
+			primaryKeys = append(primaryKeys, col.Name)
+		}
+	}
+
+	return columns, primaryKeys, nil
+}
 
-		if err := rows.Scan(&cid, &name, &dataType, &notNull, &defaultValue, &pk); err != nil {
-			return nil, nil, err
+// ColumnInfoFor returns the definition of a displayed column. For query
+// results the column is looked up in the source table when it is known;
+// otherwise a ColumnInfo with only the name is returned.
+func (s *SharedData) ColumnInfoFor(colIndex int) ColumnInfo {
+	if colIndex >= len(s.Columns) {
+		return ColumnInfo{}
+	}
+	name := s.Columns[colIndex]
+
+	if !s.IsQueryResult {
+		if colIndex < len(s.ColumnInfo) {
+			return s.ColumnInfo[colIndex]
 		}
-		columns = append(columns, name)
-		if pk == 1 {
-			primaryKeys = append(primaryKeys, name)
+		return ColumnInfo{Name: name}
+	}
+
+	tableName := s.QueryTableName
+	if tableName == "" {
+		tableName, _ = s.inferTableFromQueryResult(0, colIndex)
+	}
+	if tableName != "" {
+		if info, err := loadColumnInfo(s.DB, tableName); err == nil {
+			for _, col := range info {
+				if col.Name == name {
+					return col
+				}
+			}
 		}
 	}
+	return ColumnInfo{Name: name}
+}
 
-	return columns, primaryKeys, nil
+// displayValue returns the text shown for a cell of a displayed column
+func (s *SharedData) displayValue(colIndex int, v string) string {
+	declared := ""
+	if s.IsQueryResult && colIndex < len(s.ResultTypes) {
+		declared = s.ResultTypes[colIndex]
+	} else if !s.IsQueryResult && colIndex < len(s.ColumnInfo) {
+		declared = s.ColumnInfo[colIndex].Type
+	}
+	if isDateType(declared) {
+		return DisplayDate(v)
+	}
+	return DisplayValue(v)
 }
 
 // Helper function to find a column value in the current row
@@ -395,7 +728,7 @@ func (s *SharedData) findColumnValue(rowIndex int, columnName string, _ []string
 			}
 
 			if pkIndex >= 0 {
-				whereClause.WriteString(fmt.Sprintf("%s = ?", pkCol))
+				whereClause.WriteString(fmt.Sprintf("%s = ?", quoteIdent(pkCol)))
 				args = append(args, s.FilteredData[rowIndex][pkIndex])
 			}
 		}
@@ -407,13 +740,14 @@ func (s *SharedData) findColumnValue(rowIndex int, columnName string, _ []string
 				tableName, _ = s.inferTableFromQueryResult(rowIndex, 0)
 			}
 
-			query := fmt.Sprintf("SELECT %s FROM %s WHERE %s", columnName, tableName, whereClause.String())
-			var value string
+			// +column has no declared type, so the driver reads it as stored
+			query := fmt.Sprintf("SELECT +%s FROM %s WHERE %s", quoteIdent(columnName), quoteTable(tableName), whereClause.String())
+			var value any
 			err := s.DB.QueryRow(query, args...).Scan(&value)
 			if err != nil {
 				return "", err
 			}
-			return value, nil
+			return formatValue(value), nil
 		}
 	}
 
@@ -449,30 +783,6 @@ func (s *SharedData) inferTableFromQueryResult(_, _ int) (string, error) {
 	return "", fmt.Errorf("could not infer source table from query result")
 }
 
-// Styles
-var (
-	TitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#FAFAFA")).
-			Background(lipgloss.Color("#7D56F4")).
-			Padding(0, 1)
-
-	SelectedStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#FAFAFA")).
-			Background(lipgloss.Color("#F25D94"))
-
-	NormalStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FAFAFA"))
-
-	ErrorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FF0000")).
-			Bold(true)
-
-	HelpStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#626262"))
-)
-
 // Utility functions
 
 func TruncateString(s string, maxLen int) string {
@@ -537,28 +847,48 @@ func Max(a, b int) int {
 }
 
 func InitialModel(db *sql.DB, opts ...Option) *Model {
-	shared := NewSharedData(db)
-	if err := shared.LoadTables(); err != nil {
-		return &Model{err: err}
-	}
-
 	m := &Model{
 		db:          db,
-		currentView: NewTableListModel(shared),
 		width:       80,
 		height:      24,
 		keyMap:      DefaultAppKeyMap(),
 		focused:     true,
+		commands:    defaultCommands(),
+		settings:    DefaultSettings(),
+		bindings:    map[string]string{},
+		renameInput: newRenameInput(),
+		cmdLine:     newCommandLine(),
 	}
 
 	// Apply options
 	for _, opt := range opts {
 		opt(m)
 	}
+	if m.savedQueries == nil {
+		m.savedQueries = NewSavedQueries()
+	}
+	if m.readOnlyDB {
+		m.settings.ReadOnly = true
+	}
+	m.cmdLine.keyMap = m.settings.KeyMaps.CommandLine
+
+	shared := m.newSharedData()
+	// A model that failed to load still renders the error and quits
+	m.err = shared.LoadTables()
+	first := &tab{currentView: NewTableListModel(shared)}
+	m.tab = first
+	m.tabs = []*tab{first}
 
 	return m
 }
 
+// newSharedData creates shared data that uses the application settings
+func (m *Model) newSharedData() *SharedData {
+	shared := NewSharedData(m.db)
+	shared.Settings = m.settings
+	return shared
+}
+
 // Focus sets the focus state of the application
 func (m *Model) Focus() {
 	m.focused = true
@@ -583,30 +913,42 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	model, cmd := m.update(msg)
+	m.syncPanes()
+	return model, cmd
+}
+
+func (m *Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if cmd, ok := m.handlePaneMsg(msg); ok {
+		return m, cmd
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		// Update current view with new dimensions
-		switch v := m.currentView.(type) {
-		case *TableListModel:
-			v.Shared.Width = m.width
-			v.Shared.Height = m.height
-		case *TableDataModel:
-			v.Shared.Width = m.width
-			v.Shared.Height = m.height
-		case *RowDetailModel:
-			v.Shared.Width = m.width
-			v.Shared.Height = m.height
-		case *EditCellModel:
-			v.Shared.Width = m.width
-			v.Shared.Height = m.height
-		case *QueryModel:
-			v.Shared.Width = m.width
-			v.Shared.Height = m.height
-		}
+		m.resize()
 
 	case tea.KeyMsg:
+		if m.renaming {
+			return m, m.handleRename(msg)
+		}
+		if m.palette != nil {
+			return m, m.handlePalette(msg)
+		}
+		if m.cmdLine.active {
+			return m, m.handleCommandLine(msg)
+		}
+		if line, ok := m.bindings[msg.String()]; ok && !m.typing(msg) {
+			return m, m.runCommand(line)
+		}
+		if cmd, ok := m.handleTabKeys(msg); ok {
+			return m, cmd
+		}
+		if cmd, ok := m.handleLayoutKeys(msg); ok {
+			return m, cmd
+		}
 		switch {
 		case key.Matches(msg, m.keyMap.Quit):
 			return m, tea.Quit
@@ -614,46 +956,171 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Suspend
 		case key.Matches(msg, m.keyMap.ToggleHelp):
 			return m, func() tea.Msg { return ToggleHelpMsg{} }
+		case key.Matches(msg, m.keyMap.Dismiss):
+			m.notifier.DismissAll()
+			return m, nil
+		case key.Matches(msg, m.keyMap.MessageLog):
+			return m, func() tea.Msg { return SwitchToMessageLogMsg{} }
+		case key.Matches(msg, m.keyMap.Back):
+			return m, func() tea.Msg { return NavigateBackMsg{} }
+		case key.Matches(msg, m.keyMap.Forward):
+			return m, func() tea.Msg { return NavigateForwardMsg{} }
+		case key.Matches(msg, m.keyMap.Palette):
+			return m, func() tea.Msg { return OpenPaletteMsg{} }
 		}
 
-	case SwitchToTableListMsg:
-		m.currentView = NewTableListModel(m.getSharedData())
+	case tea.MouseMsg:
+		return m, m.handleMouse(msg)
+
+	case RunCommandMsg:
+		return m, m.runCommand(msg.Line)
+
+	case OpenCommandLineMsg:
+		return m, m.cmdLine.open("")
+
+	case OpenPaletteMsg:
+		m.palette = m.newPalette()
 		return m, nil
 
-	case SwitchToTableListClearMsg:
+	case NotifyMsg:
+		return m, m.notifier.Add(msg.Severity, msg.Message)
+
+	case dismissNotificationMsg:
+		m.notifier.Dismiss(msg.ID)
+		return m, nil
+
+	case SwitchToMessageLogMsg:
+		if _, ok := m.currentView.(*MessageLogModel); !ok {
+			m.push()
+			m.currentView = NewMessageLogModel(m.getSharedData(), &m.notifier)
+		}
+		return m, nil
+
+	case SwitchToStatusMsg:
+		if _, ok := m.currentView.(*StatusModel); !ok {
+			m.push()
+			m.currentView = NewStatusModel(m.getSharedData(), m.dataSource, m.readOnlyDB)
+		}
+		return m, nil
+
+	case CloseMessageLogMsg:
+		return m, func() tea.Msg { return NavigateBackMsg{} }
+
+	case ClearMessageLogMsg:
+		m.notifier.ClearLog()
+		m.notifier.DismissAll()
+		return m, nil
+
+	case SwitchToTableListMsg:
 		shared := m.getSharedData()
-		// Clear any table filter
-		shared.FilteredTables = make([]string, len(shared.Tables))
-		copy(shared.FilteredTables, shared.Tables)
+		shared.Filter = nil
+		m.push()
 		m.currentView = NewTableListModel(shared)
 		return m, nil
 
+	case SwitchToTableListClearMsg:
+		m.backToTableList()
+		return m, nil
+
 	case SwitchToTableDataMsg:
 		shared := m.getSharedData()
+		entry := m.current()
+		previous, previousSort := shared.SelectedTable, shared.Sort
 		shared.SelectedTable = msg.TableIndex
+		shared.Sort = nil
 		if err := shared.LoadTableData(); err != nil {
-			m.err = err
-			return m, nil
+			// Stay on the current view; the failure is reported as a toast
+			shared.SelectedTable, shared.Sort = previous, previousSort
+			return m, NotifyError(fmt.Errorf("failed to load table: %w", err))
 		}
+		m.record(entry)
 		m.currentView = NewTableDataModel(shared)
 		return m, nil
 
+	case FollowForeignKeyMsg:
+		shared := m.getSharedData()
+		fk := shared.ForeignKeyFor(msg.ColIndex)
+		if fk == nil {
+			return m, Notify(SeverityInfo, "%s is not a foreign key", shared.ColumnInfoFor(msg.ColIndex).Name)
+		}
+		filter, err := shared.ForeignKeyTarget(msg.RowIndex, *fk)
+		if err != nil {
+			return m, NotifyError(err)
+		}
+		return m, m.navigate(fk.RefTable, filter, true)
+
+	case OpenReferencesMsg:
+		ref := msg.Reference
+		return m, m.navigate(ref.ForeignKey.Table, ref.Filter, false)
+
+	case NavigateBackMsg:
+		if !m.back() {
+			// Nothing to go back to, so go up instead
+			if view := m.fallbackView(); view != nil {
+				m.currentView = view
+			}
+		}
+		return m, nil
+
+	case NavigateForwardMsg:
+		m.forwardTo()
+		return m, nil
+
 	case SwitchToRowDetailMsg:
+		m.push()
 		m.currentView = NewRowDetailModel(m.getSharedData(), msg.RowIndex)
 		return m, nil
 
 	case SwitchToRowDetailFromQueryMsg:
+		m.push()
 		rowDetail := NewRowDetailModel(m.getSharedData(), msg.RowIndex)
 		rowDetail.FromQuery = true
 		m.currentView = rowDetail
 		return m, nil
 
 	case SwitchToEditCellMsg:
+		if m.settings.ReadOnly {
+			return m, NotifyError(errReadOnly)
+		}
+		m.push()
 		m.currentView = NewEditCellModel(m.getSharedData(), msg.RowIndex, msg.ColIndex)
 		return m, nil
 
+	case SwitchToImportMsg:
+		if m.settings.ReadOnly {
+			return m, NotifyError(errReadOnly)
+		}
+		m.push()
+		m.currentView = NewImportModel(m.getSharedData(), msg.Path, msg.Table)
+		return m, nil
+
+	case importDoneMsg:
+		if msg.Err == nil {
+			shared := m.getSharedData()
+			if err := shared.LoadTables(); err != nil {
+				return m, NotifyError(fmt.Errorf("failed to load tables: %w", err))
+			}
+			switch v := m.currentView.(type) {
+			case *TableListModel:
+				v.filterTables()
+			case *TableDataModel:
+				if v.Shared.currentTableName() == msg.Table {
+					return m, tea.Batch(append(importNotes(msg), func() tea.Msg { return RefreshDataMsg{} })...)
+				}
+			}
+		}
+		return m, tea.Batch(importNotes(msg)...)
+
+	case SwitchToExportMsg:
+		m.push()
+		m.currentView = NewExportModel(m.getSharedData(), msg.Source)
+		return m, nil
+
 	case SwitchToQueryMsg:
-		m.currentView = NewQueryModel(m.getSharedData())
+		if _, ok := m.currentView.(*QueryModel); !ok {
+			m.push()
+			m.currentView = m.queryView()
+		}
 		return m, nil
 
 	case ReturnToQueryMsg:
@@ -662,29 +1129,36 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// If we're already in query mode, just switch focus back to results
 			queryView.FocusOnInput = false
 		} else {
-			// Create new query model
-			m.currentView = NewQueryModel(m.getSharedData())
+			m.push()
+			m.currentView = m.queryView()
 		}
 		return m, nil
 
 	case RefreshDataMsg:
 		shared := m.getSharedData()
 		if err := shared.LoadTableData(); err != nil {
-			m.err = err
+			return m, NotifyError(fmt.Errorf("failed to refresh data: %w", err))
 		}
 		return m, nil
 
 	case UpdateCellMsg:
 		shared := m.getSharedData()
 		if err := shared.UpdateCell(msg.RowIndex, msg.ColIndex, msg.Value); err != nil {
-			m.err = err
+			// Stay in the editor so the value can be fixed and saved again
+			return m, func() tea.Msg {
+				return UpdateCellFailedMsg{RowIndex: msg.RowIndex, ColIndex: msg.ColIndex, Value: msg.Value, Err: err}
+			}
 		}
-		return m, func() tea.Msg { return SwitchToRowDetailMsg{msg.RowIndex} }
+		// Return to the row the edit was started from
+		return m, func() tea.Msg { return NavigateBackMsg{} }
 
 	case QueryCompletedMsg:
-		// Forward the query completion to the query model
-		if queryModel, ok := m.currentView.(*QueryModel); ok {
-			queryModel.handleQueryCompletion(msg)
+		// Forward the query completion to the query model that ran it,
+		// which may be in another tab
+		for _, t := range m.tabs {
+			if t.lastQueryView != nil && t.lastQueryView.id == msg.ID {
+				t.lastQueryView.handleQueryCompletion(msg)
+			}
 		}
 		return m, nil
 
@@ -695,8 +1169,10 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
-	if m.err != nil {
-		return m, nil
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		if cmd, ok := m.updateFocusedPane(msg); ok {
+			return m, cmd
+		}
 	}
 
 	var cmd tea.Cmd
@@ -705,10 +1181,51 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) View() string {
+	// Only reachable when the initial table load failed
 	if m.err != nil {
-		return ErrorStyle.Render(fmt.Sprintf("Error: %v\n\nPress 'ctrl+c' to quit", m.err))
+		return m.settings.Theme.Error.Render(fmt.Sprintf("Error: %v\n\nPress 'ctrl+c' to quit", m.err))
+	}
+
+	view := m.currentView.View()
+	if m.split {
+		view = m.splitView()
+	}
+	if m.palette != nil {
+		view = m.palette.view(m.width, m.height, m.settings.Theme)
 	}
-	return m.currentView.View()
+	if bar := m.tabBarView(); bar != "" {
+		view = bar + "\n" + view
+	}
+	if m.cmdLine.active {
+		view += "\n" + m.cmdLine.view(m.width, m.settings.Theme)
+	}
+	if toasts := m.notifier.View(m.width, m.settings.Theme); toasts != "" {
+		view += "\n" + toasts
+	}
+	return view
+}
+
+// navigate opens a table filtered to related rows, adding the current view
+// to the history. When showRow is set and a single row matches, its details
+// are shown instead of the table.
+func (m *Model) navigate(tableName string, filter *RowFilter, showRow bool) tea.Cmd {
+	shared := m.getSharedData()
+	entry := m.current()
+	if err := shared.OpenTable(tableName, filter); err != nil {
+		*shared = entry.shared
+		return NotifyError(fmt.Errorf("failed to open %s: %w", tableName, err))
+	}
+	m.record(entry)
+
+	if showRow && len(shared.FilteredData) == 1 {
+		m.currentView = NewRowDetailModel(shared, 0)
+		return nil
+	}
+	m.currentView = NewTableDataModel(shared)
+	if len(shared.FilteredData) == 0 {
+		return Notify(SeverityWarning, "no rows in %s where %s", tableName, filter)
+	}
+	return nil
 }
 
 func (m *Model) Err() error {
@@ -728,8 +1245,16 @@ func (m *Model) getSharedData() *SharedData {
 		return v.Shared
 	case *QueryModel:
 		return v.Shared
+	case *MessageLogModel:
+		return v.Shared
+	case *ExportModel:
+		return v.Shared
+	case *ImportModel:
+		return v.Shared
+	case *StatusModel:
+		return v.Shared
 	default:
 		// Fallback - create new shared data
-		return NewSharedData(m.db)
+		return m.newSharedData()
 	}
 }
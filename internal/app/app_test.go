@@ -0,0 +1,127 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// openTestTable loads the rows of a table of a test database
+func openTestTable(t *testing.T, setup, table string) *SharedData {
+	t.Helper()
+	s := NewSharedData(openTestDatabase(t, setup))
+	if err := s.LoadTables(); err != nil {
+		t.Fatal(err)
+	}
+	for i, name := range s.FilteredTables {
+		if name == table {
+			s.SelectedTable = i
+		}
+	}
+	if err := s.LoadTableData(); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestUpdateCellFindsRowsByStoredDates(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  string
+		column int
+	}{
+		{"no primary key", `CREATE TABLE ev(at DATETIME, note TEXT);
+			INSERT INTO ev VALUES ('2024-01-02 03:04:05.123+02:00', 'a')`, 1},
+		{"date primary key", `CREATE TABLE ev(at DATE PRIMARY KEY, note TEXT);
+			INSERT INTO ev VALUES ('2024-01-02T00:00:00Z', 'a')`, 1},
+		{"date column", `CREATE TABLE ev(at DATETIME, note TEXT);
+			INSERT INTO ev VALUES ('2024-01-02 03:04:05.123+02:00', 'a')`, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := openTestTable(t, tt.setup, "ev")
+			if err := s.UpdateCell(0, tt.column, "2025-06-07"); err != nil {
+				t.Fatal(err)
+			}
+			var count int
+			query := "SELECT count(*) FROM ev WHERE " + quoteIdent(s.Columns[tt.column]) + " = '2025-06-07'"
+			if err := s.DB.QueryRow(query).Scan(&count); err != nil || count != 1 {
+				t.Errorf("got %d updated rows, err %v", count, err)
+			}
+		})
+	}
+}
+
+func TestDateCellsShowConfiguredFormat(t *testing.T) {
+	s := openTestTable(t, `CREATE TABLE ev(at DATETIME, d DATE, note TEXT);
+		INSERT INTO ev VALUES ('2024-01-02 03:04:05.123+02:00', '2024-05-06T00:00:00Z', '2024-05-06T00:00:00Z')`, "ev")
+
+	row := s.FilteredData[0]
+	if row[0] != "2024-01-02 03:04:05.123+02:00" {
+		t.Errorf("cell holds %q, want the stored text", row[0])
+	}
+	want := []string{"2024-01-02 03:04:05", "2024-05-06", "2024-05-06T00:00:00Z"}
+	for i, w := range want {
+		if got := s.displayValue(i, row[i]); got != w {
+			t.Errorf("column %d shows %q, want %q", i, got, w)
+		}
+	}
+}
+
+func TestForeignKeyTargetOnDateKey(t *testing.T) {
+	s := openTestTable(t, `CREATE TABLE days(day DATE PRIMARY KEY, name TEXT);
+		CREATE TABLE ev(day DATE REFERENCES days(day));
+		INSERT INTO days VALUES ('2024-05-06T07:08:09Z', 'monday');
+		INSERT INTO ev VALUES ('2024-05-06T07:08:09Z')`, "ev")
+
+	filter, err := s.ForeignKeyTarget(0, s.ForeignKeys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	where, args := filter.where()
+	var name string
+	if err := s.DB.QueryRow("SELECT name FROM days WHERE "+where, args...).Scan(&name); err != nil {
+		t.Fatalf("referenced row not found: %v", err)
+	}
+}
+
+func TestUpdateCellQuotesColumns(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup string
+	}{
+		{"primary key", `CREATE TABLE t("key id" INTEGER PRIMARY KEY, "order" TEXT, "my col" TEXT);
+			INSERT INTO t VALUES (1, 'a', 'b')`},
+		{"no primary key", `CREATE TABLE t("key id" INTEGER, "order" TEXT, "my col" TEXT);
+			INSERT INTO t VALUES (1, 'a', NULL)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := openTestTable(t, tt.setup, "t")
+			if err := s.UpdateCell(0, 1, "z"); err != nil {
+				t.Fatal(err)
+			}
+			var order string
+			if err := s.DB.QueryRow(`SELECT "order" FROM t`).Scan(&order); err != nil || order != "z" {
+				t.Errorf("got %q, err %v", order, err)
+			}
+		})
+	}
+}
+
+func TestInitialModelShowsLoadError(t *testing.T) {
+	db := openTestDatabase(t, "")
+	db.Close()
+
+	m := InitialModel(db)
+	if m.err == nil {
+		t.Fatal("expected a load error")
+	}
+	m.Init()
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if view := m.View(); !strings.Contains(view, "database is closed") {
+		t.Errorf("view does not show the error: %q", view)
+	}
+}
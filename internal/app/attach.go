@@ -0,0 +1,282 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Attachment is a database attached to the main one under an alias, so
+// its tables can be used as alias.table
+type Attachment struct {
+	Alias string
+	Path  string
+}
+
+var aliasRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ParseAttachment parses a database given as path or alias=path. Without
+// an alias, the file name without its extension is used.
+func ParseAttachment(s string) Attachment {
+	if alias, path, ok := strings.Cut(s, "="); ok && aliasRe.MatchString(alias) && path != "" {
+		return Attachment{Alias: alias, Path: path}
+	}
+	return Attachment{Alias: defaultAlias(s), Path: s}
+}
+
+// defaultAlias derives an alias from the file name of a path or URI
+func defaultAlias(path string) string {
+	name := filepath.Base(DatabaseFile(path))
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	alias := []byte(name)
+	for i, c := range alias {
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			alias[i] = '_'
+		}
+	}
+	if len(alias) == 0 || alias[0] >= '0' && alias[0] <= '9' {
+		alias = append([]byte("db_"), alias...)
+	}
+	return string(alias)
+}
+
+// sqliteConn is what the driver's connections implement; attachedConn
+// passes all of it through
+type sqliteConn interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+	driver.SessionResetter
+	driver.Validator
+}
+
+// connector opens the connections of a database pool. ATTACH only applies
+// to the connection it runs on, so every new connection attaches the
+// current attachments, and connections opened before the attachments last
+// changed are discarded by the pool.
+type connector struct {
+	dsn      string
+	readOnly bool
+	driver   driver.Driver
+
+	// keep holds an in-memory database open while the pool has no
+	// connection, e.g. between attachment generations
+	keep driver.Conn
+
+	mu          sync.Mutex
+	attachments []Attachment
+	generation  int
+}
+
+// attachedConn is a connection with the attachments of one generation
+type attachedConn struct {
+	sqliteConn
+	connector  *connector
+	generation int
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	c.mu.Lock()
+	attachments, generation := append([]Attachment(nil), c.attachments...), c.generation
+	c.mu.Unlock()
+
+	conn, err := c.driver.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	sc, ok := conn.(sqliteConn)
+	if !ok {
+		conn.Close()
+		return nil, fmt.Errorf("unsupported driver connection %T", conn)
+	}
+	for _, a := range attachments {
+		source, err := DataSource(a.Path, ConnectionOptions{ReadOnly: c.readOnly})
+		if err == nil {
+			_, err = sc.ExecContext(ctx, "ATTACH DATABASE ? AS "+quoteIdent(a.Alias), []driver.NamedValue{{Ordinal: 1, Value: source}})
+		}
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to attach %s as %s: %w", a.Path, a.Alias, err)
+		}
+	}
+	return &attachedConn{sqliteConn: sc, connector: c, generation: generation}, nil
+}
+
+// Driver returns the connector itself, which lets AttachDatabase find it
+// from a *sql.DB
+func (c *connector) Driver() driver.Driver {
+	return c
+}
+
+// Open opens a connection; name is ignored as the connector has its own
+func (c *connector) Open(string) (driver.Conn, error) {
+	return c.Connect(context.Background())
+}
+
+// Close closes the connection that keeps an in-memory database; the pool
+// calls it when it is closed
+func (c *connector) Close() error {
+	if c.keep == nil {
+		return nil
+	}
+	return c.keep.Close()
+}
+
+// current reports whether a connection has the current attachments
+func (c *connector) current(generation int) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return generation == c.generation
+}
+
+// ResetSession makes the pool discard a connection with old attachments
+// before reusing it
+func (c *attachedConn) ResetSession(ctx context.Context) error {
+	if !c.connector.current(c.generation) {
+		return driver.ErrBadConn
+	}
+	return c.sqliteConn.ResetSession(ctx)
+}
+
+// IsValid keeps a connection with old attachments out of the pool
+func (c *attachedConn) IsValid() bool {
+	return c.connector.current(c.generation) && c.sqliteConn.IsValid()
+}
+
+// openPool opens a pool on dsn with the databases attached. Connections
+// come from the driver registered as "sqlite", so functions registered
+// with it are available. The database of a memory pool lives as long as
+// the pool.
+func openPool(dsn string, readOnly bool, attachments []Attachment, memory bool) (*sql.DB, error) {
+	registered, err := sql.Open("sqlite", "")
+	if err != nil {
+		return nil, err
+	}
+	defer registered.Close()
+	c := &connector{dsn: dsn, readOnly: readOnly, driver: registered.Driver(),
+		attachments: append([]Attachment(nil), attachments...)}
+	if memory {
+		if c.keep, err = c.driver.Open(dsn); err != nil {
+			return nil, err
+		}
+	}
+	return sql.OpenDB(c), nil
+}
+
+// connectorFor returns the connector of a pool opened by OpenDatabase
+func connectorFor(db *sql.DB) (*connector, error) {
+	c, ok := db.Driver().(*connector)
+	if !ok {
+		return nil, fmt.Errorf("databases can only be attached to a database opened by teaqlite")
+	}
+	return c, nil
+}
+
+// Attachments returns the databases attached to a pool opened by
+// OpenDatabase
+func Attachments(db *sql.DB) []Attachment {
+	c, err := connectorFor(db)
+	if err != nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Attachment(nil), c.attachments...)
+}
+
+// checkAttachment validates the alias and the file of a new attachment
+func checkAttachment(a Attachment, attached []Attachment) error {
+	if !aliasRe.MatchString(a.Alias) {
+		return fmt.Errorf("invalid alias %q: use letters, digits and underscores", a.Alias)
+	}
+	if strings.EqualFold(a.Alias, "main") || strings.EqualFold(a.Alias, "temp") {
+		return fmt.Errorf("alias %s is reserved", a.Alias)
+	}
+	for _, b := range attached {
+		if strings.EqualFold(a.Alias, b.Alias) {
+			return fmt.Errorf("alias %s is already in use", a.Alias)
+		}
+	}
+	// ATTACH creates missing files, which is never what a typo wants
+	if file := DatabaseFile(a.Path); file != "" {
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Errorf("database file '%s' does not exist", file)
+		}
+	}
+	return nil
+}
+
+// AttachDatabase attaches a database to every connection of a pool opened
+// by OpenDatabase
+func AttachDatabase(db *sql.DB, a Attachment) error {
+	c, err := connectorFor(db)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	if err := checkAttachment(a, c.attachments); err != nil {
+		c.mu.Unlock()
+		return err
+	}
+	c.attachments = append(c.attachments, a)
+	c.generation++
+	c.mu.Unlock()
+
+	// Connect now, so that a file SQLite cannot open is reported here
+	// rather than by the next query
+	if err := db.Ping(); err != nil {
+		c.mu.Lock()
+		c.attachments = c.attachments[:len(c.attachments)-1]
+		c.generation++
+		c.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// DetachDatabase detaches a database attached with AttachDatabase
+func DetachDatabase(db *sql.DB, alias string) error {
+	c, err := connectorFor(db)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, a := range c.attachments {
+		if strings.EqualFold(a.Alias, alias) {
+			c.attachments = append(c.attachments[:i:i], c.attachments[i+1:]...)
+			c.generation++
+			return nil
+		}
+	}
+	return fmt.Errorf("no database attached as %s", alias)
+}
+
+// loadSchemas returns the names of the main and attached databases, in
+// the order they were attached
+func loadSchemas(db *sql.DB) ([]string, error) {
+	rows, err := db.Query(`SELECT name FROM pragma_database_list WHERE name != 'temp' ORDER BY seq`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schemas []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, name)
+	}
+	return schemas, rows.Err()
+}
@@ -0,0 +1,132 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseAttachment(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Attachment
+	}{
+		{"other=data/other.db", Attachment{Alias: "other", Path: "data/other.db"}},
+		{"data/sales-2024.db", Attachment{Alias: "sales_2024", Path: "data/sales-2024.db"}},
+		{"2024.db", Attachment{Alias: "db_2024", Path: "2024.db"}},
+		{"file:/tmp/x.db?mode=ro", Attachment{Alias: "x", Path: "file:/tmp/x.db?mode=ro"}},
+		{"a b=c.db", Attachment{Alias: "a_b_c", Path: "a b=c.db"}},
+	}
+	for _, tt := range tests {
+		if got := ParseAttachment(tt.input); got != tt.want {
+			t.Errorf("ParseAttachment(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+// attachedFile creates a database file with a table t holding one row
+func attachedFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "other.db")
+	db, err := OpenDatabase(path, DefaultConnectionOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec("CREATE TABLE t(v); INSERT INTO t VALUES ('x')"); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestAttachDatabaseReachesEveryConnection(t *testing.T) {
+	db := openTestDatabase(t, "")
+	ctx := context.Background()
+
+	// A connection opened before attaching is replaced once returned
+	before, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := AttachDatabase(db, Attachment{Alias: "other", Path: attachedFile(t)}); err != nil {
+		t.Fatal(err)
+	}
+	before.Close()
+
+	var conns []*sql.Conn
+	for range 3 {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conns = append(conns, conn)
+		var v string
+		if err := conn.QueryRowContext(ctx, "SELECT v FROM other.t").Scan(&v); err != nil || v != "x" {
+			t.Errorf("got %q, err %v", v, err)
+		}
+	}
+	for _, c := range conns {
+		c.Close()
+	}
+
+	if got := Attachments(db); len(got) != 1 || got[0].Alias != "other" {
+		t.Errorf("Attachments = %+v", got)
+	}
+	if err := DetachDatabase(db, "OTHER"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("SELECT v FROM other.t"); err == nil {
+		t.Error("detached database is still attached")
+	}
+	if err := DetachDatabase(db, "other"); err == nil {
+		t.Error("expected an error detaching twice")
+	}
+}
+
+func TestAttachDatabaseRejects(t *testing.T) {
+	path := attachedFile(t)
+	tests := []struct {
+		name string
+		a    Attachment
+	}{
+		{"alias in use", Attachment{Alias: "Other", Path: path}},
+		{"reserved alias", Attachment{Alias: "temp", Path: path}},
+		{"invalid alias", Attachment{Alias: "a-b", Path: path}},
+		{"missing file", Attachment{Alias: "missing", Path: filepath.Join(t.TempDir(), "missing.db")}},
+		{"not a database", Attachment{Alias: "bad", Path: "file:" + path + "?mode=bogus"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDatabase(t, "")
+			if err := AttachDatabase(db, Attachment{Alias: "other", Path: path}); err != nil {
+				t.Fatal(err)
+			}
+			if err := AttachDatabase(db, tt.a); err == nil {
+				t.Error("expected an error")
+			}
+			want := []Attachment{{Alias: "other", Path: path}}
+			if got := Attachments(db); !reflect.DeepEqual(got, want) {
+				t.Errorf("Attachments = %+v, want %+v", got, want)
+			}
+			if _, err := db.Exec("SELECT v FROM other.t"); err != nil {
+				t.Errorf("pool lost its attachment: %v", err)
+			}
+		})
+	}
+}
+
+func TestOpenDatabaseWithAttachments(t *testing.T) {
+	opts := DefaultConnectionOptions()
+	opts.Attachments = []Attachment{{Alias: "other", Path: attachedFile(t)}}
+	db, err := OpenDatabase(filepath.Join(t.TempDir(), "main.db"), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	schemas, err := loadSchemas(db)
+	if err != nil || !reflect.DeepEqual(schemas, []string{"main", "other"}) {
+		t.Errorf("schemas %v, err %v", schemas, err)
+	}
+}
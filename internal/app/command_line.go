@@ -0,0 +1,319 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const maxCommandHistory = 100
+
+// commandLine is the ":" prompt shown at the bottom of the screen
+type commandLine struct {
+	input   textinput.Model
+	active  bool
+	keyMap  CommandLineKeyMap
+	history []string
+	histIdx int
+	// Completion state, kept while tab cycles through candidates
+	candidates []string
+	candIdx    int
+	candBase   string // line without the word being completed
+}
+
+func newCommandLine() commandLine {
+	input := textinput.New()
+	input.Prompt = ":"
+	input.Placeholder = "command"
+	input.CharLimit = 0
+	return commandLine{input: input, keyMap: DefaultCommandLineKeyMap()}
+}
+
+// open shows the prompt with the given text
+func (c *commandLine) open(text string) tea.Cmd {
+	c.active = true
+	c.histIdx = len(c.history)
+	c.resetCompletion()
+	c.input.SetValue(text)
+	c.input.CursorEnd()
+	return c.input.Focus()
+}
+
+func (c *commandLine) close() {
+	c.active = false
+	c.resetCompletion()
+	c.input.Blur()
+	c.input.Reset()
+}
+
+// remember adds a line to the history
+func (c *commandLine) remember(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" || (len(c.history) > 0 && c.history[len(c.history)-1] == line) {
+		return
+	}
+	c.history = append(c.history, line)
+	if len(c.history) > maxCommandHistory {
+		c.history = c.history[len(c.history)-maxCommandHistory:]
+	}
+}
+
+func (c *commandLine) resetCompletion() {
+	c.candidates = nil
+	c.candIdx = 0
+	c.candBase = ""
+}
+
+// handleCommandLine updates the command line with a key press
+func (m *Model) handleCommandLine(msg tea.KeyMsg) tea.Cmd {
+	c := &m.cmdLine
+	switch {
+	case key.Matches(msg, c.keyMap.Execute):
+		line := c.input.Value()
+		c.close()
+		c.remember(line)
+		return m.runCommand(line)
+
+	case key.Matches(msg, c.keyMap.Cancel):
+		c.close()
+		return nil
+
+	case key.Matches(msg, c.keyMap.Complete), key.Matches(msg, c.keyMap.PrevCompletion):
+		if c.candidates == nil {
+			line := c.input.Value()
+			prefix, candidates := m.completeCommandLine(line)
+			if len(candidates) == 0 {
+				return nil
+			}
+			c.candidates = candidates
+			c.candBase = line[:len(line)-len(prefix)]
+			c.candIdx = 0
+			if key.Matches(msg, c.keyMap.PrevCompletion) {
+				c.candIdx = len(candidates) - 1
+			}
+		} else if key.Matches(msg, c.keyMap.PrevCompletion) {
+			c.candIdx = (c.candIdx - 1 + len(c.candidates)) % len(c.candidates)
+		} else {
+			c.candIdx = (c.candIdx + 1) % len(c.candidates)
+		}
+		text := c.candBase + c.candidates[c.candIdx]
+		if len(c.candidates) == 1 {
+			// A unique match is accepted right away
+			text += " "
+			c.resetCompletion()
+		}
+		c.input.SetValue(text)
+		c.input.CursorEnd()
+		return nil
+
+	case key.Matches(msg, c.keyMap.HistoryPrev):
+		if c.histIdx > 0 {
+			c.histIdx--
+			c.input.SetValue(c.history[c.histIdx])
+			c.input.CursorEnd()
+		}
+		return nil
+
+	case key.Matches(msg, c.keyMap.HistoryNext):
+		if c.histIdx < len(c.history)-1 {
+			c.histIdx++
+			c.input.SetValue(c.history[c.histIdx])
+		} else {
+			c.histIdx = len(c.history)
+			c.input.SetValue("")
+		}
+		c.input.CursorEnd()
+		return nil
+	}
+
+	c.resetCompletion()
+	var cmd tea.Cmd
+	c.input, cmd = c.input.Update(msg)
+	return cmd
+}
+
+// view renders the candidates, if any, and the prompt
+func (c *commandLine) view(width int, theme *Theme) string {
+	var b strings.Builder
+	if len(c.candidates) > 1 {
+		var parts []string
+		for i, cand := range c.candidates {
+			if i == c.candIdx {
+				parts = append(parts, theme.Selected.Render(cand))
+			} else {
+				parts = append(parts, cand)
+			}
+		}
+		b.WriteString(lipgloss.NewStyle().MaxWidth(Max(10, width)).Render(strings.Join(parts, " ")))
+		b.WriteString("\n")
+	}
+	b.WriteString(c.input.View())
+	return b.String()
+}
+
+// palette is the fuzzy finder over all commands
+type palette struct {
+	input    textinput.Model
+	keyMap   PaletteKeyMap
+	items    []paletteItem
+	matches  []paletteItem
+	selected int
+}
+
+// paletteItem is a command line offered by the palette
+type paletteItem struct {
+	line        string
+	description string
+	needsArgs   bool // the line is opened in the command line to be completed
+}
+
+// newPalette lists every command, with one entry per completion of the
+// first argument so tables and saved queries can be picked directly
+func (m *Model) newPalette() *palette {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "Type a command..."
+	input.Focus()
+
+	p := &palette{input: input, keyMap: m.settings.KeyMaps.Palette}
+	for _, c := range m.commands {
+		name := c.Name
+		if c.Usage != "" {
+			name += " " + c.Usage
+		}
+		p.items = append(p.items, paletteItem{line: c.Name, description: fmt.Sprintf("%s — %s", name, c.Description), needsArgs: c.MinArgs > 0})
+		if c.Complete == nil || c.MinArgs > 1 {
+			continue
+		}
+		for _, arg := range c.Complete(m, 0) {
+			p.items = append(p.items, paletteItem{line: c.Name + " " + arg, description: c.Description})
+		}
+	}
+	p.filter()
+	return p
+}
+
+// filter keeps the items matching the input, best matches first
+func (p *palette) filter() {
+	pattern := strings.ToLower(p.input.Value())
+	p.matches = nil
+	type scored struct {
+		item  paletteItem
+		score int
+	}
+	var found []scored
+	for _, item := range p.items {
+		if score := subsequenceScore(strings.ToLower(item.line), pattern); score > 0 {
+			found = append(found, scored{item, score})
+		}
+	}
+	// Stable insertion sort keeps the registry order for equal scores
+	for i := 1; i < len(found); i++ {
+		for j := i; j > 0 && found[j].score > found[j-1].score; j-- {
+			found[j], found[j-1] = found[j-1], found[j]
+		}
+	}
+	for _, f := range found {
+		p.matches = append(p.matches, f.item)
+	}
+	p.selected = Min(p.selected, Max(0, len(p.matches)-1))
+}
+
+// subsequenceScore scores how well pattern matches text: prefixes beat
+// substrings, which beat scattered characters. 0 means no match.
+func subsequenceScore(text, pattern string) int {
+	switch {
+	case pattern == "":
+		return 1
+	case strings.HasPrefix(text, pattern):
+		return 300
+	case strings.Contains(text, pattern):
+		return 200
+	}
+	rest := []rune(pattern)
+	for _, r := range text {
+		if len(rest) > 0 && r == rest[0] {
+			rest = rest[1:]
+		}
+	}
+	if len(rest) > 0 {
+		return 0
+	}
+	return 100 - Min(99, utf8.RuneCountInString(text))
+}
+
+// handlePalette updates the palette with a key press
+func (m *Model) handlePalette(msg tea.KeyMsg) tea.Cmd {
+	p := m.palette
+	switch {
+	case key.Matches(msg, p.keyMap.Cancel):
+		m.palette = nil
+		return nil
+
+	case key.Matches(msg, p.keyMap.Select):
+		m.palette = nil
+		if len(p.matches) == 0 {
+			return nil
+		}
+		item := p.matches[p.selected]
+		if item.needsArgs {
+			return m.cmdLine.open(item.line + " ")
+		}
+		return m.runCommand(item.line)
+
+	case key.Matches(msg, p.keyMap.Up):
+		if p.selected > 0 {
+			p.selected--
+		}
+		return nil
+
+	case key.Matches(msg, p.keyMap.Down):
+		if p.selected < len(p.matches)-1 {
+			p.selected++
+		}
+		return nil
+	}
+
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	p.filter()
+	return cmd
+}
+
+// view renders the palette as a list below its input
+func (p *palette) view(width, height int, theme *Theme) string {
+	var b strings.Builder
+	b.WriteString(theme.Title.Render("Commands"))
+	b.WriteString("\n\n")
+	b.WriteString(p.input.View())
+	b.WriteString("\n\n")
+
+	visible := Max(1, height-6)
+	start := 0
+	if p.selected >= visible {
+		start = p.selected - visible + 1
+	}
+	end := Min(len(p.matches), start+visible)
+	if len(p.matches) == 0 {
+		b.WriteString(theme.Help.Render("No matching commands"))
+		b.WriteString("\n")
+	}
+	for i := start; i < end; i++ {
+		item := p.matches[i]
+		line := fmt.Sprintf("%-24s", TruncateString(item.line, 24))
+		description := TruncateString(item.description, Max(10, width-30))
+		if i == p.selected {
+			b.WriteString(theme.Selected.Render("> " + line))
+		} else {
+			b.WriteString(theme.Normal.Render("  " + line))
+		}
+		b.WriteString(" " + theme.Help.Render(description))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
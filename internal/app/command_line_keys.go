@@ -0,0 +1,96 @@
+package app
+
+import "github.com/charmbracelet/bubbles/key"
+
+// CommandLineKeyMap defines keybindings for the ":" command line
+type CommandLineKeyMap struct {
+	Execute        key.Binding
+	Cancel         key.Binding
+	Complete       key.Binding
+	PrevCompletion key.Binding
+	HistoryPrev    key.Binding
+	HistoryNext    key.Binding
+}
+
+// DefaultCommandLineKeyMap returns the default keybindings for the command line
+func DefaultCommandLineKeyMap() CommandLineKeyMap {
+	return CommandLineKeyMap{
+		Execute: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "run command"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc", "ctrl+c"),
+			key.WithHelp("esc", "cancel"),
+		),
+		Complete: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "complete"),
+		),
+		PrevCompletion: key.NewBinding(
+			key.WithKeys("shift+tab"),
+			key.WithHelp("shift+tab", "previous completion"),
+		),
+		HistoryPrev: key.NewBinding(
+			key.WithKeys("up", "ctrl+p"),
+			key.WithHelp("↑", "previous command"),
+		),
+		HistoryNext: key.NewBinding(
+			key.WithKeys("down", "ctrl+n"),
+			key.WithHelp("↓", "next command"),
+		),
+	}
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view
+func (k CommandLineKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Execute, k.Complete, k.HistoryPrev, k.Cancel}
+}
+
+// FullHelp returns keybindings for the expanded help view
+func (k CommandLineKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Execute, k.Cancel},
+		{k.Complete, k.PrevCompletion, k.HistoryPrev, k.HistoryNext},
+	}
+}
+
+// PaletteKeyMap defines keybindings for the command palette
+type PaletteKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Select key.Binding
+	Cancel key.Binding
+}
+
+// DefaultPaletteKeyMap returns the default keybindings for the command palette
+func DefaultPaletteKeyMap() PaletteKeyMap {
+	return PaletteKeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "ctrl+p", "ctrl+k"),
+			key.WithHelp("↑/ctrl+p", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "ctrl+n", "ctrl+j"),
+			key.WithHelp("↓/ctrl+n", "down"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "run"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc", "ctrl+c"),
+			key.WithHelp("esc", "close"),
+		),
+	}
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view
+func (k PaletteKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Select, k.Cancel}
+}
+
+// FullHelp returns keybindings for the expanded help view
+func (k PaletteKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.Up, k.Down, k.Select, k.Cancel}}
+}
@@ -0,0 +1,24 @@
+package app
+
+import "testing"
+
+func TestSubsequenceScore(t *testing.T) {
+	tests := []struct {
+		text, pattern string
+		want          int
+	}{
+		{"export", "", 1},
+		{"export", "exp", 300},
+		{"export", "port", 200},
+		{"export", "ept", 94},
+		{"export", "xe", 0},
+		{"größe", "gße", 95},
+		{"größe", "gÃ", 0},
+		{"résumé", "rsm", 94},
+	}
+	for _, tt := range tests {
+		if got := subsequenceScore(tt.text, tt.pattern); got != tt.want {
+			t.Errorf("subsequenceScore(%q, %q) = %d, want %d", tt.text, tt.pattern, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,568 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Command messages
+type (
+	// RunCommandMsg runs a command line such as "open users"
+	RunCommandMsg struct{ Line string }
+	// OpenCommandLineMsg shows the ":" command line
+	OpenCommandLineMsg struct{}
+	// OpenPaletteMsg shows the command palette
+	OpenPaletteMsg struct{}
+)
+
+// Command is an action that can be run from the command line, the palette
+// or a key binding
+type Command struct {
+	Name        string
+	Aliases     []string
+	Usage       string // argument synopsis, e.g. "<table>"
+	Description string
+	// MinArgs is the number of required arguments
+	MinArgs int
+	// Raw commands receive everything after the name as a single argument
+	Raw bool
+	// Complete returns the candidates for argument i, if any
+	Complete func(m *Model, i int) []string
+	Run      func(m *Model, args []string) tea.Cmd
+}
+
+// WithCommands adds commands to the built-in ones
+func WithCommands(cmds ...Command) Option {
+	return func(m *Model) {
+		m.commands = append(m.commands, cmds...)
+	}
+}
+
+// findCommand looks a command up by name or alias
+func (m *Model) findCommand(name string) (Command, bool) {
+	for _, c := range m.commands {
+		if c.Name == name || slices.Contains(c.Aliases, name) {
+			return c, true
+		}
+	}
+	return Command{}, false
+}
+
+// parseCommandLine splits a command line into the command name and its
+// arguments
+func (m *Model) parseCommandLine(line string) (string, []string) {
+	line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), ":"))
+	name, rest, _ := strings.Cut(line, " ")
+	rest = strings.TrimSpace(rest)
+	if c, ok := m.findCommand(name); ok && c.Raw {
+		if rest == "" {
+			return name, nil
+		}
+		return name, []string{rest}
+	}
+	return name, strings.Fields(rest)
+}
+
+// runCommand parses and runs a command line
+func (m *Model) runCommand(line string) tea.Cmd {
+	name, args := m.parseCommandLine(line)
+	if name == "" {
+		return nil
+	}
+	c, ok := m.findCommand(name)
+	if !ok {
+		return Notify(SeverityError, "unknown command: %s", name)
+	}
+	if len(args) < c.MinArgs {
+		return Notify(SeverityError, "usage: %s %s", c.Name, c.Usage)
+	}
+	return c.Run(m, args)
+}
+
+// typing reports whether msg is text typed into an input of the current
+// view, which key bindings must not capture
+func (m *Model) typing(msg tea.KeyMsg) bool {
+	if msg.Type != tea.KeyRunes && msg.Type != tea.KeySpace {
+		return false
+	}
+	switch v := m.currentView.(type) {
+	case *EditCellModel:
+		return true
+	case *QueryModel:
+		return v.FocusOnInput
+	case *TableListModel:
+		return v.searching
+	case *TableDataModel:
+		return v.searching
+	case *ExportModel:
+		return v.editingText()
+	case *ImportModel:
+		return v.editingText()
+	}
+	return false
+}
+
+// completeCommandLine returns the word being completed and its candidates
+func (m *Model) completeCommandLine(line string) (string, []string) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || (len(fields) == 1 && !strings.HasSuffix(line, " ")) {
+		prefix := ""
+		if len(fields) == 1 {
+			prefix = fields[0]
+		}
+		var names []string
+		for _, c := range m.commands {
+			if strings.HasPrefix(c.Name, prefix) {
+				names = append(names, c.Name)
+			}
+		}
+		return prefix, names
+	}
+
+	c, ok := m.findCommand(fields[0])
+	if !ok || c.Complete == nil {
+		return "", nil
+	}
+	arg := len(fields) - 1
+	prefix := ""
+	if !strings.HasSuffix(line, " ") {
+		arg--
+		prefix = fields[len(fields)-1]
+	}
+	var matches []string
+	for _, cand := range c.Complete(m, arg) {
+		if strings.HasPrefix(strings.ToLower(cand), strings.ToLower(prefix)) {
+			matches = append(matches, cand)
+		}
+	}
+	return prefix, matches
+}
+
+// tableNames completes table names
+func tableNames(m *Model, i int) []string {
+	if i != 0 {
+		return nil
+	}
+	return m.getSharedData().Tables
+}
+
+// savedQueryNames completes saved query names
+func savedQueryNames(m *Model, i int) []string {
+	if i != 0 {
+		return nil
+	}
+	return m.savedQueries.Names()
+}
+
+// choices completes the first argument from a fixed list
+func choices(values ...string) func(*Model, int) []string {
+	return func(_ *Model, i int) []string {
+		if i != 0 {
+			return nil
+		}
+		return values
+	}
+}
+
+// currentQueryText returns the text of the open or last used query view
+func (m *Model) currentQueryText() string {
+	if q, ok := m.currentView.(*QueryModel); ok {
+		return q.queryInput.Value()
+	}
+	if m.lastQueryView != nil {
+		return m.lastQueryView.queryInput.Value()
+	}
+	return ""
+}
+
+// runQuery opens SQL mode with the given query and executes it
+func (m *Model) runQuery(query string) tea.Cmd {
+	if _, ok := m.currentView.(*QueryModel); !ok {
+		m.push()
+	}
+	q := m.queryView()
+	q.queryInput.SetValue(query)
+	m.currentView = q
+	return q.executeQuery()
+}
+
+// createTable opens SQL mode with a CREATE TABLE statement to fill in,
+// the cursor on the first column after the primary key
+func (m *Model) createTable(args []string) tea.Cmd {
+	name := "new_table"
+	if len(args) > 0 {
+		name = quoteIdent(args[0])
+	}
+	if _, ok := m.currentView.(*QueryModel); !ok {
+		m.push()
+	}
+	q := m.queryView()
+	q.queryInput.SetValue(fmt.Sprintf("CREATE TABLE %s (\n    id INTEGER PRIMARY KEY,\n    \n);", name))
+	// Size the editor for the statement first, so it is not scrolled
+	q.layoutEditor()
+	q.queryInput.MoveTo(2, 4)
+	q.FocusOnInput = true
+	q.queryInput.Focus()
+	m.currentView = q
+	return nil
+}
+
+// toggle turns a setting on or off, or flips it without an argument. The
+// tab bar shows a badge for the mode, so the view is resized.
+func (m *Model) toggle(setting *bool, name, command string, args []string) tea.Cmd {
+	switch {
+	case len(args) == 0:
+		*setting = !*setting
+	case args[0] == "on":
+		*setting = true
+	case args[0] == "off":
+		*setting = false
+	default:
+		return Notify(SeverityError, "usage: %s [on|off]", command)
+	}
+	m.resize()
+	if *setting {
+		return Notify(SeverityInfo, "%s on", name)
+	}
+	return Notify(SeverityInfo, "%s off", name)
+}
+
+// refresh reloads the data of the current view
+func (m *Model) refresh() tea.Cmd {
+	switch v := m.currentView.(type) {
+	case *TableDataModel:
+		return v.loadPage(v.Shared.CurrentPage)
+	case *TableListModel:
+		if err := v.Shared.LoadTables(); err != nil {
+			v.err = err
+			return NotifyError(fmt.Errorf("failed to load tables: %w", err))
+		}
+		v.err = nil
+		v.filterTables()
+	}
+	return nil
+}
+
+// attach attaches a database given as path [alias] or alias=path, or
+// lists the attached databases without arguments
+func (m *Model) attach(args []string) tea.Cmd {
+	if len(args) == 0 {
+		attached := Attachments(m.db)
+		if len(attached) == 0 {
+			return Notify(SeverityInfo, "no databases attached")
+		}
+		names := make([]string, len(attached))
+		for i, a := range attached {
+			names[i] = fmt.Sprintf("%s (%s)", a.Alias, a.Path)
+		}
+		return Notify(SeverityInfo, "attached: %s", strings.Join(names, ", "))
+	}
+	a := ParseAttachment(args[0])
+	if len(args) > 1 {
+		a = Attachment{Alias: args[1], Path: args[0]}
+	}
+	if err := AttachDatabase(m.db, a); err != nil {
+		return NotifyError(fmt.Errorf("failed to attach %s: %w", a.Path, err))
+	}
+	if err := m.reloadTables(); err != nil {
+		return NotifyError(fmt.Errorf("failed to load tables: %w", err))
+	}
+	return Notify(SeverityInfo, "attached %s as %s", a.Path, a.Alias)
+}
+
+// detach detaches a database attached with attach
+func (m *Model) detach(alias string) tea.Cmd {
+	if err := DetachDatabase(m.db, alias); err != nil {
+		return NotifyError(err)
+	}
+	if err := m.reloadTables(); err != nil {
+		return NotifyError(fmt.Errorf("failed to load tables: %w", err))
+	}
+	return Notify(SeverityInfo, "detached %s", alias)
+}
+
+// attachedAliases completes the aliases of the attached databases
+func attachedAliases(m *Model, i int) []string {
+	if i != 0 {
+		return nil
+	}
+	var aliases []string
+	for _, a := range Attachments(m.db) {
+		aliases = append(aliases, a.Alias)
+	}
+	return aliases
+}
+
+// reloadTables lists the tables again after databases were attached or
+// detached, keeping the open table selected
+func (m *Model) reloadTables() error {
+	shared := m.getSharedData()
+	name := ""
+	if !shared.IsQueryResult && shared.SelectedTable < len(shared.FilteredTables) {
+		name = shared.FilteredTables[shared.SelectedTable]
+	}
+	if err := shared.LoadTables(); err != nil {
+		return err
+	}
+	if v, ok := m.currentView.(*TableListModel); ok {
+		v.filterTables()
+	} else if i := slices.Index(shared.FilteredTables, name); i >= 0 {
+		shared.SelectedTable = i
+	}
+	if m.sidebar != nil {
+		if err := m.sidebar.Shared.LoadTables(); err != nil {
+			return err
+		}
+		m.sidebar.filterTables()
+	}
+	return nil
+}
+
+// defaultCommands returns the built-in commands, in the order shown by the
+// palette
+func defaultCommands() []Command {
+	return []Command{
+		{
+			Name: "open", Aliases: []string{"o", "table"}, Usage: "<table>",
+			Description: "Open a table", MinArgs: 1, Complete: tableNames,
+			Run: func(m *Model, args []string) tea.Cmd {
+				shared := m.getSharedData()
+				entry := m.current()
+				if err := shared.OpenTable(args[0], nil); err != nil {
+					*shared = entry.shared
+					return NotifyError(err)
+				}
+				m.record(entry)
+				m.currentView = NewTableDataModel(shared)
+				return nil
+			},
+		},
+		{
+			Name: "tables", Description: "Go to the table list",
+			Run: func(m *Model, _ []string) tea.Cmd {
+				return func() tea.Msg { return SwitchToTableListClearMsg{} }
+			},
+		},
+		{
+			Name: "query", Aliases: []string{"sql"}, Usage: "[sql]", Raw: true,
+			Description: "Open SQL mode, running the query if one is given",
+			Run: func(m *Model, args []string) tea.Cmd {
+				if len(args) == 0 {
+					return func() tea.Msg { return SwitchToQueryMsg{} }
+				}
+				return m.runQuery(args[0])
+			},
+		},
+		{
+			Name: "run", Usage: "<name>", Description: "Run a saved query",
+			MinArgs: 1, Complete: savedQueryNames,
+			Run: func(m *Model, args []string) tea.Cmd {
+				query, ok := m.savedQueries.Get(args[0])
+				if !ok {
+					return Notify(SeverityError, "no saved query named %s", args[0])
+				}
+				return m.runQuery(query)
+			},
+		},
+		{
+			Name: "save", Usage: "<name>", Description: "Save the current query",
+			MinArgs: 1, Complete: savedQueryNames,
+			Run: func(m *Model, args []string) tea.Cmd {
+				query := strings.TrimSpace(m.currentQueryText())
+				if query == "" {
+					return Notify(SeverityWarning, "no query to save")
+				}
+				if err := m.savedQueries.Set(args[0], query); err != nil {
+					return NotifyError(fmt.Errorf("failed to save query: %w", err))
+				}
+				return Notify(SeverityInfo, "saved query %s", args[0])
+			},
+		},
+		{
+			Name: "forget", Usage: "<name>", Description: "Delete a saved query",
+			MinArgs: 1, Complete: savedQueryNames,
+			Run: func(m *Model, args []string) tea.Cmd {
+				if _, ok := m.savedQueries.Get(args[0]); !ok {
+					return Notify(SeverityError, "no saved query named %s", args[0])
+				}
+				if err := m.savedQueries.Delete(args[0]); err != nil {
+					return NotifyError(fmt.Errorf("failed to delete query: %w", err))
+				}
+				return Notify(SeverityInfo, "deleted query %s", args[0])
+			},
+		},
+		{
+			Name: "yank", Aliases: []string{"y", "copy"}, Usage: "[cell|row|rows|query|sql] [tsv|json|insert]",
+			Description: "Copy to the clipboard", Complete: completeYank,
+			Run: func(m *Model, args []string) tea.Cmd { return m.yank(args) },
+		},
+		{
+			Name: "export", Usage: "[file]", Description: "Export rows to a file; the extension picks the format",
+			Run: func(m *Model, args []string) tea.Cmd { return m.export(args) },
+		},
+		{
+			Name: "import", Usage: "[file]", Description: "Import rows from a CSV, TSV, JSON or NDJSON file",
+			Run: func(m *Model, args []string) tea.Cmd { return m.importFile(args) },
+		},
+		{
+			Name: "create", Usage: "[table]", Description: "Open SQL mode with a CREATE TABLE statement",
+			Run: func(m *Model, args []string) tea.Cmd { return m.createTable(args) },
+		},
+		{
+			Name: "attach", Usage: "[<file> [alias]]", Description: "Attach a database, or list the attached ones",
+			Run: func(m *Model, args []string) tea.Cmd { return m.attach(args) },
+		},
+		{
+			Name: "detach", Usage: "<alias>", Description: "Detach an attached database",
+			MinArgs: 1, Complete: attachedAliases,
+			Run: func(m *Model, args []string) tea.Cmd { return m.detach(args[0]) },
+		},
+		{
+			Name: "refresh", Description: "Reload the current view",
+			Run: func(m *Model, _ []string) tea.Cmd { return m.refresh() },
+		},
+		{
+			Name: "pagesize", Usage: "<rows>", Description: "Set the number of rows per page",
+			MinArgs: 1, Complete: choices("10", "20", "50", "100", "500"),
+			Run: func(m *Model, args []string) tea.Cmd {
+				n, err := strconv.Atoi(args[0])
+				if err != nil || n < 1 {
+					return Notify(SeverityError, "page size must be a positive number")
+				}
+				m.settings.PageSize = n
+				if v, ok := m.currentView.(*TableDataModel); ok {
+					v.selectedRow = 0
+					return v.loadPage(0)
+				}
+				return nil
+			},
+		},
+		{
+			Name: "readonly", Usage: "[on|off]", Description: "Toggle read-only mode",
+			Complete: choices("on", "off"),
+			Run: func(m *Model, args []string) tea.Cmd {
+				if m.readOnlyDB {
+					return Notify(SeverityWarning, "the database was opened read-only")
+				}
+				return m.toggle(&m.settings.ReadOnly, "read-only mode", "readonly", args)
+			},
+		},
+		{
+			Name: "safemode", Usage: "[on|off]", Description: "Toggle asking before UPDATE or DELETE without WHERE, DROP and ALTER",
+			Complete: choices("on", "off"),
+			Run: func(m *Model, args []string) tea.Cmd {
+				return m.toggle(&m.settings.SafeMode, "safe mode", "safemode", args)
+			},
+		},
+		{
+			Name: "vacuum", Description: "Rebuild the database file to reclaim space",
+			Run: func(m *Model, _ []string) tea.Cmd {
+				if m.settings.ReadOnly {
+					return NotifyError(errReadOnly)
+				}
+				db := m.db
+				return func() tea.Msg {
+					if _, err := db.Exec("VACUUM"); err != nil {
+						return NotifyMsg{Severity: SeverityError, Message: fmt.Sprintf("vacuum failed: %v", err)}
+					}
+					return NotifyMsg{Severity: SeverityInfo, Message: "database vacuumed"}
+				}
+			},
+		},
+		{
+			Name: "split", Description: "Toggle the split layout",
+			Run: func(m *Model, _ []string) tea.Cmd { return m.toggleSplit() },
+		},
+		{
+			Name: "tab", Usage: "new|close|next|prev|rename [name]", Description: "Manage tabs",
+			MinArgs: 1, Complete: choices("new", "close", "next", "prev", "rename"),
+			Run: func(m *Model, args []string) tea.Cmd {
+				switch args[0] {
+				case "new":
+					return m.newTab()
+				case "close":
+					return m.closeTab()
+				case "next":
+					m.selectTab((m.activeTab + 1) % len(m.tabs))
+				case "prev":
+					m.selectTab((m.activeTab - 1 + len(m.tabs)) % len(m.tabs))
+				case "rename":
+					m.tab.title = strings.Join(args[1:], " ")
+				default:
+					return Notify(SeverityError, "unknown tab action: %s", args[0])
+				}
+				return nil
+			},
+		},
+		{
+			Name: "back", Description: "Go back in the history",
+			Run: func(m *Model, _ []string) tea.Cmd {
+				return func() tea.Msg { return NavigateBackMsg{} }
+			},
+		},
+		{
+			Name: "forward", Description: "Go forward in the history",
+			Run: func(m *Model, _ []string) tea.Cmd {
+				return func() tea.Msg { return NavigateForwardMsg{} }
+			},
+		},
+		{
+			Name: "messages", Description: "Show the message log",
+			Run: func(m *Model, _ []string) tea.Cmd {
+				return func() tea.Msg { return SwitchToMessageLogMsg{} }
+			},
+		},
+		{
+			Name: "status", Description: "Show the connection settings and database status",
+			Run: func(m *Model, _ []string) tea.Cmd {
+				return func() tea.Msg { return SwitchToStatusMsg{} }
+			},
+		},
+		{
+			Name: "bind", Usage: "<key> <command>", Description: "Bind a key to a command",
+			MinArgs: 2,
+			Complete: func(m *Model, i int) []string {
+				if i != 1 {
+					return nil
+				}
+				var names []string
+				for _, c := range m.commands {
+					names = append(names, c.Name)
+				}
+				return names
+			},
+			Run: func(m *Model, args []string) tea.Cmd {
+				m.bindings[args[0]] = strings.Join(args[1:], " ")
+				return Notify(SeverityInfo, "%s runs %s", args[0], m.bindings[args[0]])
+			},
+		},
+		{
+			Name: "unbind", Usage: "<key>", Description: "Remove a key binding",
+			MinArgs: 1,
+			Complete: func(m *Model, i int) []string {
+				if i != 0 {
+					return nil
+				}
+				var keys []string
+				for k := range m.bindings {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				return keys
+			},
+			Run: func(m *Model, args []string) tea.Cmd {
+				delete(m.bindings, args[0])
+				return nil
+			},
+		},
+		{
+			Name: "quit", Aliases: []string{"q"}, Description: "Quit teaqlite",
+			Run: func(*Model, []string) tea.Cmd { return tea.Quit },
+		},
+	}
+}
@@ -0,0 +1,381 @@
+package app
+
+import (
+	"database/sql"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// CompletionKind identifies what a completion candidate refers to
+type CompletionKind int
+
+const (
+	CompletionColumn CompletionKind = iota
+	CompletionTable
+	CompletionView
+	CompletionFunction
+	CompletionPragma
+	CompletionKeyword
+)
+
+// String returns the short label shown next to a candidate in the popup
+func (k CompletionKind) String() string {
+	switch k {
+	case CompletionColumn:
+		return "column"
+	case CompletionTable:
+		return "table"
+	case CompletionView:
+		return "view"
+	case CompletionFunction:
+		return "func"
+	case CompletionPragma:
+		return "pragma"
+	default:
+		return "keyword"
+	}
+}
+
+// CompletionItem is a single completion candidate
+type CompletionItem struct {
+	Text   string
+	Kind   CompletionKind
+	Detail string
+}
+
+// sqlFunctions lists the built-in SQLite scalar, aggregate, window, date,
+// math and JSON functions
+var sqlFunctions = []string{
+	"abs", "changes", "char", "coalesce", "concat", "concat_ws", "format", "glob", "hex", "ifnull",
+	"iif", "instr", "last_insert_rowid", "length", "like", "likelihood", "likely", "lower", "ltrim",
+	"max", "min", "nullif", "octet_length", "printf", "quote", "random", "randomblob", "replace",
+	"round", "rtrim", "sign", "soundex", "substr", "substring", "total_changes", "trim", "typeof",
+	"unhex", "unicode", "unlikely", "upper", "zeroblob",
+	"avg", "count", "group_concat", "string_agg", "sum", "total",
+	"row_number", "rank", "dense_rank", "percent_rank", "cume_dist", "ntile", "lag", "lead",
+	"first_value", "last_value", "nth_value",
+	"date", "time", "datetime", "julianday", "unixepoch", "strftime", "timediff",
+	"acos", "acosh", "asin", "asinh", "atan", "atan2", "atanh", "ceil", "ceiling", "cos", "cosh",
+	"degrees", "exp", "floor", "ln", "log", "log10", "log2", "mod", "pi", "pow", "power", "radians",
+	"sin", "sinh", "sqrt", "tan", "tanh", "trunc",
+	"json", "jsonb", "json_array", "json_array_length", "json_error_position", "json_extract",
+	"json_insert", "json_object", "json_patch", "json_pretty", "json_remove", "json_replace",
+	"json_set", "json_type", "json_valid", "json_quote", "json_group_array", "json_group_object",
+	"json_each", "json_tree",
+}
+
+// sqlPragmas lists the PRAGMA names supported by SQLite
+var sqlPragmas = []string{
+	"analysis_limit", "application_id", "auto_vacuum", "automatic_index", "busy_timeout",
+	"cache_size", "cache_spill", "case_sensitive_like", "cell_size_check", "checkpoint_fullfsync",
+	"collation_list", "compile_options", "data_version", "database_list", "defer_foreign_keys",
+	"encoding", "foreign_key_check", "foreign_key_list", "foreign_keys", "freelist_count",
+	"fullfsync", "function_list", "hard_heap_limit", "ignore_check_constraints", "incremental_vacuum",
+	"index_info", "index_list", "index_xinfo", "integrity_check", "journal_mode",
+	"journal_size_limit", "legacy_alter_table", "locking_mode", "max_page_count", "mmap_size",
+	"module_list", "optimize", "page_count", "page_size", "pragma_list", "query_only",
+	"quick_check", "read_uncommitted", "recursive_triggers", "reverse_unordered_selects",
+	"secure_delete", "shrink_memory", "soft_heap_limit", "synchronous", "table_info",
+	"table_list", "table_xinfo", "temp_store", "threads", "trusted_schema", "user_version",
+	"wal_autocheckpoint", "wal_checkpoint",
+}
+
+// tableContextKeywords are keywords after which a table name is expected
+var tableContextKeywords = []string{"FROM", "JOIN", "INTO", "UPDATE", "TABLE"}
+
+// Completer produces schema-aware completions for SQL text. Table and view
+// names and column lists are cached until Invalidate is called.
+type Completer struct {
+	db      *sql.DB
+	tables  []string
+	views   []string
+	columns map[string][]string
+	loaded  bool
+}
+
+// NewCompleter creates a completer backed by db
+func NewCompleter(db *sql.DB) *Completer {
+	return &Completer{
+		db:      db,
+		columns: map[string][]string{},
+	}
+}
+
+// Invalidate drops all cached schema information so it is reloaded on the
+// next completion request
+func (c *Completer) Invalidate() {
+	c.loaded = false
+	c.tables = nil
+	c.views = nil
+	c.columns = map[string][]string{}
+}
+
+// SetTables seeds the cached table names, typically from SharedData.Tables
+func (c *Completer) SetTables(tables []string) {
+	c.tables = append([]string(nil), tables...)
+}
+
+func (c *Completer) ensureLoaded() {
+	if c.loaded || c.db == nil {
+		return
+	}
+	c.loaded = true
+
+	rows, err := c.db.Query(`SELECT name, type FROM sqlite_master WHERE type IN ('table', 'view') ORDER BY name`)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	haveTables := len(c.tables) > 0
+	for rows.Next() {
+		var name, kind string
+		if err := rows.Scan(&name, &kind); err != nil {
+			return
+		}
+		// Named like SharedData.Tables, see qualifyTable
+		name = qualifyTable("main", name)
+		if kind == "view" {
+			c.views = append(c.views, name)
+		} else if !haveTables {
+			c.tables = append(c.tables, name)
+		}
+	}
+}
+
+// columnsFor returns the (cached) column names of a table or view
+func (c *Completer) columnsFor(table string) []string {
+	key := strings.ToLower(table)
+	if cols, ok := c.columns[key]; ok {
+		return cols
+	}
+	if c.db == nil {
+		return nil
+	}
+
+	var cols []string
+	rows, err := c.db.Query(tablePragma("table_info", table))
+	if err == nil {
+		defer rows.Close()
+		for rows.Next() {
+			var cid, notNull, pk int
+			var name, dataType string
+			var defaultValue sql.NullString
+			if err := rows.Scan(&cid, &name, &dataType, &notNull, &defaultValue, &pk); err != nil {
+				break
+			}
+			cols = append(cols, name)
+		}
+	}
+	c.columns[key] = cols
+	return cols
+}
+
+// Complete returns the word fragment immediately before cursor (a byte
+// offset into text) and the matching candidates, best first.
+func (c *Completer) Complete(text string, cursor int) (string, []CompletionItem) {
+	c.ensureLoaded()
+
+	cursor = max(0, min(cursor, len(text)))
+	prefixStart := cursor
+	for prefixStart > 0 {
+		r, size := utf8.DecodeLastRuneInString(text[:prefixStart])
+		if !isIdentChar(r) {
+			break
+		}
+		prefixStart -= size
+	}
+	prefix := text[prefixStart:cursor]
+
+	tokens := lexSQL(text)
+	// No completions inside strings, blobs or comments
+	for _, t := range tokens {
+		if t.Kind != tokenString && t.Kind != tokenComment && t.Kind != tokenBlob {
+			continue
+		}
+		if t.Start < cursor && (cursor < t.End || (cursor == t.End && !t.Closed())) {
+			return prefix, nil
+		}
+	}
+
+	stmt := significantTokens(statementAt(tokens, cursor))
+	aliases := c.tableReferences(stmt)
+
+	// Tokens before the word being completed
+	var before []sqlToken
+	for _, t := range stmt {
+		if t.End <= prefixStart {
+			before = append(before, t)
+		}
+	}
+
+	// "alias." completes columns of that table only
+	if n := len(before); n >= 2 && before[n-1].Text == "." && before[n-1].End == prefixStart {
+		qualifier := strings.ToLower(before[n-2].Name())
+		table, ok := aliases[qualifier]
+		if !ok {
+			table = before[n-2].Name()
+		}
+		var items []CompletionItem
+		for _, col := range c.columnsFor(table) {
+			items = append(items, CompletionItem{Text: col, Kind: CompletionColumn, Detail: table})
+		}
+		return prefix, filterCompletions(items, prefix)
+	}
+
+	var prev sqlToken
+	if len(before) > 0 {
+		prev = before[len(before)-1]
+	}
+
+	var items []CompletionItem
+	switch {
+	case prev.IsKeyword("PRAGMA"):
+		for _, p := range sqlPragmas {
+			items = append(items, CompletionItem{Text: p, Kind: CompletionPragma})
+		}
+
+	case isTableContext(prev):
+		items = append(items, c.objectItems()...)
+
+	default:
+		seen := map[string]bool{}
+		for _, table := range uniqueTables(aliases) {
+			for _, col := range c.columnsFor(table) {
+				if seen[strings.ToLower(col)] {
+					continue
+				}
+				seen[strings.ToLower(col)] = true
+				items = append(items, CompletionItem{Text: col, Kind: CompletionColumn, Detail: table})
+			}
+		}
+		for _, fn := range sqlFunctions {
+			items = append(items, CompletionItem{Text: fn, Kind: CompletionFunction})
+		}
+		for _, kw := range sqlKeywords {
+			items = append(items, CompletionItem{Text: kw, Kind: CompletionKeyword})
+		}
+		items = append(items, c.objectItems()...)
+	}
+
+	if prefix == "" && !isTableContext(prev) && !prev.IsKeyword("PRAGMA") {
+		// Without a prefix only offer the context-specific candidates
+		var contextual []CompletionItem
+		for _, item := range items {
+			if item.Kind == CompletionColumn {
+				contextual = append(contextual, item)
+			}
+		}
+		items = contextual
+	}
+
+	return prefix, filterCompletions(items, prefix)
+}
+
+func (c *Completer) objectItems() []CompletionItem {
+	var items []CompletionItem
+	for _, t := range c.tables {
+		items = append(items, CompletionItem{Text: t, Kind: CompletionTable})
+	}
+	for _, v := range c.views {
+		items = append(items, CompletionItem{Text: v, Kind: CompletionView})
+	}
+	return items
+}
+
+// tableReferences maps lower-cased names and aliases to the tables they
+// refer to in a statement. Each table also maps to itself.
+func (c *Completer) tableReferences(stmt []sqlToken) map[string]string {
+	refs := map[string]string{}
+	for i := 0; i < len(stmt); i++ {
+		if !isTableContext(stmt[i]) {
+			continue
+		}
+		// Walk a comma separated list: FROM a x, b AS y
+		for j := i + 1; j < len(stmt); {
+			t := stmt[j]
+			if t.Kind != tokenIdent && t.Kind != tokenQuotedIdent {
+				break
+			}
+			name := t.Name()
+			j++
+			// schema.table
+			if j+1 < len(stmt) && stmt[j].Text == "." &&
+				(stmt[j+1].Kind == tokenIdent || stmt[j+1].Kind == tokenQuotedIdent) {
+				name = stmt[j+1].Name()
+				j += 2
+			}
+			refs[strings.ToLower(name)] = name
+			if j < len(stmt) && stmt[j].IsKeyword("AS") {
+				j++
+			}
+			if j < len(stmt) && (stmt[j].Kind == tokenIdent || stmt[j].Kind == tokenQuotedIdent) {
+				refs[strings.ToLower(stmt[j].Name())] = name
+				j++
+			}
+			if j < len(stmt) && stmt[j].Text == "," {
+				j++
+				continue
+			}
+			break
+		}
+	}
+	return refs
+}
+
+func isTableContext(t sqlToken) bool {
+	for _, kw := range tableContextKeywords {
+		if t.IsKeyword(kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueTables returns the distinct tables referenced by an alias map
+func uniqueTables(refs map[string]string) []string {
+	seen := map[string]bool{}
+	var tables []string
+	for _, table := range refs {
+		if !seen[strings.ToLower(table)] {
+			seen[strings.ToLower(table)] = true
+			tables = append(tables, table)
+		}
+	}
+	sort.Strings(tables)
+	return tables
+}
+
+// filterCompletions keeps items starting with prefix (case-insensitive),
+// preserving kind order and sorting alphabetically within each kind
+func filterCompletions(items []CompletionItem, prefix string) []CompletionItem {
+	lowerPrefix := strings.ToLower(prefix)
+	var out []CompletionItem
+	for _, item := range items {
+		lowerText := strings.ToLower(item.Text)
+		if strings.HasPrefix(lowerText, lowerPrefix) && lowerText != lowerPrefix {
+			out = append(out, item)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Kind != out[j].Kind {
+			return out[i].Kind < out[j].Kind
+		}
+		return strings.ToLower(out[i].Text) < strings.ToLower(out[j].Text)
+	})
+	return out
+}
+
+// isDDLStatement reports whether query changes the schema
+func isDDLStatement(query string) bool {
+	for _, stmt := range splitStatements(query) {
+		for _, t := range significantTokens(lexSQL(stmt)) {
+			if t.IsKeyword("CREATE") || t.IsKeyword("DROP") || t.IsKeyword("ALTER") {
+				return true
+			}
+			break
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTableCompletionText(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"users", "users"},
+		{"order items", `"order items"`},
+		{"aux.foo", "aux.foo"},
+		{"aux.order items", `aux."order items"`},
+		{"main.a.b", `main."a.b"`},
+	}
+	for _, tt := range tests {
+		if got := tableCompletionText(tt.name); got != tt.want {
+			t.Errorf("tableCompletionText(%q) = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestComplete(t *testing.T) {
+	db := openTestDatabase(t, `CREATE TABLE users(id INTEGER, name TEXT, email TEXT);
+		CREATE TABLE orders(id INTEGER, user_id INTEGER, total REAL);
+		CREATE VIEW user_names AS SELECT name FROM users`)
+	c := NewCompleter(db)
+
+	texts := func(items []CompletionItem) []string {
+		var out []string
+		for _, item := range items {
+			out = append(out, item.Text)
+		}
+		return out
+	}
+	// | marks the cursor
+	tests := []struct {
+		name   string
+		text   string
+		prefix string
+		want   []string
+	}{
+		{"tables after FROM", "SELECT * FROM us|", "us", []string{"users", "user_names"}},
+		{"columns of the tables in the statement", "SELECT na| FROM users", "na", []string{"name", "NATURAL"}},
+		{"columns after an alias", "SELECT o.t| FROM orders o", "t", []string{"total"}},
+		{"columns without a prefix", "SELECT | FROM orders", "", []string{"id", "total", "user_id"}},
+		{"keywords", "SELECT * FROM users WHE|", "WHE", []string{"WHEN", "WHERE"}},
+		{"pragmas", "PRAGMA table_i|", "table_i", []string{"table_info"}},
+		{"inside a string", "SELECT 'us|", "us", nil},
+		{"inside an unclosed string ending in a quote", "SELECT * FROM users WHERE name = 'na''|", "", nil},
+		{"inside a comment", "SELECT 1 -- us|", "us", nil},
+		{"after a closed string", "SELECT 'a' FROM us|", "us", []string{"users", "user_names"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor := strings.Index(tt.text, "|")
+			text := strings.Replace(tt.text, "|", "", 1)
+			prefix, items := c.Complete(text, cursor)
+			if prefix != tt.prefix {
+				t.Errorf("prefix %q, want %q", prefix, tt.prefix)
+			}
+			if got := texts(items); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
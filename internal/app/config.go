@@ -0,0 +1,453 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/BurntSushi/toml"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxPageSize bounds the configured page size
+const maxPageSize = 10000
+
+// Config is a validated user configuration file
+type Config struct {
+	Settings *Settings
+	// Bindings map keys to command lines, as with the bind command
+	Bindings       map[string]string
+	NullText       string
+	DateFormat     string
+	DateTimeFormat string
+}
+
+// configFile is the TOML layout of the configuration file
+type configFile struct {
+	PageSize       *int                      `toml:"page_size"`
+	ReadOnly       *bool                     `toml:"read_only"`
+	SafeMode       *bool                     `toml:"safe_mode"`
+	Null           *string                   `toml:"null"`
+	DateFormat     *string                   `toml:"date_format"`
+	DateTimeFormat *string                   `toml:"datetime_format"`
+	Theme          *string                   `toml:"theme"`
+	Themes         map[string]themeConfig    `toml:"themes"`
+	Keys           map[string]map[string]any `toml:"keys"`
+	Styles         map[string]StyleConfig    `toml:"styles"`
+	Bindings       map[string]string         `toml:"bindings"`
+}
+
+// themeConfig is a custom theme: a built-in theme with some styles changed
+type themeConfig struct {
+	Base   *string                `toml:"base"`
+	Styles map[string]StyleConfig `toml:"styles"`
+}
+
+// StyleConfig overrides parts of a style. Colors are hex values such as
+// "#7D56F4" or ANSI color numbers.
+type StyleConfig struct {
+	Foreground       *string `toml:"foreground"`
+	Background       *string `toml:"background"`
+	BorderForeground *string `toml:"border_foreground"`
+	Bold             *bool   `toml:"bold"`
+	Italic           *bool   `toml:"italic"`
+	Underline        *bool   `toml:"underline"`
+	Faint            *bool   `toml:"faint"`
+	Reverse          *bool   `toml:"reverse"`
+}
+
+// DefaultConfigPath returns the configuration file in the user config dir,
+// $XDG_CONFIG_HOME/teaqlite/config.toml on Linux
+func DefaultConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "teaqlite", "config.toml"), nil
+}
+
+// DefaultConfig returns the configuration used without a config file. The
+// theme follows the terminal background.
+func DefaultConfig() *Config {
+	settings := DefaultSettings()
+	settings.Theme, _ = BuiltinTheme(ThemeAuto)
+	return &Config{
+		Settings:       settings,
+		Bindings:       map[string]string{},
+		NullText:       NullText,
+		DateFormat:     DateFormat,
+		DateTimeFormat: DateTimeFormat,
+	}
+}
+
+// LoadConfig reads and validates the configuration file at path. Every
+// problem found is reported, not just the first.
+func LoadConfig(path string) (*Config, error) {
+	var file configFile
+	meta, err := toml.DecodeFile(path, &file)
+	if err != nil {
+		var perr toml.ParseError
+		if errors.As(err, &perr) {
+			return nil, fmt.Errorf("invalid config file %s:\n%s", path, perr.ErrorWithPosition())
+		}
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	var errs []error
+	for _, k := range meta.Undecoded() {
+		errs = append(errs, fmt.Errorf("unknown setting %q", k.String()))
+	}
+
+	c, err := file.config()
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("invalid config file %s:\n%w", path, err)
+	}
+	return c, nil
+}
+
+// config validates the file and builds the configuration
+func (f *configFile) config() (*Config, error) {
+	c := DefaultConfig()
+	var errs []error
+
+	if f.PageSize != nil {
+		if *f.PageSize < 1 || *f.PageSize > maxPageSize {
+			errs = append(errs, fmt.Errorf("page_size must be between 1 and %d, got %d", maxPageSize, *f.PageSize))
+		} else {
+			c.Settings.PageSize = *f.PageSize
+		}
+	}
+	if f.ReadOnly != nil {
+		c.Settings.ReadOnly = *f.ReadOnly
+	}
+	if f.SafeMode != nil {
+		c.Settings.SafeMode = *f.SafeMode
+	}
+	if f.Null != nil {
+		c.NullText = *f.Null
+	}
+	if f.DateFormat != nil {
+		if err := checkTimeLayout("date_format", *f.DateFormat); err != nil {
+			errs = append(errs, err)
+		}
+		c.DateFormat = *f.DateFormat
+	}
+	if f.DateTimeFormat != nil {
+		if err := checkTimeLayout("datetime_format", *f.DateTimeFormat); err != nil {
+			errs = append(errs, err)
+		}
+		c.DateTimeFormat = *f.DateTimeFormat
+	}
+
+	views := keyMapsByView(&c.Settings.KeyMaps)
+	for _, view := range sortedKeys(f.Keys) {
+		keyMap, ok := views[view]
+		if !ok {
+			errs = append(errs, fmt.Errorf("[keys.%s]: unknown view (valid views: %s)", view, strings.Join(sortedKeys(views), ", ")))
+			continue
+		}
+		if err := overrideKeys(keyMap, f.Keys[view]); err != nil {
+			errs = append(errs, fmt.Errorf("[keys.%s]: %w", view, err))
+		}
+	}
+
+	theme, err := f.theme()
+	if err != nil {
+		errs = append(errs, err)
+	} else {
+		c.Settings.Theme = theme
+	}
+
+	commands := defaultCommands()
+	for _, k := range sortedKeys(f.Bindings) {
+		line := f.Bindings[k]
+		name, _, _ := strings.Cut(strings.TrimSpace(line), " ")
+		if !isCommand(commands, name) {
+			errs = append(errs, fmt.Errorf("[bindings]: %q runs unknown command %q", k, name))
+			continue
+		}
+		c.Bindings[k] = line
+	}
+
+	return c, errors.Join(errs...)
+}
+
+// isCommand reports whether name is a command name or alias
+func isCommand(commands []Command, name string) bool {
+	for _, c := range commands {
+		if c.Name == name || slices.Contains(c.Aliases, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTimeLayout makes sure a date format is a Go time layout
+func checkTimeLayout(setting, layout string) error {
+	if layout == "" || time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC).Format(layout) == layout {
+		return fmt.Errorf("%s %q is not a Go time layout; write the reference time 2006-01-02 15:04:05 in the desired format", setting, layout)
+	}
+	return nil
+}
+
+// keyMapsByView returns the key maps that can be configured, by the name
+// of their section in the config file
+func keyMapsByView(k *KeyMaps) map[string]any {
+	return map[string]any{
+		"app":          &k.App,
+		"table_list":   &k.TableList,
+		"table_data":   &k.TableData,
+		"row_detail":   &k.RowDetail,
+		"edit_cell":    &k.EditCell,
+		"query":        &k.Query,
+		"sql_editor":   &k.SQLEditor,
+		"message_log":  &k.MessageLog,
+		"schema":       &k.Schema,
+		"command_line": &k.CommandLine,
+		"palette":      &k.Palette,
+		"export":       &k.Export,
+		"import":       &k.Import,
+		"status":       &k.Status,
+		"picker":       &k.Picker,
+	}
+}
+
+// overrideKeys replaces the keys of the bindings in keyMap, a pointer to a
+// key map struct. Actions are the field names in snake case; an empty list
+// disables the binding.
+func overrideKeys(keyMap any, actions map[string]any) error {
+	v := reflect.ValueOf(keyMap).Elem()
+	bindings := map[string]*key.Binding{}
+	for i := 0; i < v.NumField(); i++ {
+		if b, ok := v.Field(i).Addr().Interface().(*key.Binding); ok {
+			bindings[snakeCase(v.Type().Field(i).Name)] = b
+		}
+	}
+
+	var errs []error
+	for _, action := range sortedKeys(actions) {
+		b, ok := bindings[action]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown action %q (valid actions: %s)", action, strings.Join(sortedKeys(bindings), ", ")))
+			continue
+		}
+		keys, err := keyList(actions[action])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", action, err))
+			continue
+		}
+		if len(keys) == 0 {
+			b.SetEnabled(false)
+			continue
+		}
+		b.SetKeys(keys...)
+		b.SetHelp(strings.Join(keys, "/"), b.Help().Desc)
+	}
+	return errors.Join(errs...)
+}
+
+// keyList reads a key or a list of keys
+func keyList(value any) ([]string, error) {
+	var values []any
+	switch v := value.(type) {
+	case string:
+		values = []any{v}
+	case []any:
+		values = v
+	default:
+		return nil, fmt.Errorf("expected a key such as \"ctrl+s\" or a list of keys, got %v", value)
+	}
+
+	keys := make([]string, 0, len(values))
+	for _, v := range values {
+		k, ok := v.(string)
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid key %v; keys are strings such as \"j\", \"ctrl+s\" or \"alt+enter\"", v)
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// snakeCase converts a Go field name such as SQLMode to sql_mode
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prevLower := !unicode.IsUpper(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || nextLower {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// theme builds the selected theme: a built-in or custom theme, then the
+// [styles] overrides. Custom themes are checked even when not selected.
+func (f *configFile) theme() (*Theme, error) {
+	var errs []error
+	custom := map[string]*Theme{}
+	for _, name := range sortedKeys(f.Themes) {
+		t := f.Themes[name]
+		base := ThemeAuto
+		if t.Base != nil {
+			base = *t.Base
+		}
+		theme, ok := BuiltinTheme(base)
+		if !ok {
+			errs = append(errs, fmt.Errorf("[themes.%s]: unknown base theme %q (valid themes: %s)", name, base, strings.Join(BuiltinThemeNames(), ", ")))
+			continue
+		}
+		theme.Name = name
+		if err := overrideStyles(theme, t.Styles); err != nil {
+			errs = append(errs, fmt.Errorf("[themes.%s]: %w", name, err))
+		}
+		custom[name] = theme
+	}
+
+	name := ThemeAuto
+	if f.Theme != nil {
+		name = *f.Theme
+	}
+	theme, ok := custom[name]
+	if !ok {
+		if theme, ok = BuiltinTheme(name); !ok {
+			names := append(BuiltinThemeNames(), sortedKeys(f.Themes)...)
+			errs = append(errs, fmt.Errorf("unknown theme %q (valid themes: %s)", name, strings.Join(names, ", ")))
+			return nil, errors.Join(errs...)
+		}
+	}
+	if err := overrideStyles(theme, f.Styles); err != nil {
+		errs = append(errs, fmt.Errorf("[styles]: %w", err))
+	}
+	return theme, errors.Join(errs...)
+}
+
+// overrideStyles changes the named styles of a theme. Style names are the
+// theme field names in snake case.
+func overrideStyles(theme *Theme, styles map[string]StyleConfig) error {
+	v := reflect.ValueOf(theme).Elem()
+	byName := map[string]*lipgloss.Style{}
+	for i := 0; i < v.NumField(); i++ {
+		if s, ok := v.Field(i).Addr().Interface().(*lipgloss.Style); ok {
+			byName[snakeCase(v.Type().Field(i).Name)] = s
+		}
+	}
+
+	var errs []error
+	for _, name := range sortedKeys(styles) {
+		style, ok := byName[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown style %q (valid styles: %s)", name, strings.Join(sortedKeys(byName), ", ")))
+			continue
+		}
+		if err := styles[name].validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		*style = styles[name].apply(*style)
+	}
+	return errors.Join(errs...)
+}
+
+// validate checks the colors of a style
+func (s StyleConfig) validate() error {
+	var errs []error
+	colors := []struct {
+		name  string
+		value *string
+	}{
+		{"foreground", s.Foreground},
+		{"background", s.Background},
+		{"border_foreground", s.BorderForeground},
+	}
+	for _, c := range colors {
+		if c.value != nil && !validColor(*c.value) {
+			errs = append(errs, fmt.Errorf("%s: invalid color %q; use a hex color such as \"#7D56F4\" or an ANSI color number from 0 to 255", c.name, *c.value))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validColor(c string) bool {
+	if strings.HasPrefix(c, "#") {
+		if len(c) != 4 && len(c) != 7 {
+			return false
+		}
+		_, err := strconv.ParseUint(c[1:], 16, 32)
+		return err == nil
+	}
+	n, err := strconv.Atoi(c)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// apply overrides parts of style
+func (s StyleConfig) apply(style lipgloss.Style) lipgloss.Style {
+	if s.Foreground != nil {
+		style = style.Foreground(lipgloss.Color(*s.Foreground))
+	}
+	if s.Background != nil {
+		style = style.Background(lipgloss.Color(*s.Background))
+	}
+	if s.BorderForeground != nil {
+		style = style.BorderForeground(lipgloss.Color(*s.BorderForeground))
+	}
+	if s.Bold != nil {
+		style = style.Bold(*s.Bold)
+	}
+	if s.Italic != nil {
+		style = style.Italic(*s.Italic)
+	}
+	if s.Underline != nil {
+		style = style.Underline(*s.Underline)
+	}
+	if s.Faint != nil {
+		style = style.Faint(*s.Faint)
+	}
+	if s.Reverse != nil {
+		style = style.Reverse(*s.Reverse)
+	}
+	return style
+}
+
+// WithConfig applies a configuration: settings, theme, keybindings and
+// command bindings for the model, and the value formats, which are global.
+func WithConfig(c *Config) Option {
+	return func(m *Model) {
+		m.settings = c.Settings
+		m.keyMap = c.Settings.KeyMaps.App
+		for k, line := range c.Bindings {
+			m.bindings[k] = line
+		}
+
+		NullText = c.NullText
+		DateFormat = c.DateFormat
+		DateTimeFormat = c.DateTimeFormat
+	}
+}
+
+// sortedKeys returns the keys of a map in order, for stable messages
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,182 @@
+package app
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultBusyTimeout is how long a connection waits for a lock held by
+// another connection before failing with SQLITE_BUSY
+const DefaultBusyTimeout = 5 * time.Second
+
+// Pragma is a PRAGMA run on every new connection
+type Pragma struct {
+	Name  string
+	Value string
+}
+
+var (
+	pragmaNameRe  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	pragmaValueRe = regexp.MustCompile(`^[A-Za-z0-9_.+-]+$`)
+)
+
+// ParsePragma parses a pragma given as name=value
+func ParsePragma(s string) (Pragma, error) {
+	name, value, ok := strings.Cut(s, "=")
+	name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+	if !ok || !pragmaNameRe.MatchString(name) {
+		return Pragma{}, fmt.Errorf("invalid pragma %q: use name=value, e.g. foreign_keys=on", s)
+	}
+	if !pragmaValueRe.MatchString(value) {
+		return Pragma{}, fmt.Errorf("invalid value for pragma %s: %q", name, value)
+	}
+	return Pragma{Name: strings.ToLower(name), Value: value}, nil
+}
+
+// ConnectionOptions configure how a database is opened
+type ConnectionOptions struct {
+	// ReadOnly opens the database with mode=ro
+	ReadOnly bool
+	// BusyTimeout sets PRAGMA busy_timeout unless a pragma sets it
+	BusyTimeout time.Duration
+	// Pragmas are run on every new connection, after busy_timeout
+	Pragmas []Pragma
+	// Attachments are attached to every new connection
+	Attachments []Attachment
+}
+
+// DefaultConnectionOptions returns the options used without flags
+func DefaultConnectionOptions() ConnectionOptions {
+	return ConnectionOptions{BusyTimeout: DefaultBusyTimeout}
+}
+
+// DataSource returns the data source name that opens path, a file name or
+// a file: URI, with the options. The pragmas become _pragma parameters so
+// the driver runs them on every connection in the pool.
+func DataSource(path string, opts ConnectionOptions) (string, error) {
+	uri, query := path, ""
+	if strings.HasPrefix(path, "file:") {
+		uri, query, _ = strings.Cut(path, "?")
+	} else {
+		uri = "file:" + strings.NewReplacer("%", "%25", "?", "%3f", "#", "%23").Replace(path)
+	}
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return "", fmt.Errorf("invalid database URI %q: %w", path, err)
+	}
+
+	extra := url.Values{}
+	if opts.ReadOnly {
+		switch mode := params.Get("mode"); mode {
+		case "":
+			extra.Set("mode", "ro")
+		case "ro", "memory":
+		default:
+			return "", fmt.Errorf("cannot open read-only with mode=%s", mode)
+		}
+	}
+	hasBusyTimeout := false
+	for _, p := range params["_pragma"] {
+		hasBusyTimeout = hasBusyTimeout || strings.HasPrefix(strings.ToLower(strings.TrimSpace(p)), "busy_timeout")
+	}
+	for _, p := range opts.Pragmas {
+		hasBusyTimeout = hasBusyTimeout || p.Name == "busy_timeout"
+	}
+	if !hasBusyTimeout && opts.BusyTimeout > 0 {
+		extra.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", opts.BusyTimeout.Milliseconds()))
+	}
+	for _, p := range opts.Pragmas {
+		extra.Add("_pragma", fmt.Sprintf("%s(%s)", p.Name, p.Value))
+	}
+
+	if len(extra) > 0 {
+		if query != "" {
+			query += "&"
+		}
+		// Parentheses are allowed in a query and keep pragmas readable
+		query += strings.NewReplacer("%28", "(", "%29", ")").Replace(extra.Encode())
+	}
+	if query == "" {
+		return uri, nil
+	}
+	return uri + "?" + query, nil
+}
+
+// DatabaseFile returns the file name a path or file: URI refers to, or ""
+// for an in-memory database
+func DatabaseFile(path string) string {
+	if !strings.HasPrefix(path, "file:") {
+		if path == ":memory:" {
+			return ""
+		}
+		return path
+	}
+	rest, query, _ := strings.Cut(strings.TrimPrefix(path, "file:"), "?")
+	rest, _, _ = strings.Cut(rest, "#")
+	if strings.HasPrefix(rest, "//") {
+		// Drop the authority, which SQLite only allows to be empty or localhost
+		rest = rest[2:]
+		if i := strings.IndexByte(rest, '/'); i >= 0 {
+			rest = rest[i:]
+		} else {
+			rest = ""
+		}
+	}
+	if params, err := url.ParseQuery(query); err == nil && params.Get("mode") == "memory" {
+		return ""
+	}
+	if name, err := url.PathUnescape(rest); err == nil {
+		rest = name
+	}
+	if rest == ":memory:" {
+		return ""
+	}
+	return rest
+}
+
+// OpenDatabase opens a database file or URI and connects, so that a
+// missing file, a failing pragma or attachment is reported straight away
+func OpenDatabase(path string, opts ConnectionOptions) (*sql.DB, error) {
+	dsn, err := DataSource(path, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i, a := range opts.Attachments {
+		if err := checkAttachment(a, opts.Attachments[:i]); err != nil {
+			return nil, err
+		}
+	}
+	memory := DatabaseFile(path) == ""
+	if memory {
+		dsn = sharedMemory(dsn)
+	}
+	db, err := openPool(dsn, opts.ReadOnly, opts.Attachments, memory)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// memoryDatabases numbers the in-memory databases opened by the process
+var memoryDatabases atomic.Int64
+
+// sharedMemory names the data source of an in-memory database and gives
+// it a shared cache, so every connection of a pool opens the same
+// database instead of an empty one of its own
+func sharedMemory(dsn string) string {
+	_, query, _ := strings.Cut(dsn, "?")
+	params, _ := url.ParseQuery(query)
+	params.Set("mode", "memory")
+	params.Set("cache", "shared")
+	name := fmt.Sprintf("file:teaqlite-memory-%d", memoryDatabases.Add(1))
+	return name + "?" + strings.NewReplacer("%28", "(", "%29", ")").Replace(params.Encode())
+}
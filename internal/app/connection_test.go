@@ -0,0 +1,119 @@
+package app
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestInMemoryDatabaseIsShared(t *testing.T) {
+	other := openTestDatabase(t, "CREATE TABLE o(v)")
+	var otherPath string
+	if err := other.QueryRow("SELECT file FROM pragma_database_list WHERE name = 'main'").Scan(&otherPath); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{":memory:", "file::memory:", "file:mem?mode=memory&cache=shared"} {
+		t.Run(path, func(t *testing.T) {
+			db, err := OpenDatabase(path, DefaultConnectionOptions())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+			if _, err := db.Exec("CREATE TABLE t(v); INSERT INTO t VALUES (1)"); err != nil {
+				t.Fatal(err)
+			}
+
+			// A second connection sees the same database
+			rows, release, err := queryOnly(db, "SELECT v FROM t")
+			if err != nil {
+				t.Fatal(err)
+			}
+			rows.Close()
+			release()
+
+			// Attaching replaces every connection of the pool
+			if err := AttachDatabase(db, Attachment{Alias: "other", Path: filepath.Clean(otherPath)}); err != nil {
+				t.Fatal(err)
+			}
+			var count int
+			if err := db.QueryRow("SELECT count(*) FROM t, other.o").Scan(&count); err != nil {
+				t.Errorf("table lost after attaching: %v", err)
+			}
+		})
+	}
+}
+
+func TestParsePragma(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Pragma
+		wantErr bool
+	}{
+		{"foreign_keys=on", Pragma{Name: "foreign_keys", Value: "on"}, false},
+		{" Cache_Size = -2000 ", Pragma{Name: "cache_size", Value: "-2000"}, false},
+		{"journal_mode=WAL", Pragma{Name: "journal_mode", Value: "WAL"}, false},
+		{"foreign_keys", Pragma{}, true},
+		{"1abc=2", Pragma{}, true},
+		{"a=", Pragma{}, true},
+		{"a=1); DROP TABLE t", Pragma{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePragma(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParsePragma(%q) = %+v, %v; want %+v, error %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestDataSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		opts    ConnectionOptions
+		want    string
+		wantErr bool
+	}{
+		{"plain file", "shop.db", ConnectionOptions{}, "file:shop.db", false},
+		{"escaped characters", "a?b#c%.db", ConnectionOptions{}, "file:a%3fb%23c%25.db", false},
+		{"busy timeout", "shop.db", DefaultConnectionOptions(), "file:shop.db?_pragma=busy_timeout(5000)", false},
+		{"read-only", "shop.db", ConnectionOptions{ReadOnly: true}, "file:shop.db?mode=ro", false},
+		{"pragmas", "shop.db", ConnectionOptions{BusyTimeout: time.Second, Pragmas: []Pragma{{"foreign_keys", "on"}}},
+			"file:shop.db?_pragma=busy_timeout(1000)&_pragma=foreign_keys(on)", false},
+		{"pragma sets busy timeout", "shop.db", ConnectionOptions{BusyTimeout: time.Second, Pragmas: []Pragma{{"busy_timeout", "10"}}},
+			"file:shop.db?_pragma=busy_timeout(10)", false},
+		{"URI keeps its parameters", "file:shop.db?cache=private", ConnectionOptions{ReadOnly: true}, "file:shop.db?cache=private&mode=ro", false},
+		{"URI with busy timeout", "file:shop.db?_pragma=busy_timeout(1)", DefaultConnectionOptions(), "file:shop.db?_pragma=busy_timeout(1)", false},
+		{"URI already read-only", "file:shop.db?mode=ro", ConnectionOptions{ReadOnly: true}, "file:shop.db?mode=ro", false},
+		{"read-only conflicts with mode", "file:shop.db?mode=rwc", ConnectionOptions{ReadOnly: true}, "", true},
+		{"invalid URI", "file:shop.db?a=%zz", ConnectionOptions{}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DataSource(tt.path, tt.opts)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("got %q, %v; want %q, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDatabaseFile(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"shop.db", "shop.db"},
+		{":memory:", ""},
+		{"file::memory:", ""},
+		{"file:mem?mode=memory&cache=shared", ""},
+		{"file:shop.db?mode=ro", "shop.db"},
+		{"file:///tmp/shop.db", "/tmp/shop.db"},
+		{"file://localhost/tmp/a%20b.db#x", "/tmp/a b.db"},
+	}
+	for _, tt := range tests {
+		if got := DatabaseFile(tt.path); got != tt.want {
+			t.Errorf("DatabaseFile(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
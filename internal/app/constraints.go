@@ -0,0 +1,271 @@
+package app
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ConstraintKind identifies the kind of constraint an edit violated
+type ConstraintKind int
+
+const (
+	ConstraintNotNull ConstraintKind = iota
+	ConstraintCheck
+	ConstraintUnique
+	ConstraintForeignKey
+	ConstraintType
+)
+
+// String returns the SQL name of the constraint kind
+func (k ConstraintKind) String() string {
+	switch k {
+	case ConstraintNotNull:
+		return "NOT NULL"
+	case ConstraintCheck:
+		return "CHECK"
+	case ConstraintUnique:
+		return "UNIQUE"
+	case ConstraintForeignKey:
+		return "FOREIGN KEY"
+	default:
+		return "TYPE"
+	}
+}
+
+// ConstraintError reports an edit rejected by a table constraint
+type ConstraintError struct {
+	Kind    ConstraintKind
+	Columns []string
+	Detail  string
+	Err     error // underlying SQLite error, if any
+}
+
+func (e *ConstraintError) Error() string {
+	msg := e.Kind.String() + " constraint failed"
+	if len(e.Columns) > 0 {
+		msg += " on " + strings.Join(e.Columns, ", ")
+	}
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	return msg
+}
+
+func (e *ConstraintError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	notNullFailedRe = regexp.MustCompile(`NOT NULL constraint failed: ([^\s(]+)`)
+	checkFailedRe   = regexp.MustCompile(`CHECK constraint failed: (.+?)(?: \(\d+\))?$`)
+	uniqueFailedRe  = regexp.MustCompile(`UNIQUE constraint failed: (.+?)(?: \(\d+\))?$`)
+	strictTypeRe    = regexp.MustCompile(`cannot store (\w+) value in (\w+) column ([^\s(]+)`)
+)
+
+// classifyConstraintError turns SQLite constraint failures into a
+// ConstraintError. Other errors are returned unchanged.
+func classifyConstraintError(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+
+	// Drop the "table." qualifier from column names
+	columns := func(list string) []string {
+		var cols []string
+		for _, c := range strings.Split(list, ",") {
+			c = strings.TrimSpace(c)
+			if i := strings.LastIndex(c, "."); i >= 0 {
+				c = c[i+1:]
+			}
+			cols = append(cols, c)
+		}
+		return cols
+	}
+
+	switch {
+	case notNullFailedRe.MatchString(msg):
+		m := notNullFailedRe.FindStringSubmatch(msg)
+		return &ConstraintError{Kind: ConstraintNotNull, Columns: columns(m[1]), Detail: "value cannot be NULL", Err: err}
+	case checkFailedRe.MatchString(msg):
+		m := checkFailedRe.FindStringSubmatch(msg)
+		return &ConstraintError{Kind: ConstraintCheck, Detail: m[1], Err: err}
+	case uniqueFailedRe.MatchString(msg):
+		m := uniqueFailedRe.FindStringSubmatch(msg)
+		return &ConstraintError{Kind: ConstraintUnique, Columns: columns(m[1]), Detail: "another row already has this value", Err: err}
+	case strings.Contains(msg, "FOREIGN KEY constraint failed"):
+		return &ConstraintError{Kind: ConstraintForeignKey, Detail: "no matching row in the referenced table", Err: err}
+	case strictTypeRe.MatchString(msg):
+		m := strictTypeRe.FindStringSubmatch(msg)
+		return &ConstraintError{Kind: ConstraintType, Columns: columns(m[3]),
+			Detail: fmt.Sprintf("STRICT table cannot store %s in %s column", m[1], m[2]), Err: err}
+	case strings.Contains(msg, "datatype mismatch"):
+		return &ConstraintError{Kind: ConstraintType, Detail: "datatype mismatch", Err: err}
+	}
+	return err
+}
+
+// isConstraintError reports whether err is a ConstraintError
+func isConstraintError(err error) bool {
+	var ce *ConstraintError
+	return errors.As(err, &ce)
+}
+
+// ForeignKey is one foreign key constraint of a table, as reported by
+// PRAGMA foreign_key_list. Columns and RefColumns pair up by position.
+type ForeignKey struct {
+	ID         int
+	Table      string
+	RefTable   string
+	Columns    []string
+	RefColumns []string
+}
+
+// loadForeignKeys reads the foreign keys declared on a table
+func loadForeignKeys(db querier, tableName string) ([]ForeignKey, error) {
+	rows, err := db.Query(tablePragma("foreign_key_list", tableName))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Foreign keys refer to tables of the same schema
+	schema, _ := splitTable(tableName)
+	var fks []ForeignKey
+	byID := map[int]int{}
+	for rows.Next() {
+		var id, seq int
+		var refTable, from string
+		var to sql.NullString
+		var onUpdate, onDelete, match string
+		if err := rows.Scan(&id, &seq, &refTable, &from, &to, &onUpdate, &onDelete, &match); err != nil {
+			return nil, err
+		}
+		idx, ok := byID[id]
+		if !ok {
+			idx = len(fks)
+			byID[id] = idx
+			fks = append(fks, ForeignKey{ID: id, Table: tableName, RefTable: qualifyTable(schema, refTable)})
+		}
+		fks[idx].Columns = append(fks[idx].Columns, from)
+		fks[idx].RefColumns = append(fks[idx].RefColumns, to.String)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// An omitted column list refers to the parent's primary key
+	for i := range fks {
+		if fks[i].RefColumns[0] != "" {
+			continue
+		}
+		parentInfo, err := loadColumnInfo(db, fks[i].RefTable)
+		if err != nil {
+			return nil, err
+		}
+		pk := make([]string, len(fks[i].Columns))
+		for _, col := range parentInfo {
+			if col.PrimaryKey > 0 && col.PrimaryKey <= len(pk) {
+				pk[col.PrimaryKey-1] = col.Name
+			}
+		}
+		fks[i].RefColumns = pk
+	}
+	return fks, nil
+}
+
+// isStrictTable reports whether a table was declared STRICT
+func isStrictTable(db *sql.DB, tableName string) bool {
+	var strict int
+	schema, table := splitTable(tableName)
+	err := db.QueryRow(`SELECT strict FROM pragma_table_list WHERE schema = ? AND name = ?`, schema, table).Scan(&strict)
+	return err == nil && strict != 0
+}
+
+// checkCellConstraints validates an edited value before it is written.
+// NOT NULL, STRICT types and foreign keys are checked here so that they are
+// reported even when SQLite would not enforce them (foreign keys are off by
+// default); CHECK and UNIQUE violations are reported by SQLite on UPDATE.
+func (s *SharedData) checkCellConstraints(tableName string, rowIndex int, col ColumnInfo, stored StoredValue) error {
+	if stored.Arg == nil {
+		if col.NotNull {
+			return &ConstraintError{Kind: ConstraintNotNull, Columns: []string{col.Name}, Detail: "value cannot be NULL"}
+		}
+		return nil
+	}
+
+	if isStrictTable(s.DB, tableName) {
+		declared := strings.ToUpper(col.Type)
+		ok := true
+		switch declared {
+		case "INT", "INTEGER":
+			ok = stored.Type == "INTEGER"
+		case "REAL":
+			ok = stored.Type == "INTEGER" || stored.Type == "REAL"
+		case "TEXT":
+			ok = stored.Type == "TEXT"
+		case "BLOB":
+			ok = false
+		}
+		if !ok {
+			return &ConstraintError{Kind: ConstraintType, Columns: []string{col.Name},
+				Detail: fmt.Sprintf("STRICT table cannot store %s in %s column", stored.Type, declared)}
+		}
+	}
+
+	fks, err := loadForeignKeys(s.DB, tableName)
+	if err != nil {
+		return err
+	}
+	tableColumns, _, _ := s.getTableInfo(tableName)
+	for _, fk := range fks {
+		edited := false
+		for _, c := range fk.Columns {
+			if c == col.Name {
+				edited = true
+			}
+		}
+		if !edited {
+			continue
+		}
+
+		var where []string
+		var args []any
+		hasNull := false
+		for i, c := range fk.Columns {
+			var value any = stored.Arg
+			if c != col.Name {
+				v, err := s.findColumnValue(rowIndex, c, tableColumns)
+				if err != nil {
+					return err
+				}
+				if v == NullValue {
+					hasNull = true
+					break
+				}
+				value = v
+			}
+			where = append(where, fmt.Sprintf("%s = ?", quoteIdent(fk.RefColumns[i])))
+			args = append(args, value)
+		}
+		// Foreign keys with a NULL column are not enforced
+		if hasNull {
+			continue
+		}
+
+		var exists int
+		query := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE %s)", quoteTable(fk.RefTable), strings.Join(where, " AND "))
+		if err := s.DB.QueryRow(query, args...).Scan(&exists); err != nil {
+			return err
+		}
+		if exists == 0 {
+			return &ConstraintError{Kind: ConstraintForeignKey, Columns: fk.Columns,
+				Detail: fmt.Sprintf("no row in %s with %s = %s", fk.RefTable, strings.Join(fk.RefColumns, ", "), stored.Display)}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,379 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// DumpOptions selects what a dump contains
+type DumpOptions struct {
+	// Tables limits the dump to these tables and views with their indexes
+	// and triggers; empty dumps everything
+	Tables []string
+	// DataOnly leaves out the schema
+	DataOnly bool
+	// SchemaOnly leaves out the rows
+	SchemaOnly bool
+}
+
+// schemaObject is an entry of sqlite_master
+type schemaObject struct {
+	Type  string
+	Name  string
+	Table string
+	SQL   string
+}
+
+// Dump writes the database as an SQL script like the sqlite3 .dump
+// command: tables in foreign key order with their rows, then indexes,
+// views and triggers, in one transaction. The database is read in a single
+// read transaction, so the dump is a consistent snapshot.
+func Dump(db *sql.DB, w io.Writer, opts DumpOptions) error {
+	if opts.DataOnly && opts.SchemaOnly {
+		return fmt.Errorf("a dump cannot be both data-only and schema-only")
+	}
+	tx, err := db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	return dump(tx, w, opts)
+}
+
+// dump writes the dump of the database tx reads
+func dump(tx querier, w io.Writer, opts DumpOptions) error {
+	objects, err := loadSchemaObjects(tx)
+	if err != nil {
+		return err
+	}
+	objects, err = selectObjects(objects, opts.Tables)
+	if err != nil {
+		return err
+	}
+
+	var tables, indexes, triggers, views []schemaObject
+	hasSequence := false
+	for _, obj := range objects {
+		switch {
+		case obj.Name == "sqlite_sequence":
+			hasSequence = true
+		case strings.HasPrefix(obj.Name, "sqlite_"):
+			// Internal tables are created by SQLite itself
+		case obj.Type == "table":
+			tables = append(tables, obj)
+		case obj.Type == "index":
+			indexes = append(indexes, obj)
+		case obj.Type == "trigger":
+			triggers = append(triggers, obj)
+		case obj.Type == "view":
+			views = append(views, obj)
+		}
+	}
+	tables = withoutShadowTables(tables)
+	if tables, err = sortByForeignKeys(tx, tables); err != nil {
+		return err
+	}
+	views = sortViews(views)
+
+	out := bufio.NewWriter(w)
+	out.WriteString("PRAGMA foreign_keys=OFF;\nBEGIN TRANSACTION;\n")
+	for _, table := range tables {
+		if !opts.DataOnly {
+			out.WriteString(table.SQL + ";\n")
+		}
+		if !opts.SchemaOnly {
+			if err := dumpRows(tx, out, table.Name); err != nil {
+				return fmt.Errorf("table %s: %w", table.Name, err)
+			}
+		}
+	}
+	if hasSequence && !opts.SchemaOnly {
+		if err := dumpSequence(tx, out, tables); err != nil {
+			return err
+		}
+	}
+	if !opts.DataOnly {
+		// INSTEAD OF triggers need their view
+		for _, group := range [][]schemaObject{indexes, views, triggers} {
+			for _, obj := range group {
+				out.WriteString(obj.SQL + ";\n")
+			}
+		}
+	}
+	out.WriteString("COMMIT;\n")
+	return out.Flush()
+}
+
+// loadSchemaObjects reads sqlite_master in creation order. Automatic
+// indexes have no SQL and are left out.
+func loadSchemaObjects(db querier) ([]schemaObject, error) {
+	rows, err := db.Query(`SELECT type, name, tbl_name, sql FROM sqlite_master WHERE sql IS NOT NULL ORDER BY rowid`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var objects []schemaObject
+	for rows.Next() {
+		var obj schemaObject
+		if err := rows.Scan(&obj.Type, &obj.Name, &obj.Table, &obj.SQL); err != nil {
+			return nil, err
+		}
+		objects = append(objects, obj)
+	}
+	return objects, rows.Err()
+}
+
+// selectObjects keeps the named tables and views and the indexes and
+// triggers that belong to them
+func selectObjects(objects []schemaObject, names []string) ([]schemaObject, error) {
+	if len(names) == 0 {
+		return objects, nil
+	}
+	wanted := map[string]bool{}
+	for _, name := range names {
+		found := false
+		for _, obj := range objects {
+			if (obj.Type == "table" || obj.Type == "view") && strings.EqualFold(obj.Name, name) {
+				wanted[strings.ToLower(obj.Name)] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no such table: %s", name)
+		}
+	}
+
+	var selected []schemaObject
+	for _, obj := range objects {
+		if wanted[strings.ToLower(obj.Table)] || obj.Name == "sqlite_sequence" {
+			selected = append(selected, obj)
+		}
+	}
+	return selected, nil
+}
+
+// shadowSuffixes name the tables FTS and R*Tree virtual tables keep their
+// data in
+var shadowSuffixes = []string{"content", "data", "idx", "docsize", "config",
+	"segments", "segdir", "stat", "node", "parent", "rowid"}
+
+// withoutShadowTables drops the tables a virtual table keeps its data in,
+// which creating the virtual table recreates
+func withoutShadowTables(tables []schemaObject) []schemaObject {
+	shadows := map[string]bool{}
+	for _, t := range tables {
+		if strings.HasPrefix(strings.ToUpper(t.SQL), "CREATE VIRTUAL TABLE") {
+			for _, suffix := range shadowSuffixes {
+				shadows[strings.ToLower(t.Name+"_"+suffix)] = true
+			}
+		}
+	}
+	var kept []schemaObject
+	for _, t := range tables {
+		if !shadows[strings.ToLower(t.Name)] {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+// sortByForeignKeys orders tables so referenced tables come before the
+// tables referencing them. Tables in a reference cycle keep their order.
+func sortByForeignKeys(db querier, tables []schemaObject) ([]schemaObject, error) {
+	index := map[string]int{}
+	for i, t := range tables {
+		index[strings.ToLower(qualifyTable("main", t.Name))] = i
+	}
+	deps := make([][]int, len(tables))
+	for i, t := range tables {
+		fks, err := loadForeignKeys(db, qualifyTable("main", t.Name))
+		if err != nil {
+			return nil, err
+		}
+		for _, fk := range fks {
+			if j, ok := index[strings.ToLower(fk.RefTable)]; ok && j != i {
+				deps[i] = append(deps[i], j)
+			}
+		}
+	}
+	return dependencyOrder(tables, deps), nil
+}
+
+// sortViews orders views so views used by other views come first
+func sortViews(views []schemaObject) []schemaObject {
+	index := map[string]int{}
+	for i, v := range views {
+		index[strings.ToLower(v.Name)] = i
+	}
+	deps := make([][]int, len(views))
+	for i, v := range views {
+		for _, t := range significantTokens(lexSQL(v.SQL)) {
+			if j, ok := index[strings.ToLower(t.Name())]; ok && j != i {
+				deps[i] = append(deps[i], j)
+			}
+		}
+	}
+	return dependencyOrder(views, deps)
+}
+
+// dependencyOrder sorts objects after the objects they depend on, keeping
+// the original order otherwise
+func dependencyOrder(objects []schemaObject, deps [][]int) []schemaObject {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]int, len(objects))
+	var order []schemaObject
+	var visit func(i int)
+	visit = func(i int) {
+		if state[i] != unvisited {
+			// Done, or a cycle
+			return
+		}
+		state[i] = visiting
+		sorted := append([]int(nil), deps[i]...)
+		sort.Ints(sorted)
+		for _, j := range sorted {
+			visit(j)
+		}
+		state[i] = done
+		order = append(order, objects[i])
+	}
+	for i := range objects {
+		visit(i)
+	}
+	return order
+}
+
+// dumpRows writes the rows of a table as INSERT statements. Generated and
+// hidden columns are left out, naming the other columns.
+func dumpRows(db querier, w io.Writer, table string) error {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_xinfo(%s)", quoteIdent(table)))
+	if err != nil {
+		return err
+	}
+	var columns []string
+	generated := false
+	for rows.Next() {
+		var cid, notNull, pk, hidden int
+		var name, typ string
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &dflt, &pk, &hidden); err != nil {
+			rows.Close()
+			return err
+		}
+		if hidden == 0 {
+			// +column has no declared type, so the driver returns dates
+			// as stored instead of parsing them
+			columns = append(columns, "+"+quoteIdent(name)+" AS "+quoteIdent(name))
+		} else {
+			generated = true
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	opts := DefaultExportOptions()
+	opts.Format = FormatSQL
+	opts.Table = qualifyTable("main", table)
+	opts.ColumnList = generated
+	return dumpQuery(db, w, fmt.Sprintf("SELECT %s FROM %s", strings.Join(columns, ", "), quoteIdent(table)), opts)
+}
+
+// dumpSequence writes the AUTOINCREMENT counters of the dumped tables.
+// Tables without a counter are left out, as the database the dump is
+// restored into may have no sqlite_sequence table at all.
+func dumpSequence(db querier, w io.Writer, tables []schemaObject) error {
+	rows, err := db.Query("SELECT name FROM sqlite_sequence")
+	if err != nil {
+		return err
+	}
+	counters := map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		counters[name] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var names []string
+	for _, t := range tables {
+		if counters[t.Name] {
+			names = append(names, sqlLiteral(t.Name))
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	list := strings.Join(names, ", ")
+	io.WriteString(w, fmt.Sprintf("DELETE FROM sqlite_sequence WHERE name IN (%s);\n", list))
+	opts := DefaultExportOptions()
+	opts.Format = FormatSQL
+	opts.Table = "sqlite_sequence"
+	opts.ColumnList = false
+	return dumpQuery(db, w, fmt.Sprintf("SELECT * FROM sqlite_sequence WHERE name IN (%s)", list), opts)
+}
+
+// dumpQuery writes the rows of a query the dump itself built
+func dumpQuery(db querier, w io.Writer, query string, opts ExportOptions) error {
+	rows, err := db.Query(query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	_, err = ExportRows(w, rows, opts)
+	return err
+}
+
+// Restore runs a dump on one connection. When a statement fails an open
+// transaction is rolled back and the error names the statement.
+func Restore(db *sql.DB, r io.Reader) (int, error) {
+	script, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	n := 0
+	for i, stmt := range splitStatements(string(script)) {
+		if len(significantTokens(lexSQL(stmt))) == 0 {
+			continue
+		}
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			conn.ExecContext(ctx, "ROLLBACK")
+			return n, fmt.Errorf("statement %d (%s): %w", i+1, summarizeStatement(stmt), err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// summarizeStatement shortens a statement for an error message
+func summarizeStatement(stmt string) string {
+	stmt = strings.Join(strings.Fields(stmt), " ")
+	if len(stmt) > 60 {
+		stmt = stmt[:57] + "..."
+	}
+	return stmt
+}
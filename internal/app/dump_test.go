@@ -0,0 +1,245 @@
+package app
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"modernc.org/sqlite"
+)
+
+// openTestDatabase creates a database file in a temporary directory and
+// runs setup on it
+func openTestDatabase(t *testing.T, setup string) *sql.DB {
+	t.Helper()
+	db, err := OpenDatabase(filepath.Join(t.TempDir(), "test.db"), DefaultConnectionOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.Exec(setup); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestDumpKeepsDates(t *testing.T) {
+	db := openTestDatabase(t, `CREATE TABLE ev(at DATETIME, d DATE, ts TIMESTAMP);
+		INSERT INTO ev VALUES ('2024-01-02 03:04:05.123+02:00', '2024-05-06T00:00:00Z', 'not a date')`)
+
+	var out bytes.Buffer
+	if err := Dump(db, &out, DumpOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	want := `INSERT INTO "ev" VALUES ('2024-01-02 03:04:05.123+02:00', '2024-05-06T00:00:00Z', 'not a date');`
+	if !strings.Contains(out.String(), want) {
+		t.Errorf("dump lacks %s:\n%s", want, out.String())
+	}
+}
+
+func TestExportKeepsDates(t *testing.T) {
+	db := openTestDatabase(t, `CREATE TABLE ev(id INTEGER, at DATETIME);
+		INSERT INTO ev VALUES (1, '2024-01-02 03:04:05.123+02:00'), (2, '2024-05-06'), (3, 20240101);
+		CREATE VIEW late AS SELECT at AS day FROM ev WHERE id > 1`)
+
+	tests := []struct {
+		name   string
+		format ExportFormat
+		query  string
+		want   string
+	}{
+		{"csv", FormatCSV, "SELECT at FROM ev WHERE id < 3 ORDER BY id", "at\n2024-01-02 03:04:05.123+02:00\n2024-05-06\n"},
+		{"ndjson", FormatNDJSON, "SELECT * FROM ev WHERE id = 2;", `{"id":2,"at":"2024-05-06"}` + "\n"},
+		{"trailing comment", FormatCSV, "SELECT at FROM ev WHERE id = 2 -- second", "at\n2024-05-06\n"},
+		{"order kept", FormatCSV, "SELECT at FROM ev WHERE id < 3 ORDER BY id DESC", "at\n2024-05-06\n2024-01-02 03:04:05.123+02:00\n"},
+		{"affinity kept", FormatCSV, "SELECT id FROM ev WHERE at = '20240101'", "id\n3\n"},
+		{"alias and join", FormatCSV, "SELECT e.at FROM ev AS e JOIN ev f ON f.id = e.id WHERE e.id = 2", "at\n2024-05-06\n"},
+		{"view", FormatCSV, "SELECT day FROM late WHERE day LIKE '2024-%'", "day\n2024-05-06\n"},
+		{"own CTE", FormatCSV, "WITH RECURSIVE n(i) AS (SELECT 2) SELECT at FROM ev, n WHERE id = i", "at\n2024-05-06\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultExportOptions()
+			opts.Format = tt.format
+			var out bytes.Buffer
+			if _, err := ExportQuery(db, &out, tt.query, opts); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("got %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRunScriptKeepsDates(t *testing.T) {
+	db := openTestDatabase(t, `CREATE TABLE ev(at DATE);
+		INSERT INTO ev VALUES ('2024-05-06T07:08:09Z')`)
+
+	opts := DefaultExportOptions()
+	opts.Format = FormatCSV
+	var out bytes.Buffer
+	if err := RunScript(db, &out, "SELECT at FROM ev; SELECT at AS again FROM ev", opts); err != nil {
+		t.Fatal(err)
+	}
+	if want := "at\n2024-05-06T07:08:09Z\nagain\n2024-05-06T07:08:09Z\n"; out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+}
+
+// calls counts the calls of the SQL function test_calls()
+var (
+	calls         int
+	registerCalls sync.Once
+)
+
+func TestStoredQueryRunsOnce(t *testing.T) {
+	registerCalls.Do(func() {
+		sqlite.MustRegisterScalarFunction("test_calls", 0, func(*sqlite.FunctionContext, []driver.Value) (driver.Value, error) {
+			calls++
+			return int64(calls), nil
+		})
+	})
+	db := openTestDatabase(t, `CREATE TABLE ev(at DATE);
+		INSERT INTO ev VALUES ('2024-05-06T07:08:09Z')`)
+
+	calls = 0
+	opts := DefaultExportOptions()
+	opts.Format = FormatCSV
+	var out bytes.Buffer
+	if err := RunScript(db, &out, "SELECT at, test_calls() AS n FROM ev", opts); err != nil {
+		t.Fatal(err)
+	}
+	if want := "at,n\n2024-05-06T07:08:09Z,1\n"; out.String() != want || calls != 1 {
+		t.Errorf("got %q after %d calls, want %q after 1", out.String(), calls, want)
+	}
+}
+
+const roundTripSchema = `CREATE TABLE customers(id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT, joined DATE);
+	CREATE TABLE orders(id INTEGER PRIMARY KEY, customer INTEGER REFERENCES customers(id), total REAL, note BLOB);
+	CREATE TABLE tags(name TEXT);
+	CREATE TABLE "odd.name"(v);
+	INSERT INTO "odd.name" VALUES (1);
+	CREATE INDEX orders_customer ON orders(customer);
+	CREATE VIEW big_orders AS SELECT * FROM orders WHERE total > 10;
+	CREATE TRIGGER tag_upper AFTER INSERT ON tags BEGIN UPDATE tags SET name = upper(name) WHERE rowid = new.rowid; END;
+	CREATE VIEW tag_names AS SELECT name FROM tags;
+	CREATE TRIGGER tag_names_insert INSTEAD OF INSERT ON tag_names BEGIN INSERT INTO tags VALUES (new.name); END;
+	INSERT INTO customers(name, joined) VALUES ('Ann', '2024-01-02T03:04:05Z'), ('Bob''s', NULL);
+	DELETE FROM customers WHERE name = 'Bob''s';
+	INSERT INTO orders VALUES (1, 1, 12.5, x'00ff'), (2, 1, 3, NULL);
+	INSERT INTO tags VALUES ('new')`
+
+func TestDumpRestoreRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		tables []string
+	}{
+		{"everything", nil},
+		{"with counter", []string{"customers"}},
+		{"without counter", []string{"tags"}},
+		{"view and its tables", []string{"orders", "big_orders"}},
+		{"dotted name", []string{"odd.name"}},
+		{"trigger on a view", []string{"tags", "tag_names"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := openTestDatabase(t, roundTripSchema)
+			opts := DumpOptions{Tables: tt.tables}
+			var dump bytes.Buffer
+			if err := Dump(source, &dump, opts); err != nil {
+				t.Fatal(err)
+			}
+
+			target := openTestDatabase(t, "")
+			if _, err := Restore(target, bytes.NewReader(dump.Bytes())); err != nil {
+				t.Fatalf("restore: %v\n%s", err, dump.String())
+			}
+			var again bytes.Buffer
+			if err := Dump(target, &again, opts); err != nil {
+				t.Fatal(err)
+			}
+			if again.String() != dump.String() {
+				t.Errorf("restored database dumps differently:\n%s\nwant:\n%s", again.String(), dump.String())
+			}
+		})
+	}
+}
+
+func TestExportTableOfAttachedDatabase(t *testing.T) {
+	other := filepath.Join(t.TempDir(), "other.db")
+	setup := openTestDatabase(t, "")
+	if _, err := setup.Exec("ATTACH DATABASE ? AS other; CREATE TABLE other.t(v); INSERT INTO other.t VALUES ('x')", other); err != nil {
+		t.Fatal(err)
+	}
+	db := openTestDatabase(t, "")
+	if err := AttachDatabase(db, Attachment{Alias: "other", Path: other}); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := DefaultExportOptions()
+	opts.Format = FormatSQL
+	var out bytes.Buffer
+	if _, err := ExportTable(db, &out, "other.t", opts); err != nil {
+		t.Fatal(err)
+	}
+	if want := `INSERT INTO "other"."t" ("v") VALUES ('x');` + "\n"; out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+}
+
+func TestDumpSchemaThenData(t *testing.T) {
+	source := openTestDatabase(t, roundTripSchema)
+	var schema, data, full bytes.Buffer
+	for _, d := range []struct {
+		out  *bytes.Buffer
+		opts DumpOptions
+	}{
+		{&schema, DumpOptions{SchemaOnly: true}},
+		{&data, DumpOptions{DataOnly: true}},
+		{&full, DumpOptions{}},
+	} {
+		if err := Dump(source, d.out, d.opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if strings.Contains(schema.String(), "\nINSERT INTO") || strings.Contains(data.String(), "CREATE ") {
+		t.Fatalf("schema and data are mixed:\n%s\n%s", schema.String(), data.String())
+	}
+
+	target := openTestDatabase(t, "")
+	for _, dump := range []*bytes.Buffer{&schema, &data} {
+		if _, err := Restore(target, bytes.NewReader(dump.Bytes())); err != nil {
+			t.Fatalf("restore: %v\n%s", err, dump.String())
+		}
+	}
+	var again bytes.Buffer
+	if err := Dump(target, &again, DumpOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if again.String() != full.String() {
+		t.Errorf("restored database dumps differently:\n%s\nwant:\n%s", again.String(), full.String())
+	}
+}
+
+func TestDumpRejectsOptions(t *testing.T) {
+	db := openTestDatabase(t, roundTripSchema)
+	tests := []struct {
+		name string
+		opts DumpOptions
+	}{
+		{"data and schema only", DumpOptions{DataOnly: true, SchemaOnly: true}},
+		{"unknown table", DumpOptions{Tables: []string{"missing"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Dump(db, &bytes.Buffer{}, tt.opts); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -2,19 +2,25 @@ package app
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
-	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/textarea"
 )
 
 type EditCellModel struct {
 	Shared       *SharedData
 	rowIndex     int
 	colIndex     int
-	input        textinput.Model
+	column       ColumnInfo
+	input        textarea.Model
+	isNull       bool
+	stashed      string // text hidden while the value is set to NULL
+	preview      StoredValue
+	err          error
 	blinkState   bool
 	keyMap       EditCellKeyMap
 	help         help.Model
@@ -45,19 +51,23 @@ func NewEditCellModel(shared *SharedData, rowIndex, colIndex int, opts ...EditCe
 		value = shared.FilteredData[rowIndex][colIndex]
 	}
 
-	input := textinput.New()
-	input.SetValue(value)
-	input.Width = 50
+	input := textarea.New()
+	input.Placeholder = NullText
+	input.ShowLineNumbers = false
+	input.CharLimit = 0
+	input.MaxHeight = 0
 	input.Focus()
 
 	m := &EditCellModel{
 		Shared:     shared,
 		rowIndex:   rowIndex,
 		colIndex:   colIndex,
+		column:     shared.ColumnInfoFor(colIndex),
 		input:      input,
+		isNull:     value == NullValue,
 		blinkState: true,
-		keyMap:     DefaultEditCellKeyMap(),
-		help:       help.New(),
+		keyMap:     shared.keyMaps().EditCell,
+		help:       shared.theme().newHelp(),
 		focused:    true,
 		id:         nextID(),
 	}
@@ -67,6 +77,26 @@ func NewEditCellModel(shared *SharedData, rowIndex, colIndex int, opts ...EditCe
 		opt(m)
 	}
 
+	// The textarea follows the edit cell key map
+	km := &m.input.KeyMap
+	km.CharacterBackward = m.keyMap.CursorLeft
+	km.CharacterForward = m.keyMap.CursorRight
+	km.WordBackward = m.keyMap.WordLeft
+	km.WordForward = m.keyMap.WordRight
+	km.LineStart = m.keyMap.LineStart
+	km.LineEnd = m.keyMap.LineEnd
+	km.DeleteWordBackward = m.keyMap.DeleteWord
+	km.DeleteCharacterBackward = m.keyMap.DeleteChar
+	km.InsertNewline = m.keyMap.NewLine
+	km.LineNext = key.NewBinding(key.WithKeys("down"))
+	km.LinePrevious = key.NewBinding(key.WithKeys("up"))
+
+	if !m.isNull {
+		m.input.SetValue(value)
+	}
+	m.layout()
+	m.validate()
+
 	return m
 }
 
@@ -94,7 +124,7 @@ func (m EditCellModel) Focused() bool {
 
 func (m *EditCellModel) Init() tea.Cmd {
 	return tea.Batch(
-		textinput.Blink,
+		textarea.Blink,
 		blinkCmd(),
 	)
 }
@@ -115,42 +145,162 @@ func (m *EditCellModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.blinkState = !m.blinkState
 		cmds = append(cmds, blinkCmd())
 
+	case UpdateCellFailedMsg:
+		if msg.RowIndex == m.rowIndex && msg.ColIndex == m.colIndex {
+			m.err = msg.Err
+		}
+		return m, nil
+
+	case ExternalEditorMsg:
+		if msg.ID != m.id {
+			return m, nil
+		}
+		if msg.Err != nil {
+			m.err = msg.Err
+			return m, nil
+		}
+		m.err = nil
+		m.isNull = false
+		m.input.SetValue(msg.Value)
+		m.validate()
+		return m, nil
+
 	case tea.KeyMsg:
 		switch {
+		case key.Matches(msg, m.keyMap.OpenEditor):
+			return m, openExternalEditor(m.id, m.input.Value(), valueExtension(m.input.Value()))
+
 		case key.Matches(msg, m.keyMap.Save):
 			return m, func() tea.Msg {
 				return UpdateCellMsg{
 					RowIndex: m.rowIndex,
 					ColIndex: m.colIndex,
-					Value:    m.input.Value(),
+					Value:    m.value(),
 				}
 			}
 
+		case key.Matches(msg, m.keyMap.SetNull):
+			m.toggleNull()
+			return m, nil
+
+		case key.Matches(msg, m.keyMap.CopySQL):
+			return m, func() tea.Msg { return RunCommandMsg{Line: "yank sql"} }
+
 		case key.Matches(msg, m.keyMap.Cancel):
-			return m, func() tea.Msg {
-				return SwitchToRowDetailMsg{RowIndex: m.rowIndex}
-			}
+			return m, func() tea.Msg { return NavigateBackMsg{} }
+		}
+
+		// Typing replaces a NULL value
+		if m.isNull && (msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace) {
+			m.isNull = false
+			m.stashed = ""
 		}
 	}
 
 	// Update the input for all other messages
+	before := m.input.Value()
 	var cmd tea.Cmd
 	m.input, cmd = m.input.Update(msg)
 	if cmd != nil {
 		cmds = append(cmds, cmd)
 	}
+	if m.input.Value() != before {
+		m.isNull = false
+		m.err = nil
+		m.validate()
+	}
 
 	return m, tea.Batch(cmds...)
 }
 
+// value returns the edited value, NullValue when set to NULL
+func (m *EditCellModel) value() string {
+	if m.isNull {
+		return NullValue
+	}
+	return m.input.Value()
+}
+
+// toggleNull switches between NULL and the text that was being edited
+func (m *EditCellModel) toggleNull() {
+	if m.isNull {
+		m.isNull = false
+		m.input.SetValue(m.stashed)
+		m.stashed = ""
+	} else {
+		m.isNull = true
+		m.stashed = m.input.Value()
+		m.input.Reset()
+	}
+	m.err = nil
+	m.validate()
+}
+
+// validate checks the value against the column type and updates the preview
+func (m *EditCellModel) validate() {
+	m.preview = convertValue(m.column, m.value())
+}
+
+// layout sizes the editor to the terminal
+func (m *EditCellModel) layout() {
+	if m.isNull {
+		m.input.Placeholder = NullText
+	} else {
+		m.input.Placeholder = "(empty string)"
+	}
+	m.input.SetWidth(Max(20, m.Shared.Width-4))
+	m.input.SetHeight(Max(3, m.Shared.Height-14))
+}
+
+// columnDescription summarises the declared type and constraints of the column
+func (m *EditCellModel) columnDescription() string {
+	declType := m.column.Type
+	if declType == "" {
+		declType = "any type"
+	}
+	parts := []string{fmt.Sprintf("%s (%s affinity)", declType, m.column.Affinity())}
+	if m.column.PrimaryKey > 0 {
+		parts = append(parts, "PRIMARY KEY")
+	}
+	if m.column.NotNull {
+		parts = append(parts, "NOT NULL")
+	}
+	if m.column.Default.Valid {
+		parts = append(parts, "DEFAULT "+m.column.Default.String)
+	}
+	return strings.Join(parts, " • ")
+}
+
 func (m *EditCellModel) View() string {
+	theme := m.Shared.theme()
 	columnName := ""
 	if m.colIndex < len(m.Shared.Columns) {
 		columnName = m.Shared.Columns[m.colIndex]
 	}
 
-	content := fmt.Sprintf("%s\n\n", TitleStyle.Render(fmt.Sprintf("Edit Cell: %s", columnName)))
-	content += fmt.Sprintf("Value: %s\n\n", m.input.View())
+	m.layout()
+
+	content := fmt.Sprintf("%s\n\n", theme.Title.Render(fmt.Sprintf("Edit Cell: %s", columnName)))
+	content += theme.Help.Render(m.columnDescription()) + "\n\n"
+	content += m.input.View() + "\n\n"
+
+	switch {
+	case m.preview.Type == "NULL":
+		content += "Stored as: NULL\n\n"
+	default:
+		content += fmt.Sprintf("Stored as: %s %s\n\n", m.preview.Type, TruncateString(strings.ReplaceAll(m.preview.Display, "\n", "⏎"), Max(10, m.Shared.Width-20)))
+	}
+	if m.preview.Warning != "" {
+		content += theme.Help.Render("Note: "+m.preview.Warning) + "\n\n"
+	}
+	if m.err != nil {
+		label := "Error"
+		if isConstraintError(m.err) {
+			label = "Constraint violation"
+		}
+		content += theme.Error.Render(fmt.Sprintf("%s: %v", label, m.err)) + "\n"
+		content += theme.Help.Render("Fix the value and press enter to retry, or esc to cancel") + "\n\n"
+	}
 	
 	if m.showFullHelp {
 		content += m.help.FullHelpView(m.keyMap.FullHelp())
@@ -159,4 +309,4 @@ func (m *EditCellModel) View() string {
 	}
 
 	return content
-}
\ No newline at end of file
+}
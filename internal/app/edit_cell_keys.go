@@ -14,6 +14,10 @@ type EditCellKeyMap struct {
 	LineEnd       key.Binding
 	DeleteWord    key.Binding
 	DeleteChar    key.Binding
+	OpenEditor    key.Binding
+	NewLine       key.Binding
+	SetNull       key.Binding
+	CopySQL       key.Binding
 	ToggleHelp    key.Binding
 }
 
@@ -60,6 +64,22 @@ func DefaultEditCellKeyMap() EditCellKeyMap {
 			key.WithKeys("backspace"),
 			key.WithHelp("backspace", "delete char"),
 		),
+		OpenEditor: key.NewBinding(
+			key.WithKeys("ctrl+o"),
+			key.WithHelp("ctrl+o", "open in $EDITOR"),
+		),
+		NewLine: key.NewBinding(
+			key.WithKeys("alt+enter", "ctrl+j"),
+			key.WithHelp("alt+enter/ctrl+j", "new line"),
+		),
+		SetNull: key.NewBinding(
+			key.WithKeys("ctrl+n"),
+			key.WithHelp("ctrl+n", "set/unset NULL"),
+		),
+		CopySQL: key.NewBinding(
+			key.WithKeys("ctrl+y"),
+			key.WithHelp("ctrl+y", "copy UPDATE statement"),
+		),
 		ToggleHelp: key.NewBinding(
 			key.WithKeys("ctrl+g"),
 			key.WithHelp("ctrl+g", "toggle help"),
@@ -69,13 +89,13 @@ func DefaultEditCellKeyMap() EditCellKeyMap {
 
 // ShortHelp returns keybindings to be shown in the mini help view
 func (k EditCellKeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Save, k.Cancel, k.ToggleHelp}
+	return []key.Binding{k.Save, k.Cancel, k.NewLine, k.SetNull, k.OpenEditor, k.ToggleHelp}
 }
 
 // FullHelp returns keybindings for the expanded help view
 func (k EditCellKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Save, k.Cancel},
+		{k.Save, k.Cancel, k.NewLine, k.SetNull, k.OpenEditor, k.CopySQL},
 		{k.CursorLeft, k.CursorRight, k.WordLeft, k.WordRight},
 		{k.LineStart, k.LineEnd, k.DeleteWord, k.DeleteChar, k.ToggleHelp},
 	}
@@ -0,0 +1,579 @@
+package app
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportFormat names a file format rows can be exported to
+type ExportFormat string
+
+const (
+	FormatCSV    ExportFormat = "csv"
+	FormatTSV    ExportFormat = "tsv"
+	FormatJSON   ExportFormat = "json"
+	FormatNDJSON ExportFormat = "ndjson"
+	// FormatSQL writes INSERT statements
+	FormatSQL      ExportFormat = "sql"
+	FormatMarkdown ExportFormat = "markdown"
+	// FormatASCII writes a table drawn with +, - and |
+	FormatASCII ExportFormat = "ascii"
+	FormatHTML  ExportFormat = "html"
+)
+
+// ExportFormats lists the supported formats
+func ExportFormats() []ExportFormat {
+	return []ExportFormat{FormatCSV, FormatTSV, FormatJSON, FormatNDJSON, FormatSQL, FormatMarkdown, FormatASCII, FormatHTML}
+}
+
+// Extension returns the file extension of the format, without the dot
+func (f ExportFormat) Extension() string {
+	switch f {
+	case FormatMarkdown:
+		return "md"
+	case FormatASCII:
+		return "txt"
+	}
+	return string(f)
+}
+
+// BlobEncoding says how BLOB values are written
+type BlobEncoding string
+
+const (
+	BlobHex    BlobEncoding = "hex"
+	BlobBase64 BlobEncoding = "base64"
+	// BlobText writes the bytes unchanged
+	BlobText BlobEncoding = "text"
+)
+
+// ExportOptions configures how rows are written
+type ExportOptions struct {
+	Format ExportFormat
+	// Header writes the column names first in CSV and TSV
+	Header bool
+	// Delimiter separates CSV fields
+	Delimiter rune
+	// Null is written for NULL in CSV and TSV; JSON uses null
+	Null string
+	Blob BlobEncoding
+	// Table is the table INSERT statements write to, schema.table for a
+	// table of an attached database
+	Table string
+	// ColumnList names the columns in INSERT statements
+	ColumnList bool
+	// Batch is the number of rows per INSERT statement
+	Batch int
+}
+
+// DefaultExportOptions returns CSV with a header, commas, empty NULLs and
+// hex encoded BLOBs. INSERT statements name their columns and insert one
+// row each.
+func DefaultExportOptions() ExportOptions {
+	return ExportOptions{
+		Format:     FormatCSV,
+		Header:     true,
+		Delimiter:  ',',
+		Null:       "",
+		Blob:       BlobHex,
+		ColumnList: true,
+		Batch:      1,
+	}
+}
+
+// ParseExportFormat looks a format up by name or file extension
+func ParseExportFormat(name string) (ExportFormat, error) {
+	name = strings.ToLower(strings.TrimPrefix(name, "."))
+	for _, f := range ExportFormats() {
+		if string(f) == name || f.Extension() == name {
+			return f, nil
+		}
+	}
+	switch name {
+	case "jsonl":
+		return FormatNDJSON, nil
+	case "insert":
+		return FormatSQL, nil
+	case "htm":
+		return FormatHTML, nil
+	}
+	return "", fmt.Errorf("unknown export format %q", name)
+}
+
+// rowWriter writes exported rows in one format
+type rowWriter interface {
+	begin(columns []string) error
+	row(values []any) error
+	end() error
+}
+
+func newRowWriter(w io.Writer, opts ExportOptions) (rowWriter, error) {
+	switch opts.Format {
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if opts.Delimiter != 0 {
+			cw.Comma = opts.Delimiter
+		}
+		return &csvWriter{w: cw, opts: opts}, nil
+	case FormatTSV:
+		return &tsvWriter{w: bufio.NewWriter(w), opts: opts}, nil
+	case FormatJSON, FormatNDJSON:
+		return &jsonWriter{w: bufio.NewWriter(w), opts: opts, lines: opts.Format == FormatNDJSON}, nil
+	case FormatSQL:
+		if opts.Table == "" {
+			return nil, errors.New("INSERT statements need a table name")
+		}
+		return &insertWriter{w: bufio.NewWriter(w), opts: opts}, nil
+	case FormatMarkdown, FormatASCII:
+		return &gridWriter{w: bufio.NewWriter(w), opts: opts}, nil
+	case FormatHTML:
+		return &htmlWriter{w: bufio.NewWriter(w), opts: opts}, nil
+	}
+	return nil, fmt.Errorf("unknown export format %q", opts.Format)
+}
+
+// ExportRows streams the rows of a query to w and returns how many were
+// written. The rows are closed.
+func ExportRows(w io.Writer, rows *sql.Rows, opts ExportOptions) (int, error) {
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	rw, err := newRowWriter(w, opts)
+	if err != nil {
+		return 0, err
+	}
+	if err := rw.begin(columns); err != nil {
+		return 0, err
+	}
+
+	n := 0
+	values := make([]any, len(columns))
+	ptrs := make([]any, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return n, err
+		}
+		if err := rw.row(values); err != nil {
+			return n, err
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return n, err
+	}
+	return n, rw.end()
+}
+
+// exportCells writes rows already loaded as cell text, typing the values
+// from the column definitions
+func exportCells(w io.Writer, grid gridData, rows [][]string, opts ExportOptions) (int, error) {
+	rw, err := newRowWriter(w, opts)
+	if err != nil {
+		return 0, err
+	}
+	columns := make([]string, len(grid.columns))
+	for i, col := range grid.columns {
+		columns[i] = col.Name
+	}
+	if err := rw.begin(columns); err != nil {
+		return 0, err
+	}
+	for n, row := range rows {
+		values := make([]any, len(row))
+		for i, cell := range row {
+			switch {
+			case cell == NullValue:
+				values[i] = nil
+			case numericCell(grid.column(i), cell):
+				values[i] = json.Number(cell)
+			default:
+				values[i] = cell
+			}
+		}
+		if err := rw.row(values); err != nil {
+			return n, err
+		}
+	}
+	return len(rows), rw.end()
+}
+
+// exportText converts a value to the text written in CSV and TSV
+func exportText(v any, opts ExportOptions) string {
+	switch v := v.(type) {
+	case nil:
+		return opts.Null
+	case []byte:
+		return encodeBlob(v, opts.Blob)
+	case float64:
+		return formatReal(v)
+	case time.Time:
+		return v.Format(storedTimeFormat)
+	}
+	return fmt.Sprint(v)
+}
+
+func encodeBlob(b []byte, enc BlobEncoding) string {
+	switch enc {
+	case BlobBase64:
+		return base64.StdEncoding.EncodeToString(b)
+	case BlobText:
+		return string(b)
+	}
+	return hex.EncodeToString(b)
+}
+
+type csvWriter struct {
+	w    *csv.Writer
+	opts ExportOptions
+}
+
+func (c *csvWriter) begin(columns []string) error {
+	if !c.opts.Header {
+		return nil
+	}
+	return c.w.Write(columns)
+}
+
+func (c *csvWriter) row(values []any) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		record[i] = exportText(v, c.opts)
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) end() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// tsvWriter writes tab separated values, escaping tabs, newlines and
+// backslashes in the values
+type tsvWriter struct {
+	w    *bufio.Writer
+	opts ExportOptions
+}
+
+var tsvEscaper = strings.NewReplacer("\\", `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+func (t *tsvWriter) line(fields []string) error {
+	_, err := t.w.WriteString(strings.Join(fields, "\t") + "\n")
+	return err
+}
+
+func (t *tsvWriter) begin(columns []string) error {
+	if !t.opts.Header {
+		return nil
+	}
+	fields := make([]string, len(columns))
+	for i, col := range columns {
+		fields[i] = tsvEscaper.Replace(col)
+	}
+	return t.line(fields)
+}
+
+// row escapes the values but not the NULL text, so \N stays a NULL marker
+func (t *tsvWriter) row(values []any) error {
+	fields := make([]string, len(values))
+	for i, v := range values {
+		if v == nil {
+			fields[i] = t.opts.Null
+		} else {
+			fields[i] = tsvEscaper.Replace(exportText(v, t.opts))
+		}
+	}
+	return t.line(fields)
+}
+
+func (t *tsvWriter) end() error {
+	return t.w.Flush()
+}
+
+// jsonWriter writes an array of objects, or one object per line for
+// NDJSON, keeping the column order
+type jsonWriter struct {
+	w       *bufio.Writer
+	opts    ExportOptions
+	lines   bool
+	columns []string
+	rows    int
+}
+
+func (j *jsonWriter) begin(columns []string) error {
+	j.columns = make([]string, len(columns))
+	for i, col := range columns {
+		name, err := json.Marshal(col)
+		if err != nil {
+			return err
+		}
+		j.columns[i] = string(name)
+	}
+	if j.lines {
+		return nil
+	}
+	_, err := j.w.WriteString("[")
+	return err
+}
+
+func (j *jsonWriter) row(values []any) error {
+	var b strings.Builder
+	switch {
+	case j.lines:
+	case j.rows == 0:
+		b.WriteString("\n  ")
+	default:
+		b.WriteString(",\n  ")
+	}
+	b.WriteString("{")
+	for i, v := range values {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString(j.columns[i])
+		b.WriteString(":")
+		value, err := j.value(v)
+		if err != nil {
+			return err
+		}
+		b.WriteString(value)
+	}
+	b.WriteString("}")
+	if j.lines {
+		b.WriteString("\n")
+	}
+	j.rows++
+	_, err := j.w.WriteString(b.String())
+	return err
+}
+
+// value encodes one value; BLOBs become strings in the chosen encoding
+func (j *jsonWriter) value(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "null", nil
+	case []byte:
+		return j.value(encodeBlob(v, j.opts.Blob))
+	case float64:
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return strconv.Quote(formatReal(v)), nil
+		}
+	case time.Time:
+		return j.value(v.Format(storedTimeFormat))
+	}
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+func (j *jsonWriter) end() error {
+	if !j.lines {
+		closing := "]\n"
+		if j.rows > 0 {
+			closing = "\n]\n"
+		}
+		if _, err := j.w.WriteString(closing); err != nil {
+			return err
+		}
+	}
+	return j.w.Flush()
+}
+
+// ExportQuery streams the rows of a query to w. The query runs on a
+// query_only connection, so it cannot change the database.
+func ExportQuery(db *sql.DB, w io.Writer, query string, opts ExportOptions) (int, error) {
+	rows, release, err := queryStored(db, query)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+	return ExportRows(w, rows, opts)
+}
+
+// ExportTable streams every row of a table to w. INSERT statements write
+// to the same table unless opts names another.
+func ExportTable(db *sql.DB, w io.Writer, table string, opts ExportOptions) (int, error) {
+	if opts.Table == "" {
+		opts.Table = table
+	}
+	return ExportQuery(db, w, "SELECT * FROM "+quoteTable(table), opts)
+}
+
+// declaredTypes returns the declared types of the columns of rows, "" for
+// expressions. dates holds the types of the date columns storedQuery hid.
+func declaredTypes(rows *sql.Rows, dates map[string]string) []string {
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil
+	}
+	declared := make([]string, len(types))
+	for i, t := range types {
+		declared[i] = t.DatabaseTypeName()
+		if declared[i] == "" {
+			declared[i] = dates[strings.ToLower(t.Name())]
+		}
+	}
+	return declared
+}
+
+// queryStored runs a query like queryOnly, but returns the text of DATE,
+// DATETIME and TIMESTAMP columns as stored instead of parsed by the driver
+func queryStored(db *sql.DB, query string, args ...any) (*sql.Rows, func(), error) {
+	stored, _ := storedQuery(db, query)
+	rows, release, err := queryOnly(db, stored, args...)
+	if err != nil && stored != query {
+		return queryOnly(db, query, args...)
+	}
+	return rows, release, err
+}
+
+// storedQuery rewrites a SELECT so the driver returns its date columns as
+// stored. The driver parses the text of columns declared as a date into
+// time.Time, so every table or view the query names with such columns is
+// shadowed by a CTE of the same name selecting them with COLLATE BINARY,
+// which keeps their affinity but has no declared type. The query still
+// runs once and in its own order. dates maps the names of the hidden
+// columns to their declared types. Tables named with their schema, and
+// queries using rowid, are left as they are.
+func storedQuery(db querier, query string) (stored string, dates map[string]string) {
+	if len(splitStatements(query)) != 1 || !isSelect(query) {
+		return query, nil
+	}
+	tokens := significantTokens(lexSQL(query))
+	var names []string
+	seen := map[string]bool{}
+	for i, t := range tokens {
+		if t.Kind != tokenIdent && t.Kind != tokenQuotedIdent {
+			continue
+		}
+		switch strings.ToLower(t.Name()) {
+		case "rowid", "oid", "_rowid_":
+			return query, nil
+		}
+		if i > 0 && tokens[i-1].Text == "." || i+1 < len(tokens) && isCTEName(tokens[i+1:]) {
+			continue
+		}
+		if name := strings.ToLower(t.Name()); !seen[name] {
+			seen[name] = true
+			names = append(names, t.Name())
+		}
+	}
+
+	schemas, err := resolutionOrder(db)
+	if err != nil {
+		return query, nil
+	}
+	var ctes []string
+	dates = map[string]string{}
+	for _, name := range names {
+		if cte := shadowDates(db, schemas, name, dates); cte != "" {
+			ctes = append(ctes, cte)
+		}
+	}
+	if len(ctes) == 0 {
+		return query, nil
+	}
+
+	start := tokens[0].Start
+	if !tokens[0].IsKeyword("WITH") {
+		return query[:start] + "WITH " + strings.Join(ctes, ", ") + " " + query[start:], dates
+	}
+	// The CTEs go first, as the query's own CTEs may use them
+	end, with := tokens[0].End, "WITH "
+	if len(tokens) > 1 && tokens[1].IsKeyword("RECURSIVE") {
+		end, with = tokens[1].End, "WITH RECURSIVE "
+	}
+	return query[:start] + with + strings.Join(ctes, ", ") + "," + query[end:], dates
+}
+
+// isCTEName reports whether the tokens after a name make it the name of a
+// CTE or a function rather than a table
+func isCTEName(next []sqlToken) bool {
+	if next[0].Text == "(" {
+		return true
+	}
+	return len(next) > 1 && next[0].IsKeyword("AS") &&
+		(next[1].Text == "(" || next[1].IsKeyword("MATERIALIZED") || next[1].IsKeyword("NOT"))
+}
+
+// resolutionOrder returns the schemas in the order SQLite searches them
+// for a table named without one
+func resolutionOrder(db querier) ([]string, error) {
+	rows, err := db.Query(`SELECT name FROM pragma_database_list ORDER BY name != 'temp', seq`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schemas []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, name)
+	}
+	return schemas, rows.Err()
+}
+
+// shadowDates returns the CTE that hides the date columns of the table or
+// view a name refers to, adding their types to dates, or "" when it has
+// none. Virtual tables are left alone, as their hidden columns take
+// arguments.
+func shadowDates(db querier, schemas []string, name string, dates map[string]string) string {
+	for _, schema := range schemas {
+		rows, err := db.Query(fmt.Sprintf("PRAGMA %s.table_xinfo(%s)", quoteIdent(schema), quoteIdent(name)))
+		if err != nil {
+			return ""
+		}
+		var columns []string
+		found := map[string]string{}
+		virtual := false
+		for rows.Next() {
+			var cid, notNull, pk, hidden int
+			var col, typ string
+			var dflt sql.NullString
+			if err := rows.Scan(&cid, &col, &typ, &notNull, &dflt, &pk, &hidden); err != nil {
+				rows.Close()
+				return ""
+			}
+			switch {
+			case hidden == 1:
+				virtual = true
+			case isDateType(typ):
+				columns = append(columns, quoteIdent(col)+" COLLATE BINARY AS "+quoteIdent(col))
+				found[strings.ToLower(col)] = strings.ToUpper(typ)
+			default:
+				columns = append(columns, quoteIdent(col))
+			}
+		}
+		rows.Close()
+		if len(columns) == 0 {
+			// Not in this schema
+			continue
+		}
+		if virtual || len(found) == 0 {
+			return ""
+		}
+		for col, typ := range found {
+			dates[col] = typ
+		}
+		// The schema keeps the CTE from referring to itself
+		return fmt.Sprintf("%s AS NOT MATERIALIZED (SELECT %s FROM %s.%s)", quoteIdent(name), strings.Join(columns, ", "), quoteIdent(schema), quoteIdent(name))
+	}
+	return ""
+}
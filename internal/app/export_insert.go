@@ -0,0 +1,77 @@
+package app
+
+import (
+	"bufio"
+	"strings"
+)
+
+// insertWriter writes INSERT statements, batching several rows into one
+// statement when asked to
+type insertWriter struct {
+	w      *bufio.Writer
+	opts   ExportOptions
+	prefix string
+	rows   int
+}
+
+func (i *insertWriter) begin(columns []string) error {
+	i.prefix = "INSERT INTO " + quoteTable(i.opts.Table)
+	if i.opts.ColumnList {
+		names := make([]string, len(columns))
+		for n, col := range columns {
+			names[n] = quoteIdent(col)
+		}
+		i.prefix += " (" + strings.Join(names, ", ") + ")"
+	}
+	i.prefix += " VALUES"
+	return nil
+}
+
+func (i *insertWriter) row(values []any) error {
+	literals := make([]string, len(values))
+	for n, v := range values {
+		literals[n] = i.literal(v)
+	}
+	tuple := "(" + strings.Join(literals, ", ") + ")"
+
+	var b strings.Builder
+	batch := max(i.opts.Batch, 1)
+	switch {
+	case batch == 1:
+		b.WriteString(i.prefix + " " + tuple + ";\n")
+	case i.rows%batch == 0:
+		if i.rows > 0 {
+			b.WriteString(";\n")
+		}
+		b.WriteString(i.prefix + "\n  " + tuple)
+	default:
+		b.WriteString(",\n  " + tuple)
+	}
+	i.rows++
+	_, err := i.w.WriteString(b.String())
+	return err
+}
+
+// literal writes a value as an SQL literal. BLOBs become literals like X'00FF'
+// unless they are exported as text.
+func (i *insertWriter) literal(v any) string {
+	switch v := v.(type) {
+	case []byte:
+		if i.opts.Blob == BlobText {
+			return sqlLiteral(string(v))
+		}
+	case string:
+		// A text value spelled like the NULL marker is still text
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	}
+	return sqlLiteral(v)
+}
+
+func (i *insertWriter) end() error {
+	if max(i.opts.Batch, 1) > 1 && i.rows > 0 {
+		if _, err := i.w.WriteString(";\n"); err != nil {
+			return err
+		}
+	}
+	return i.w.Flush()
+}
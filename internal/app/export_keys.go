@@ -0,0 +1,61 @@
+package app
+
+import "github.com/charmbracelet/bubbles/key"
+
+// ExportKeyMap defines keybindings for the export dialog
+type ExportKeyMap struct {
+	NextField  key.Binding
+	PrevField  key.Binding
+	NextChoice key.Binding
+	PrevChoice key.Binding
+	Export     key.Binding
+	Cancel     key.Binding
+	ToggleHelp key.Binding
+}
+
+// DefaultExportKeyMap returns the default keybindings for the export dialog
+func DefaultExportKeyMap() ExportKeyMap {
+	return ExportKeyMap{
+		NextField: key.NewBinding(
+			key.WithKeys("down", "tab"),
+			key.WithHelp("↓/tab", "next field"),
+		),
+		PrevField: key.NewBinding(
+			key.WithKeys("up", "shift+tab"),
+			key.WithHelp("↑/shift+tab", "prev field"),
+		),
+		NextChoice: key.NewBinding(
+			key.WithKeys("right", " "),
+			key.WithHelp("→/space", "next option"),
+		),
+		PrevChoice: key.NewBinding(
+			key.WithKeys("left"),
+			key.WithHelp("←", "prev option"),
+		),
+		Export: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "export"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+		),
+		ToggleHelp: key.NewBinding(
+			key.WithKeys("ctrl+g"),
+			key.WithHelp("ctrl+g", "toggle help"),
+		),
+	}
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view
+func (k ExportKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.NextField, k.NextChoice, k.Export, k.Cancel, k.ToggleHelp}
+}
+
+// FullHelp returns keybindings for the expanded help view
+func (k ExportKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.NextField, k.PrevField, k.NextChoice, k.PrevChoice},
+		{k.Export, k.Cancel, k.ToggleHelp},
+	}
+}
@@ -0,0 +1,183 @@
+package app
+
+import (
+	"bufio"
+	"encoding/json"
+	"html"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// tableCell converts a value to one line of table text
+func tableCell(v any, opts ExportOptions) string {
+	return strings.NewReplacer("\r\n", `\n`, "\n", `\n`, "\r", `\r`, "\t", " ").Replace(exportText(v, opts))
+}
+
+// numericValue reports whether a value is a number, which tables align
+// to the right
+func numericValue(v any) bool {
+	switch v.(type) {
+	case int64, float64, json.Number:
+		return true
+	}
+	return false
+}
+
+// gridWriter writes Markdown and ASCII tables. The column widths depend on
+// every row, so the rows are collected and written at the end.
+type gridWriter struct {
+	w       *bufio.Writer
+	opts    ExportOptions
+	columns []string
+	rows    [][]string
+	text    []bool // columns holding anything but numbers and NULLs
+}
+
+func (g *gridWriter) begin(columns []string) error {
+	g.columns = columns
+	g.text = make([]bool, len(columns))
+	return nil
+}
+
+func (g *gridWriter) row(values []any) error {
+	cells := make([]string, len(values))
+	for i, v := range values {
+		cells[i] = tableCell(v, g.opts)
+		if g.opts.Format == FormatMarkdown {
+			cells[i] = strings.ReplaceAll(cells[i], "|", `\|`)
+		}
+		if v != nil && !numericValue(v) {
+			g.text[i] = true
+		}
+	}
+	g.rows = append(g.rows, cells)
+	return nil
+}
+
+func (g *gridWriter) end() error {
+	header := make([]string, len(g.columns))
+	for i, col := range g.columns {
+		header[i] = tableCell(col, g.opts)
+		if g.opts.Format == FormatMarkdown {
+			header[i] = strings.ReplaceAll(header[i], "|", `\|`)
+		}
+	}
+	widths := make([]int, len(g.columns))
+	for i, name := range header {
+		widths[i] = runewidth.StringWidth(name)
+		if g.opts.Format == FormatMarkdown {
+			// Markdown needs three dashes per column
+			widths[i] = max(widths[i], 3)
+		}
+	}
+	for _, row := range g.rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], runewidth.StringWidth(cell))
+		}
+	}
+
+	if g.opts.Format == FormatMarkdown {
+		g.markdown(header, widths)
+	} else {
+		g.ascii(header, widths)
+	}
+	return g.w.Flush()
+}
+
+// pad aligns a cell in its column
+func (g *gridWriter) pad(cell string, col, width int) string {
+	fill := strings.Repeat(" ", width-runewidth.StringWidth(cell))
+	if g.text[col] {
+		return cell + fill
+	}
+	return fill + cell
+}
+
+func (g *gridWriter) line(cells []string, widths []int, left, sep, right string) {
+	padded := make([]string, len(cells))
+	for i, cell := range cells {
+		padded[i] = g.pad(cell, i, widths[i])
+	}
+	g.w.WriteString(left + strings.Join(padded, sep) + right + "\n")
+}
+
+// markdown writes a pipe table; Markdown tables always have a header
+func (g *gridWriter) markdown(header []string, widths []int) {
+	g.line(header, widths, "| ", " | ", " |")
+	rules := make([]string, len(widths))
+	for i, width := range widths {
+		if g.text[i] {
+			rules[i] = strings.Repeat("-", width)
+		} else {
+			rules[i] = strings.Repeat("-", width-1) + ":"
+		}
+	}
+	g.w.WriteString("| " + strings.Join(rules, " | ") + " |\n")
+	for _, row := range g.rows {
+		g.line(row, widths, "| ", " | ", " |")
+	}
+}
+
+// ascii writes a table framed with +, - and |
+func (g *gridWriter) ascii(header []string, widths []int) {
+	rules := make([]string, len(widths))
+	for i, width := range widths {
+		rules[i] = strings.Repeat("-", width)
+	}
+	rule := "+-" + strings.Join(rules, "-+-") + "-+\n"
+
+	g.w.WriteString(rule)
+	if g.opts.Header {
+		g.line(header, widths, "| ", " | ", " |")
+		g.w.WriteString(rule)
+	}
+	for _, row := range g.rows {
+		g.line(row, widths, "| ", " | ", " |")
+	}
+	if len(g.rows) > 0 {
+		g.w.WriteString(rule)
+	}
+}
+
+// htmlWriter writes an HTML table
+type htmlWriter struct {
+	w    *bufio.Writer
+	opts ExportOptions
+}
+
+func (h *htmlWriter) begin(columns []string) error {
+	h.w.WriteString("<table>\n")
+	if h.opts.Header {
+		h.w.WriteString("  <thead>\n    <tr>")
+		for _, col := range columns {
+			h.w.WriteString("<th>" + html.EscapeString(col) + "</th>")
+		}
+		h.w.WriteString("</tr>\n  </thead>\n")
+	}
+	_, err := h.w.WriteString("  <tbody>\n")
+	return err
+}
+
+func (h *htmlWriter) row(values []any) error {
+	h.w.WriteString("    <tr>")
+	for _, v := range values {
+		switch {
+		case v == nil:
+			h.w.WriteString(`<td class="null">` + html.EscapeString(h.opts.Null) + "</td>")
+		case numericValue(v):
+			h.w.WriteString(`<td align="right">` + html.EscapeString(exportText(v, h.opts)) + "</td>")
+		default:
+			h.w.WriteString("<td>" + html.EscapeString(exportText(v, h.opts)) + "</td>")
+		}
+	}
+	_, err := h.w.WriteString("</tr>\n")
+	return err
+}
+
+func (h *htmlWriter) end() error {
+	if _, err := h.w.WriteString("  </tbody>\n</table>\n"); err != nil {
+		return err
+	}
+	return h.w.Flush()
+}
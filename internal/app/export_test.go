@@ -0,0 +1,89 @@
+package app
+
+import (
+	"bytes"
+	"testing"
+)
+
+const exportSchema = `CREATE TABLE t(id INTEGER, name TEXT, price REAL, data BLOB);
+	INSERT INTO t VALUES (1, 'it''s', 2.5, x'00ff'), (2, '<b>|x', NULL, NULL)`
+
+func TestExportRows(t *testing.T) {
+	tests := []struct {
+		name   string
+		format ExportFormat
+		change func(*ExportOptions)
+		want   string
+	}{
+		{name: "insert", format: FormatSQL, want: `INSERT INTO "t" ("id", "name", "price", "data") VALUES (1, 'it''s', 2.5, X'00FF');
+INSERT INTO "t" ("id", "name", "price", "data") VALUES (2, '<b>|x', NULL, NULL);
+`},
+		{name: "insert batch", format: FormatSQL, change: func(o *ExportOptions) { o.Batch = 2; o.ColumnList = false }, want: `INSERT INTO "t" VALUES
+  (1, 'it''s', 2.5, X'00FF'),
+  (2, '<b>|x', NULL, NULL);
+`},
+		{name: "insert other table", format: FormatSQL, change: func(o *ExportOptions) { o.Table = "main.copy" }, want: `INSERT INTO "main"."copy" ("id", "name", "price", "data") VALUES (1, 'it''s', 2.5, X'00FF');
+INSERT INTO "main"."copy" ("id", "name", "price", "data") VALUES (2, '<b>|x', NULL, NULL);
+`},
+		{name: "markdown", format: FormatMarkdown, want: `|  id | name   | price | data |
+| --: | ------ | ----: | ---- |
+|   1 | it's   |   2.5 | 00ff |
+|   2 | <b>\|x |       |      |
+`},
+		{name: "ascii", format: FormatASCII, want: `+----+-------+-------+------+
+| id | name  | price | data |
++----+-------+-------+------+
+|  1 | it's  |   2.5 | 00ff |
+|  2 | <b>|x |       |      |
++----+-------+-------+------+
+`},
+		{name: "ascii without header", format: FormatASCII, change: func(o *ExportOptions) { o.Header = false }, want: `+----+-------+-------+------+
+|  1 | it's  |   2.5 | 00ff |
+|  2 | <b>|x |       |      |
++----+-------+-------+------+
+`},
+		{name: "html", format: FormatHTML, want: `<table>
+  <thead>
+    <tr><th>id</th><th>name</th><th>price</th><th>data</th></tr>
+  </thead>
+  <tbody>
+    <tr><td align="right">1</td><td>it&#39;s</td><td align="right">2.5</td><td>00ff</td></tr>
+    <tr><td align="right">2</td><td>&lt;b&gt;|x</td><td class="null"></td><td class="null"></td></tr>
+  </tbody>
+</table>
+`},
+		{name: "html base64", format: FormatHTML, change: func(o *ExportOptions) { o.Blob = BlobBase64; o.Null = "NULL" }, want: `<table>
+  <thead>
+    <tr><th>id</th><th>name</th><th>price</th><th>data</th></tr>
+  </thead>
+  <tbody>
+    <tr><td align="right">1</td><td>it&#39;s</td><td align="right">2.5</td><td>AP8=</td></tr>
+    <tr><td align="right">2</td><td>&lt;b&gt;|x</td><td class="null">NULL</td><td class="null">NULL</td></tr>
+  </tbody>
+</table>
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDatabase(t, exportSchema)
+			opts := DefaultExportOptions()
+			opts.Format = tt.format
+			opts.Table = "t"
+			if tt.change != nil {
+				tt.change(&opts)
+			}
+			rows, err := db.Query("SELECT * FROM t ORDER BY id")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out bytes.Buffer
+			n, err := ExportRows(&out, rows, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != 2 || out.String() != tt.want {
+				t.Errorf("wrote %d rows:\n%s\nwant:\n%s", n, out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,530 @@
+package app
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SwitchToExportMsg opens the export dialog for the rows of a view
+type SwitchToExportMsg struct{ Source exportSource }
+
+// Export scopes
+const (
+	scopeTable    = "table"    // every row of the table
+	scopeFilter   = "filter"   // the rows matching the current filter
+	scopeResults  = "results"  // every row the query returns
+	scopeSelected = "selected" // the marked rows, or the row under the cursor
+)
+
+// exportSource describes the rows a view can export
+type exportSource struct {
+	name     string // suggested file name without extension
+	grid     gridData
+	table    string
+	filter   *RowFilter
+	sort     *SortOrder
+	query    string
+	selected [][]string
+}
+
+// scopes returns the scopes that apply to the source, widest first
+func (s exportSource) scopes() []string {
+	var scopes []string
+	switch {
+	case s.table != "":
+		scopes = append(scopes, scopeTable)
+		if s.filter != nil {
+			scopes = append(scopes, scopeFilter)
+		}
+	case s.query != "":
+		scopes = append(scopes, scopeResults)
+	}
+	if len(s.selected) > 0 {
+		scopes = append(scopes, scopeSelected)
+	}
+	return scopes
+}
+
+// statement returns the query that reads the rows of a scope
+func (s exportSource) statement(scope string) (string, []any) {
+	if scope == scopeResults {
+		return s.query, nil
+	}
+	query := "SELECT * FROM " + quoteTable(s.table)
+	var args []any
+	if scope == scopeFilter && s.filter != nil {
+		var where string
+		where, args = s.filter.where()
+		query += " WHERE " + where
+	}
+	if s.sort != nil {
+		query += " ORDER BY " + s.sort.orderBy()
+	}
+	return query, args
+}
+
+// write exports the rows of a scope to w. Queries run on a query_only
+// connection so exporting cannot repeat a write.
+func (s exportSource) write(db *sql.DB, w io.Writer, scope string, opts ExportOptions) (int, error) {
+	if opts.Table == "" {
+		opts.Table = s.grid.table
+	}
+	if scope == scopeSelected {
+		return exportCells(w, s.grid, s.selected, opts)
+	}
+	query, args := s.statement(scope)
+	rows, release, err := queryStored(db, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+	return ExportRows(w, rows, opts)
+}
+
+// exportFile writes a scope to a file in the background, removing the file
+// again when the export fails
+func (s exportSource) exportFile(db *sql.DB, path, scope string, opts ExportOptions) tea.Cmd {
+	return func() tea.Msg {
+		f, err := os.Create(expandHome(path))
+		if err != nil {
+			return NotifyMsg{Severity: SeverityError, Message: fmt.Sprintf("export failed: %v", err)}
+		}
+		n, err := s.write(db, f, scope, opts)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(f.Name())
+			return NotifyMsg{Severity: SeverityError, Message: fmt.Sprintf("export failed: %v", err)}
+		}
+		return NotifyMsg{Severity: SeverityInfo, Message: fmt.Sprintf("exported %d rows to %s", n, path)}
+	}
+}
+
+// expandHome replaces a leading ~ with the home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+// exportField is a line of the export dialog
+type exportField int
+
+const (
+	fieldScope exportField = iota
+	fieldFormat
+	fieldPath
+	fieldTable
+	fieldColumnList
+	fieldBatch
+	fieldHeader
+	fieldDelimiter
+	fieldNull
+	fieldBlob
+	exportFieldCount
+)
+
+// Choices offered by the export dialog
+var (
+	exportDelimiters = []rune{',', ';', '|', '\t'}
+	exportNulls      = []string{"", "NULL", `\N`}
+	exportBlobs      = []BlobEncoding{BlobHex, BlobBase64, BlobText}
+	exportBatches    = []int{1, 10, 100, 500}
+)
+
+// ExportModel is the dialog that writes table or query rows to a file
+type ExportModel struct {
+	Shared       *SharedData
+	source       exportSource
+	scopes       []string
+	scope        int
+	opts         ExportOptions
+	pathInput    textinput.Model
+	pathEdited   bool
+	tableInput   textinput.Model
+	field        exportField
+	keyMap       ExportKeyMap
+	help         help.Model
+	showFullHelp bool
+	focused      bool
+	id           int
+}
+
+// ExportOption is a functional option for configuring ExportModel
+type ExportOption func(*ExportModel)
+
+// WithExportKeyMap sets the key map
+func WithExportKeyMap(km ExportKeyMap) ExportOption {
+	return func(m *ExportModel) {
+		m.keyMap = km
+	}
+}
+
+func NewExportModel(shared *SharedData, source exportSource, opts ...ExportOption) *ExportModel {
+	pathInput := textinput.New()
+	pathInput.CharLimit = 1024
+	pathInput.Width = 50
+
+	tableInput := textinput.New()
+	tableInput.Placeholder = "table name"
+	tableInput.CharLimit = 256
+	tableInput.Width = 50
+	tableInput.SetValue(source.grid.table)
+
+	m := &ExportModel{
+		Shared:     shared,
+		source:     source,
+		scopes:     source.scopes(),
+		opts:       DefaultExportOptions(),
+		pathInput:  pathInput,
+		tableInput: tableInput,
+		keyMap:     shared.keyMaps().Export,
+		help:       shared.theme().newHelp(),
+		focused:    true,
+		id:         nextID(),
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	// A filtered table exports the filtered rows unless told otherwise
+	for i, scope := range m.scopes {
+		if scope == scopeFilter {
+			m.scope = i
+		}
+	}
+	m.suggestPath()
+	return m
+}
+
+// ID returns the unique ID of the model
+func (m ExportModel) ID() int {
+	return m.id
+}
+
+// Focus sets the focus state
+func (m *ExportModel) Focus() {
+	m.focused = true
+	if input := m.input(); input != nil {
+		input.Focus()
+	}
+}
+
+// Blur removes focus
+func (m *ExportModel) Blur() {
+	m.focused = false
+	m.pathInput.Blur()
+	m.tableInput.Blur()
+}
+
+// Focused returns the focus state
+func (m ExportModel) Focused() bool {
+	return m.focused
+}
+
+// input returns the text input of the current field, if it has one
+func (m *ExportModel) input() *textinput.Model {
+	switch m.field {
+	case fieldPath:
+		return &m.pathInput
+	case fieldTable:
+		return &m.tableInput
+	}
+	return nil
+}
+
+// editingText reports whether keys are typed into the file or table name
+func (m *ExportModel) editingText() bool {
+	return m.input() != nil
+}
+
+func (m *ExportModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *ExportModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if !m.focused {
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case ToggleHelpMsg:
+		m.showFullHelp = !m.showFullHelp
+		return m, nil
+
+	case tea.KeyMsg:
+		return m, m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m *ExportModel) handleKey(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.keyMap.Cancel):
+		return func() tea.Msg { return NavigateBackMsg{} }
+
+	case key.Matches(msg, m.keyMap.Export):
+		return m.export()
+
+	case key.Matches(msg, m.keyMap.NextField):
+		m.moveField(1)
+		return nil
+
+	case key.Matches(msg, m.keyMap.PrevField):
+		m.moveField(-1)
+		return nil
+	}
+
+	if input := m.input(); input != nil {
+		var cmd tea.Cmd
+		*input, cmd = input.Update(msg)
+		m.pathEdited = m.pathEdited || m.field == fieldPath
+		return cmd
+	}
+
+	switch {
+	case key.Matches(msg, m.keyMap.NextChoice):
+		m.cycle(1)
+	case key.Matches(msg, m.keyMap.PrevChoice):
+		m.cycle(-1)
+	}
+	return nil
+}
+
+// moveField moves to the next or previous line, skipping options that do
+// not apply to the format
+func (m *ExportModel) moveField(delta int) {
+	for {
+		m.field = (m.field + exportField(delta) + exportFieldCount) % exportFieldCount
+		if m.applies(m.field) {
+			break
+		}
+	}
+	m.pathInput.Blur()
+	m.tableInput.Blur()
+	if input := m.input(); input != nil {
+		input.Focus()
+		input.CursorEnd()
+	}
+}
+
+// applies reports whether a field is used by the selected format
+func (m *ExportModel) applies(field exportField) bool {
+	switch format := m.opts.Format; field {
+	case fieldTable, fieldColumnList, fieldBatch:
+		return format == FormatSQL
+	case fieldHeader:
+		return format == FormatCSV || format == FormatTSV || format == FormatASCII || format == FormatHTML
+	case fieldDelimiter:
+		return format == FormatCSV
+	case fieldNull:
+		return format != FormatJSON && format != FormatNDJSON && format != FormatSQL
+	}
+	return true
+}
+
+// cycle changes the option of the current field
+func (m *ExportModel) cycle(delta int) {
+	switch m.field {
+	case fieldScope:
+		m.scope = wrapIndex(m.scope+delta, len(m.scopes))
+	case fieldFormat:
+		formats := ExportFormats()
+		m.opts.Format = formats[wrapIndex(indexOf(formats, m.opts.Format)+delta, len(formats))]
+		m.suggestPath()
+	case fieldColumnList:
+		m.opts.ColumnList = !m.opts.ColumnList
+	case fieldBatch:
+		m.opts.Batch = exportBatches[wrapIndex(indexOf(exportBatches, m.opts.Batch)+delta, len(exportBatches))]
+	case fieldHeader:
+		m.opts.Header = !m.opts.Header
+	case fieldDelimiter:
+		m.opts.Delimiter = exportDelimiters[wrapIndex(indexOf(exportDelimiters, m.opts.Delimiter)+delta, len(exportDelimiters))]
+	case fieldNull:
+		m.opts.Null = exportNulls[wrapIndex(indexOf(exportNulls, m.opts.Null)+delta, len(exportNulls))]
+	case fieldBlob:
+		m.opts.Blob = exportBlobs[wrapIndex(indexOf(exportBlobs, m.opts.Blob)+delta, len(exportBlobs))]
+	}
+}
+
+// suggestPath names the file after the source and format until the user
+// edits the name
+func (m *ExportModel) suggestPath() {
+	if m.pathEdited {
+		return
+	}
+	m.pathInput.SetValue(m.source.name + "." + m.opts.Format.Extension())
+}
+
+func (m *ExportModel) export() tea.Cmd {
+	path := strings.TrimSpace(m.pathInput.Value())
+	if path == "" {
+		return Notify(SeverityError, "enter a file name")
+	}
+	if len(m.scopes) == 0 {
+		return Notify(SeverityError, "nothing to export")
+	}
+	opts := m.opts
+	if opts.Format == FormatSQL {
+		if opts.Table = strings.TrimSpace(m.tableInput.Value()); opts.Table == "" {
+			return Notify(SeverityError, "enter the table the INSERT statements write to")
+		}
+	}
+	return tea.Batch(
+		m.source.exportFile(m.Shared.DB, path, m.scopes[m.scope], opts),
+		func() tea.Msg { return NavigateBackMsg{} },
+	)
+}
+
+func wrapIndex(i, n int) int {
+	if n == 0 {
+		return 0
+	}
+	return (i%n + n) % n
+}
+
+func indexOf[T comparable](list []T, v T) int {
+	for i, item := range list {
+		if item == v {
+			return i
+		}
+	}
+	return 0
+}
+
+// scopeLabel describes a scope with its row count where it is known
+func (m *ExportModel) scopeLabel(scope string) string {
+	switch scope {
+	case scopeTable:
+		return "whole table"
+	case scopeFilter:
+		return fmt.Sprintf("rows where %s", m.source.filter)
+	case scopeResults:
+		return "all query results"
+	case scopeSelected:
+		if len(m.source.selected) == 1 {
+			return "selected row"
+		}
+		return fmt.Sprintf("%d selected rows", len(m.source.selected))
+	}
+	return scope
+}
+
+func (m *ExportModel) View() string {
+	theme := m.Shared.theme()
+	var content strings.Builder
+
+	content.WriteString(theme.Title.Render("Export " + m.source.name))
+	content.WriteString("\n\n")
+
+	scope := ""
+	if len(m.scopes) > 0 {
+		scope = m.scopeLabel(m.scopes[m.scope])
+	}
+	delimiter := map[rune]string{',': "comma", ';': "semicolon", '|': "pipe", '\t': "tab"}[m.opts.Delimiter]
+	null := m.opts.Null
+	if null == "" {
+		null = "(empty)"
+	}
+	onOff := map[bool]string{true: "on", false: "off"}
+	batch := "1 row per statement"
+	if m.opts.Batch > 1 {
+		batch = fmt.Sprintf("%d rows per statement", m.opts.Batch)
+	}
+
+	lines := []struct {
+		field exportField
+		label string
+		value string
+	}{
+		{fieldScope, "Rows", scope},
+		{fieldFormat, "Format", string(m.opts.Format)},
+		{fieldPath, "File", m.pathInput.View()},
+		{fieldTable, "Table", m.tableInput.View()},
+		{fieldColumnList, "Columns", onOff[m.opts.ColumnList]},
+		{fieldBatch, "Batch", batch},
+		{fieldHeader, "Header", onOff[m.opts.Header]},
+		{fieldDelimiter, "Delimiter", delimiter},
+		{fieldNull, "NULL as", null},
+		{fieldBlob, "BLOBs as", string(m.opts.Blob)},
+	}
+	for _, line := range lines {
+		if !m.applies(line.field) {
+			continue
+		}
+		value := line.value
+		if line.field != fieldPath && line.field != fieldTable {
+			value = "‹ " + value + " ›"
+		}
+		text := fmt.Sprintf("%-10s %s", line.label+":", value)
+		if line.field == m.field {
+			content.WriteString(theme.Selected.Render("> " + text))
+		} else {
+			content.WriteString(theme.Normal.Render("  " + text))
+		}
+		content.WriteString("\n")
+	}
+
+	content.WriteString("\n")
+	if m.showFullHelp {
+		content.WriteString(m.help.FullHelpView(m.keyMap.FullHelp()))
+	} else {
+		content.WriteString(m.help.ShortHelpView(m.keyMap.ShortHelp()))
+	}
+
+	return content.String()
+}
+
+// export opens the export dialog for the current view, or with a file name
+// writes its rows straight away in the format of the file extension
+func (m *Model) export(args []string) tea.Cmd {
+	var source exportSource
+	switch v := m.currentView.(type) {
+	case *TableDataModel:
+		source = v.exportSource()
+	case *QueryModel:
+		source = v.exportSource()
+	default:
+		return Notify(SeverityWarning, "open a table or query results to export")
+	}
+	if len(args) == 0 {
+		return func() tea.Msg { return SwitchToExportMsg{Source: source} }
+	}
+
+	path := args[0]
+	opts := DefaultExportOptions()
+	format, err := ParseExportFormat(filepath.Ext(path))
+	if err != nil {
+		return NotifyError(fmt.Errorf("%w; use a .csv, .tsv, .json, .ndjson, .sql, .md, .txt or .html file", err))
+	}
+	opts.Format = format
+	scopes := source.scopes()
+	if len(scopes) == 0 {
+		return Notify(SeverityError, "nothing to export")
+	}
+	// Export the widest scope, or the filtered rows of a filtered table
+	scope := scopes[0]
+	for _, s := range scopes {
+		if s == scopeFilter {
+			scope = s
+		}
+	}
+	return source.exportFile(m.getSharedData().DB, path, scope, opts)
+}
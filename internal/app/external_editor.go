@@ -0,0 +1,82 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ExternalEditorMsg is sent when an external editor started by
+// openExternalEditor exits. ID is the ID of the model that requested it.
+type ExternalEditorMsg struct {
+	ID    int
+	Value string
+	Err   error
+}
+
+// editorCommand returns the user's preferred editor command line,
+// honouring $VISUAL, then $EDITOR, then falling back to vi
+func editorCommand() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"vi"}
+}
+
+// valueExtension picks a temp file extension so editors select a sensible
+// syntax mode for a cell value
+func valueExtension(value string) string {
+	trimmed := strings.TrimSpace(value)
+	switch {
+	case (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)):
+		return ".json"
+	case strings.HasPrefix(trimmed, "<"):
+		return ".xml"
+	default:
+		return ".txt"
+	}
+}
+
+// openExternalEditor suspends the TUI, edits value in a temp file with the
+// given extension and reports the result with an ExternalEditorMsg
+func openExternalEditor(id int, value, ext string) tea.Cmd {
+	f, err := os.CreateTemp("", "teaqlite-*"+ext)
+	if err != nil {
+		return func() tea.Msg { return ExternalEditorMsg{ID: id, Err: err} }
+	}
+	path := f.Name()
+	if _, err := f.WriteString(value); err != nil {
+		f.Close()
+		os.Remove(path)
+		return func() tea.Msg { return ExternalEditorMsg{ID: id, Err: err} }
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(path)
+		return func() tea.Msg { return ExternalEditorMsg{ID: id, Err: err} }
+	}
+
+	args := append(editorCommand(), path)
+	c := exec.Command(args[0], args[1:]...)
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		defer os.Remove(path)
+		if err != nil {
+			return ExternalEditorMsg{ID: id, Err: fmt.Errorf("editor %s failed: %w", args[0], err)}
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return ExternalEditorMsg{ID: id, Err: err}
+		}
+		edited := string(data)
+		// Most editors append a final newline; drop it unless it was there before
+		if !strings.HasSuffix(value, "\n") {
+			edited = strings.TrimSuffix(strings.TrimSuffix(edited, "\n"), "\r")
+		}
+		return ExternalEditorMsg{ID: id, Value: edited}
+	})
+}
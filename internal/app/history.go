@@ -0,0 +1,123 @@
+package app
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// maxHistory is the number of views kept for back navigation
+const maxHistory = 100
+
+// navEntry is a view in the navigation history together with the shared
+// data it showed when it was left
+type navEntry struct {
+	view   tea.Model
+	shared SharedData
+}
+
+// current captures the current view and its shared data
+func (m *Model) current() navEntry {
+	return navEntry{view: m.currentView, shared: *m.getSharedData()}
+}
+
+// push records the current view before navigating to a new one
+func (m *Model) push() {
+	m.record(m.current())
+}
+
+// record adds an entry to the history. Going somewhere new discards the
+// forward history.
+func (m *Model) record(entry navEntry) {
+	m.history = append(m.history, entry)
+	if len(m.history) > maxHistory {
+		m.history = m.history[len(m.history)-maxHistory:]
+	}
+	m.forward = nil
+}
+
+// back returns to the previous view, reporting false when there is none
+func (m *Model) back() bool {
+	if len(m.history) == 0 {
+		return false
+	}
+	entry := m.history[len(m.history)-1]
+	m.history = m.history[:len(m.history)-1]
+	m.forward = append(m.forward, m.current())
+	m.restore(entry)
+	return true
+}
+
+// forwardTo returns to the view left by the last back navigation
+func (m *Model) forwardTo() bool {
+	if len(m.forward) == 0 {
+		return false
+	}
+	entry := m.forward[len(m.forward)-1]
+	m.forward = m.forward[:len(m.forward)-1]
+	m.history = append(m.history, m.current())
+	m.restore(entry)
+	return true
+}
+
+// restore makes a history entry the current view. The window size and the
+// schema are not part of a view's state, so they keep their current values;
+// a table list filters the current tables again.
+func (m *Model) restore(entry navEntry) {
+	shared := m.getSharedData()
+	now := *shared
+	*shared = entry.shared
+	shared.Width, shared.Height = now.Width, now.Height
+	shared.Schemas, shared.Tables = now.Schemas, now.Tables
+	shared.completer = now.completer
+	m.currentView = entry.view
+	if v, ok := entry.view.(*TableListModel); ok {
+		v.filterTables()
+	}
+}
+
+// backToTableList returns to the most recent table list in the history,
+// or opens a new one, and clears the history
+func (m *Model) backToTableList() {
+	for i := len(m.history) - 1; i >= 0; i-- {
+		if _, ok := m.history[i].view.(*TableListModel); ok {
+			m.restore(m.history[i])
+			m.history, m.forward = nil, nil
+			return
+		}
+	}
+	shared := m.getSharedData()
+	shared.Filter = nil
+	shared.FilteredTables = make([]string, len(shared.Tables))
+	copy(shared.FilteredTables, shared.Tables)
+	m.history, m.forward = nil, nil
+	m.currentView = NewTableListModel(shared)
+}
+
+// fallbackView returns the view to go back to when the history is empty,
+// e.g. the table a row belongs to. It returns nil for the table list.
+func (m *Model) fallbackView() tea.Model {
+	shared := m.getSharedData()
+	switch v := m.currentView.(type) {
+	case *EditCellModel:
+		rowDetail := NewRowDetailModel(shared, v.rowIndex)
+		rowDetail.FromQuery = shared.IsQueryResult
+		return rowDetail
+	case *RowDetailModel:
+		if v.FromQuery {
+			return m.queryView()
+		}
+		return NewTableDataModel(shared)
+	case *TableListModel:
+		return nil
+	default:
+		return NewTableListModel(shared)
+	}
+}
+
+// queryView returns the query view, reusing the last one so its query text
+// and results survive leaving SQL mode
+func (m *Model) queryView() *QueryModel {
+	if m.lastQueryView == nil {
+		m.lastQueryView = NewQueryModel(m.getSharedData())
+	} else {
+		m.lastQueryView.resume()
+	}
+	return m.lastQueryView
+}
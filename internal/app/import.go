@@ -0,0 +1,526 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ImportFormats lists the formats rows can be imported from
+func ImportFormats() []ExportFormat {
+	return []ExportFormat{FormatCSV, FormatTSV, FormatJSON, FormatNDJSON}
+}
+
+// ParseImportFormat looks an import format up by name or file extension
+func ParseImportFormat(name string) (ExportFormat, error) {
+	format, err := ParseExportFormat(name)
+	if err == nil {
+		for _, f := range ImportFormats() {
+			if f == format {
+				return f, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("cannot import %q, use csv, tsv, json or ndjson", strings.TrimPrefix(name, "."))
+}
+
+// ImportOptions configures how a file is read
+type ImportOptions struct {
+	Format ExportFormat
+	// Header reads the column names from the first CSV or TSV line
+	Header bool
+	// Delimiter separates CSV fields
+	Delimiter rune
+	// Null is the CSV and TSV text read as NULL
+	Null string
+}
+
+// DefaultImportOptions returns CSV with a header and commas, reading empty
+// fields as NULL
+func DefaultImportOptions() ImportOptions {
+	return ImportOptions{Format: FormatCSV, Header: true, Delimiter: ','}
+}
+
+// ImportData holds the rows read from a file as cell text, with NullValue
+// for NULLs
+type ImportData struct {
+	Columns []string
+	Rows    [][]string
+	// Lines are the line numbers of CSV and TSV rows or the positions of
+	// JSON records, for reporting rejected rows
+	Lines []int
+	// Unit names what Lines count: "line" or "record"
+	Unit string
+}
+
+// ReadImport reads a CSV, TSV, JSON or NDJSON file. JSON may be an array of
+// objects or one object per line; the columns are the keys in the order
+// they first appear.
+func ReadImport(r io.Reader, opts ImportOptions) (*ImportData, error) {
+	var data *ImportData
+	var err error
+	switch opts.Format {
+	case FormatCSV:
+		data, err = readCSV(r, opts)
+	case FormatTSV:
+		data, err = readTSV(r, opts)
+	case FormatJSON, FormatNDJSON:
+		data, err = readJSON(r)
+	default:
+		return nil, fmt.Errorf("cannot import %q", opts.Format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data.Columns) == 0 {
+		return nil, errors.New("the file has no columns")
+	}
+	return data, nil
+}
+
+// addRecord adds a CSV or TSV record, naming the columns after the first
+// record when there is no header
+func (d *ImportData) addRecord(fields []string, line int, opts ImportOptions) {
+	if d.Columns == nil {
+		if len(fields) > 0 {
+			fields[0] = strings.TrimPrefix(fields[0], "\ufeff")
+		}
+		if opts.Header {
+			d.Columns = fields
+			return
+		}
+		for i := range fields {
+			d.Columns = append(d.Columns, fmt.Sprintf("column%d", i+1))
+		}
+	}
+	for i, f := range fields {
+		if f == opts.Null {
+			fields[i] = NullValue
+		}
+	}
+	d.Rows = append(d.Rows, fields)
+	d.Lines = append(d.Lines, line)
+}
+
+func readCSV(r io.Reader, opts ImportOptions) (*ImportData, error) {
+	cr := csv.NewReader(r)
+	if opts.Delimiter != 0 {
+		cr.Comma = opts.Delimiter
+	}
+	// Rows with too few or too many fields are rejected when importing
+	cr.FieldsPerRecord = -1
+	data := &ImportData{Unit: "line"}
+	for {
+		fields, err := cr.Read()
+		if err == io.EOF {
+			return data, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		data.addRecord(fields, line, opts)
+	}
+}
+
+var tsvUnescaper = strings.NewReplacer(`\\`, "\\", `\t`, "\t", `\n`, "\n", `\r`, "\r")
+
+func readTSV(r io.Reader, opts ImportOptions) (*ImportData, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	data := &ImportData{Unit: "line"}
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if text == "" {
+			continue
+		}
+		fields := strings.Split(text, "\t")
+		for i, f := range fields {
+			if f != opts.Null {
+				fields[i] = tsvUnescaper.Replace(f)
+			}
+		}
+		data.addRecord(fields, line, opts)
+	}
+	return data, scanner.Err()
+}
+
+func readJSON(r io.Reader) (*ImportData, error) {
+	input, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(input))
+	data := &ImportData{Unit: "record"}
+	index := map[string]int{}
+	var records []map[string]string
+
+	// An array of objects, or objects one after another
+	array := bytes.HasPrefix(bytes.TrimSpace(input), []byte("["))
+	if array {
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+	}
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("record %d: %w", len(records)+1, err)
+		}
+		keys, values, err := decodeObject(raw)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", len(records)+1, err)
+		}
+		for _, k := range keys {
+			if _, ok := index[k]; !ok {
+				index[k] = len(data.Columns)
+				data.Columns = append(data.Columns, k)
+			}
+		}
+		records = append(records, values)
+	}
+	if array {
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, values := range records {
+		row := make([]string, len(data.Columns))
+		for col, name := range data.Columns {
+			if v, ok := values[name]; ok {
+				row[col] = v
+			} else {
+				row[col] = NullValue
+			}
+		}
+		data.Rows = append(data.Rows, row)
+		data.Lines = append(data.Lines, i+1)
+	}
+	return data, nil
+}
+
+// decodeObject reads a JSON object, returning its keys in order and its
+// values as cell text. Nested objects and arrays are kept as JSON text.
+func decodeObject(raw json.RawMessage) ([]string, map[string]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if tok, err := dec.Token(); err != nil {
+		return nil, nil, err
+	} else if tok != json.Delim('{') {
+		return nil, nil, errors.New("expected an object")
+	}
+
+	var keys []string
+	values := map[string]string{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		name, ok := tok.(string)
+		if !ok {
+			return nil, nil, fmt.Errorf("expected a key, got %v", tok)
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+		if _, ok := values[name]; !ok {
+			keys = append(keys, name)
+		}
+		values[name], err = jsonCell(value)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return keys, values, nil
+}
+
+// jsonCell converts a JSON value to cell text
+func jsonCell(raw json.RawMessage) (string, error) {
+	trimmed := bytes.TrimSpace(raw)
+	switch {
+	case string(trimmed) == "null":
+		return NullValue, nil
+	case string(trimmed) == "true":
+		return "1", nil
+	case string(trimmed) == "false":
+		return "0", nil
+	case len(trimmed) > 0 && trimmed[0] == '"':
+		var s string
+		err := json.Unmarshal(trimmed, &s)
+		return s, err
+	case len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '['):
+		var b bytes.Buffer
+		err := json.Compact(&b, trimmed)
+		return b.String(), err
+	}
+	return string(trimmed), nil
+}
+
+// ImportColumn maps a column of the file to a table column
+type ImportColumn struct {
+	Source string
+	// Target is the table column, or empty to skip the column
+	Target string
+	// Type is the declared type of the column when the table is created
+	Type string
+}
+
+// ImportPlan says where the rows of a file go
+type ImportPlan struct {
+	Table string
+	// Create is set when the table does not exist yet
+	Create  bool
+	Columns []ImportColumn
+	// Existing lists the columns of an existing table
+	Existing []ColumnInfo
+}
+
+// PlanImport maps the columns of a file to a table. An existing table's
+// columns are matched by name, ignoring case; a new table gets a column
+// for every column of the file with an inferred type.
+func PlanImport(db *sql.DB, data *ImportData, table string) (*ImportPlan, error) {
+	if strings.TrimSpace(table) == "" {
+		return nil, errors.New("enter a table name")
+	}
+	existing, err := loadColumnInfo(db, table)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &ImportPlan{Table: table, Create: len(existing) == 0, Existing: existing}
+	used := map[string]bool{}
+	for i, name := range data.Columns {
+		col := ImportColumn{Source: name}
+		if plan.Create {
+			col.Target = uniqueName(name, i, used)
+			col.Type = inferType(data, i)
+		} else {
+			for _, c := range existing {
+				if strings.EqualFold(c.Name, name) && !used[strings.ToLower(c.Name)] {
+					col.Target = c.Name
+					used[strings.ToLower(c.Name)] = true
+					break
+				}
+			}
+		}
+		plan.Columns = append(plan.Columns, col)
+	}
+	return plan, nil
+}
+
+// uniqueName names a new column after a file column, avoiding empty and
+// repeated names
+func uniqueName(name string, i int, used map[string]bool) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = fmt.Sprintf("column%d", i+1)
+	}
+	unique := name
+	for n := 2; used[strings.ToLower(unique)]; n++ {
+		unique = fmt.Sprintf("%s_%d", name, n)
+	}
+	used[strings.ToLower(unique)] = true
+	return unique
+}
+
+// inferType picks INTEGER, REAL or TEXT for a column of the file. Numbers
+// with leading zeros, like postal codes, stay text.
+func inferType(data *ImportData, col int) string {
+	typ := ""
+	for _, row := range data.Rows {
+		if col >= len(row) || row[col] == NullValue {
+			continue
+		}
+		v := strings.TrimSpace(row[col])
+		switch {
+		case v == "" || v != row[col] || leadingZero(v):
+			return "TEXT"
+		case isInteger(v):
+			if typ == "" {
+				typ = "INTEGER"
+			}
+		case isReal(v):
+			typ = "REAL"
+		default:
+			return "TEXT"
+		}
+	}
+	if typ == "" {
+		return "TEXT"
+	}
+	return typ
+}
+
+func leadingZero(v string) bool {
+	v = strings.TrimPrefix(v, "-")
+	return len(v) > 1 && v[0] == '0' && v[1] != '.'
+}
+
+func isInteger(v string) bool {
+	_, err := strconv.ParseInt(v, 10, 64)
+	return err == nil
+}
+
+func isReal(v string) bool {
+	f, err := strconv.ParseFloat(v, 64)
+	return err == nil && !math.IsInf(f, 0) && !math.IsNaN(f)
+}
+
+// Map sends a file column to a table column; an empty target skips it
+func (p *ImportPlan) Map(source, target string) error {
+	if target != "" && !p.Create {
+		found := false
+		for _, c := range p.Existing {
+			if strings.EqualFold(c.Name, target) {
+				target, found = c.Name, true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("table %s has no column %q", p.Table, target)
+		}
+	}
+	for i := range p.Columns {
+		if p.Columns[i].Source == source {
+			p.Columns[i].Target = target
+			return nil
+		}
+	}
+	return fmt.Errorf("the file has no column %q", source)
+}
+
+// targetInfo returns the definition of the column a file column maps to
+func (p *ImportPlan) targetInfo(col ImportColumn) ColumnInfo {
+	if p.Create {
+		return ColumnInfo{Name: col.Target, Type: col.Type}
+	}
+	for _, c := range p.Existing {
+		if c.Name == col.Target {
+			return c
+		}
+	}
+	return ColumnInfo{Name: col.Target}
+}
+
+// createSQL returns the CREATE TABLE statement for a new table
+func (p *ImportPlan) createSQL() string {
+	var defs []string
+	for _, col := range p.Columns {
+		if col.Target != "" {
+			defs = append(defs, quoteIdent(col.Target)+" "+col.Type)
+		}
+	}
+	return fmt.Sprintf("CREATE TABLE %s (%s)", quoteTable(p.Table), strings.Join(defs, ", "))
+}
+
+// RejectedRow is a row of the file that could not be imported
+type RejectedRow struct {
+	Line int
+	Err  error
+}
+
+// ImportResult reports the outcome of an import
+type ImportResult struct {
+	Inserted int
+	Rejected []RejectedRow
+	Unit     string
+}
+
+// Import inserts the rows of a file as planned, in one transaction. Rows
+// with values that do not fit their column or that break a constraint are
+// rejected and reported; the other rows are imported.
+func Import(db *sql.DB, data *ImportData, plan *ImportPlan) (*ImportResult, error) {
+	var names, marks []string
+	var sources []int
+	var targets []ColumnInfo
+	mapped := map[string]string{}
+	for i, col := range plan.Columns {
+		if col.Target == "" {
+			continue
+		}
+		if other, ok := mapped[strings.ToLower(col.Target)]; ok {
+			return nil, fmt.Errorf("%s and %s are both mapped to column %s", other, col.Source, col.Target)
+		}
+		mapped[strings.ToLower(col.Target)] = col.Source
+		names = append(names, quoteIdent(col.Target))
+		marks = append(marks, "?")
+		sources = append(sources, i)
+		targets = append(targets, plan.targetInfo(col))
+	}
+	if len(names) == 0 {
+		return nil, errors.New("no columns are mapped to the table")
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if plan.Create {
+		if _, err := tx.Exec(plan.createSQL()); err != nil {
+			return nil, err
+		}
+	}
+	stmt, err := tx.Prepare(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		quoteTable(plan.Table), strings.Join(names, ", "), strings.Join(marks, ", ")))
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	result := &ImportResult{Unit: data.Unit}
+	args := make([]any, len(sources))
+	for r, row := range data.Rows {
+		err := func() error {
+			if len(row) != len(data.Columns) {
+				return fmt.Errorf("has %d fields, expected %d", len(row), len(data.Columns))
+			}
+			for i, src := range sources {
+				args[i] = convertValue(targets[i], row[src]).Arg
+			}
+			_, err := stmt.Exec(args...)
+			return classifyConstraintError(err)
+		}()
+		if err != nil {
+			result.Rejected = append(result.Rejected, RejectedRow{Line: data.Lines[r], Err: err})
+			continue
+		}
+		result.Inserted++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Preview writes the first n rows as an ASCII table, showing NULLs as
+// NullText
+func (d *ImportData) Preview(w io.Writer, n int) error {
+	grid := gridData{columns: make([]ColumnInfo, len(d.Columns))}
+	for i, name := range d.Columns {
+		grid.columns[i] = ColumnInfo{Name: name}
+	}
+	opts := DefaultExportOptions()
+	opts.Format = FormatASCII
+	opts.Null = NullText
+	rows := make([][]string, min(len(d.Rows), n))
+	for i := range rows {
+		// Ragged rows are cut or padded to fit the table
+		rows[i] = make([]string, len(d.Columns))
+		copy(rows[i], d.Rows[i])
+	}
+	_, err := exportCells(w, grid, rows, opts)
+	return err
+}
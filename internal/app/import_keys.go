@@ -0,0 +1,61 @@
+package app
+
+import "github.com/charmbracelet/bubbles/key"
+
+// ImportKeyMap defines keybindings for the import dialog
+type ImportKeyMap struct {
+	NextField  key.Binding
+	PrevField  key.Binding
+	NextChoice key.Binding
+	PrevChoice key.Binding
+	Import     key.Binding
+	Cancel     key.Binding
+	ToggleHelp key.Binding
+}
+
+// DefaultImportKeyMap returns the default keybindings for the import dialog
+func DefaultImportKeyMap() ImportKeyMap {
+	return ImportKeyMap{
+		NextField: key.NewBinding(
+			key.WithKeys("down", "tab"),
+			key.WithHelp("↓/tab", "next field"),
+		),
+		PrevField: key.NewBinding(
+			key.WithKeys("up", "shift+tab"),
+			key.WithHelp("↑/shift+tab", "prev field"),
+		),
+		NextChoice: key.NewBinding(
+			key.WithKeys("right", " "),
+			key.WithHelp("→/space", "next option"),
+		),
+		PrevChoice: key.NewBinding(
+			key.WithKeys("left"),
+			key.WithHelp("←", "prev option"),
+		),
+		Import: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "import"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+		),
+		ToggleHelp: key.NewBinding(
+			key.WithKeys("ctrl+g"),
+			key.WithHelp("ctrl+g", "toggle help"),
+		),
+	}
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view
+func (k ImportKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.NextField, k.NextChoice, k.Import, k.Cancel, k.ToggleHelp}
+}
+
+// FullHelp returns keybindings for the expanded help view
+func (k ImportKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.NextField, k.PrevField, k.NextChoice, k.PrevChoice},
+		{k.Import, k.Cancel, k.ToggleHelp},
+	}
+}
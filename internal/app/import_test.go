@@ -0,0 +1,231 @@
+package app
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInferType(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   string
+	}{
+		{"integers", []string{"1", "-20", NullValue}, "INTEGER"},
+		{"integers and reals", []string{"1", "2.5"}, "REAL"},
+		{"reals and integers", []string{"2.5", "1"}, "REAL"},
+		{"leading zero", []string{"1", "007"}, "TEXT"},
+		{"negative leading zero", []string{"-01"}, "TEXT"},
+		{"zero point", []string{"0.5", "0"}, "REAL"},
+		{"padded", []string{" 1"}, "TEXT"},
+		{"empty", []string{""}, "TEXT"},
+		{"only NULL", []string{NullValue}, "TEXT"},
+		{"infinity", []string{"1e999"}, "TEXT"},
+		{"text", []string{"1", "abc"}, "TEXT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &ImportData{Columns: []string{"v"}}
+			for _, v := range tt.values {
+				data.Rows = append(data.Rows, []string{v})
+			}
+			if got := inferType(data, 0); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInferTypeSkipsShortRows(t *testing.T) {
+	data := &ImportData{Columns: []string{"a", "b"}, Rows: [][]string{{"1"}, {"2", "3"}}}
+	if got := inferType(data, 1); got != "INTEGER" {
+		t.Errorf("got %s, want INTEGER", got)
+	}
+}
+
+func TestReadTSV(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		header bool
+		null   string
+		cols   []string
+		rows   [][]string
+		lines  []int
+	}{
+		{
+			name:   "header",
+			input:  "id\tzip\n1\t01234\n",
+			header: true,
+			cols:   []string{"id", "zip"},
+			rows:   [][]string{{"1", "01234"}},
+			lines:  []int{2},
+		},
+		{
+			name:  "no header",
+			input: "a\tb\r\n",
+			cols:  []string{"column1", "column2"},
+			rows:  [][]string{{"a", "b"}},
+			lines: []int{1},
+		},
+		{
+			name:   "ragged rows and blank lines",
+			input:  "a\tb\n1\n\n1\t2\t3\n",
+			header: true,
+			cols:   []string{"a", "b"},
+			rows:   [][]string{{"1"}, {"1", "2", "3"}},
+			lines:  []int{2, 4},
+		},
+		{
+			name:   "escapes and NULL",
+			input:  "a\tb\nx\\ty\\\\n\t\\N\n",
+			header: true,
+			null:   `\N`,
+			cols:   []string{"a", "b"},
+			rows:   [][]string{{"x\ty\\n", NullValue}},
+			lines:  []int{2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := ImportOptions{Format: FormatTSV, Header: tt.header, Null: tt.null}
+			data, err := readTSV(strings.NewReader(tt.input), opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(data.Columns, tt.cols) || !reflect.DeepEqual(data.Rows, tt.rows) || !reflect.DeepEqual(data.Lines, tt.lines) {
+				t.Errorf("got %q %q %v, want %q %q %v", data.Columns, data.Rows, data.Lines, tt.cols, tt.rows, tt.lines)
+			}
+		})
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		cols  []string
+		rows  [][]string
+	}{
+		{
+			name:  "key order of the first record",
+			input: `[{"zeta": 1, "alpha": 2, "mid": 3}]`,
+			cols:  []string{"zeta", "alpha", "mid"},
+			rows:  [][]string{{"1", "2", "3"}},
+		},
+		{
+			name:  "keys added by later records",
+			input: `{"b": "x", "a": null}` + "\n" + `{"c": true, "b": 0.50}`,
+			cols:  []string{"b", "a", "c"},
+			rows:  [][]string{{"x", NullValue, NullValue}, {"0.50", NullValue, "1"}},
+		},
+		{
+			name:  "nested values",
+			input: `[{"tags": [1, 2], "meta": {"k": "v"}, "zip": "01234"}]`,
+			cols:  []string{"tags", "meta", "zip"},
+			rows:  [][]string{{"[1,2]", `{"k":"v"}`, "01234"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := readJSON(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(data.Columns, tt.cols) || !reflect.DeepEqual(data.Rows, tt.rows) {
+				t.Errorf("got %q %q, want %q %q", data.Columns, data.Rows, tt.cols, tt.rows)
+			}
+		})
+	}
+}
+
+func TestReadJSONRejectsNonObjects(t *testing.T) {
+	if _, err := readJSON(strings.NewReader(`[{"a": 1}, 2]`)); err == nil {
+		t.Error("expected an error for a record that is not an object")
+	}
+}
+
+func TestImport(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    string
+		format   ExportFormat
+		input    string
+		inserted int
+		rejected []int
+		query    string
+		want     string
+	}{
+		{
+			name:     "new table keeps leading zeros",
+			format:   FormatCSV,
+			input:    "id,zip\n1,01234\n2,98765\n",
+			inserted: 2,
+			query:    "SELECT group_concat(zip || ':' || typeof(zip)) FROM t",
+			want:     "01234:text,98765:text",
+		},
+		{
+			name:     "ragged rows are rejected",
+			format:   FormatCSV,
+			input:    "a,b\n1,2\n3\n4,5,6\n7,8\n",
+			inserted: 2,
+			rejected: []int{3, 4},
+			query:    "SELECT group_concat(a || b) FROM t",
+			want:     "12,78",
+		},
+		{
+			name:     "JSON columns matched by name",
+			setup:    "CREATE TABLE t(b TEXT, a INTEGER)",
+			format:   FormatJSON,
+			input:    `[{"a": 1, "b": "x"}, {"b": "y", "a": "2"}]`,
+			inserted: 2,
+			query:    "SELECT group_concat(a || typeof(a) || b) FROM t",
+			want:     "1integerx,2integery",
+		},
+		{
+			name:     "constraint violations are rejected",
+			setup:    "CREATE TABLE t(a INTEGER NOT NULL)",
+			format:   FormatCSV,
+			input:    "a\n1\n\n2\n\"\"\n",
+			inserted: 2,
+			rejected: []int{5},
+			query:    "SELECT group_concat(a) FROM t",
+			want:     "1,2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDatabase(t, tt.setup)
+			opts := DefaultImportOptions()
+			opts.Format = tt.format
+			data, err := ReadImport(strings.NewReader(tt.input), opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			plan, err := PlanImport(db, data, "t")
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := Import(db, data, plan)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var rejected []int
+			for _, r := range result.Rejected {
+				rejected = append(rejected, r.Line)
+			}
+			if result.Inserted != tt.inserted || !reflect.DeepEqual(rejected, tt.rejected) {
+				t.Errorf("inserted %d, rejected lines %v; want %d, %v", result.Inserted, rejected, tt.inserted, tt.rejected)
+			}
+			var got string
+			if err := db.QueryRow(tt.query).Scan(&got); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,528 @@
+package app
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-runewidth"
+)
+
+// Import messages
+type (
+	// SwitchToImportMsg opens the import dialog, optionally for a file and
+	// a target table
+	SwitchToImportMsg struct {
+		Path  string
+		Table string
+	}
+	// importDoneMsg reports a finished import
+	importDoneMsg struct {
+		Table  string
+		Result *ImportResult
+		Err    error
+	}
+)
+
+// importPreviewRows is the number of file rows shown before importing
+const importPreviewRows = 5
+
+// maxRejectedNotes limits the rejected rows listed in the message log
+const maxRejectedNotes = 10
+
+// Lines of the import dialog; the column mapping lines follow them
+const (
+	importFile = iota
+	importFormat
+	importHeader
+	importDelimiter
+	importNull
+	importTable
+	importFieldCount
+)
+
+// Column types offered for new tables; an empty type skips the column
+var importTypes = []string{"INTEGER", "REAL", "TEXT", "NUMERIC", "BLOB", ""}
+
+// ImportModel is the dialog that loads a CSV, TSV or JSON file into a table
+type ImportModel struct {
+	Shared       *SharedData
+	opts         ImportOptions
+	fileInput    textinput.Model
+	tableInput   textinput.Model
+	loadedPath   string
+	loadedTable  string
+	data         *ImportData
+	plan         *ImportPlan
+	names        []string // new column names, kept while a column is skipped
+	err          error
+	field        int
+	keyMap       ImportKeyMap
+	help         help.Model
+	showFullHelp bool
+	focused      bool
+	id           int
+}
+
+// ImportOption is a functional option for configuring ImportModel
+type ImportOption func(*ImportModel)
+
+// WithImportKeyMap sets the key map
+func WithImportKeyMap(km ImportKeyMap) ImportOption {
+	return func(m *ImportModel) {
+		m.keyMap = km
+	}
+}
+
+func NewImportModel(shared *SharedData, path, table string, opts ...ImportOption) *ImportModel {
+	fileInput := textinput.New()
+	fileInput.Placeholder = "data.csv"
+	fileInput.CharLimit = 1024
+	fileInput.Width = 50
+	fileInput.SetValue(path)
+	fileInput.Focus()
+
+	tableInput := textinput.New()
+	tableInput.Placeholder = "table name"
+	tableInput.CharLimit = 256
+	tableInput.Width = 50
+	tableInput.SetValue(table)
+
+	m := &ImportModel{
+		Shared:     shared,
+		opts:       DefaultImportOptions(),
+		fileInput:  fileInput,
+		tableInput: tableInput,
+		keyMap:     shared.keyMaps().Import,
+		help:       shared.theme().newHelp(),
+		focused:    true,
+		id:         nextID(),
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if path != "" {
+		m.loadFile()
+	}
+	return m
+}
+
+// ID returns the unique ID of the model
+func (m ImportModel) ID() int {
+	return m.id
+}
+
+// Focus sets the focus state
+func (m *ImportModel) Focus() {
+	m.focused = true
+	if input := m.input(); input != nil {
+		input.Focus()
+	}
+}
+
+// Blur removes focus
+func (m *ImportModel) Blur() {
+	m.focused = false
+	m.fileInput.Blur()
+	m.tableInput.Blur()
+}
+
+// Focused returns the focus state
+func (m ImportModel) Focused() bool {
+	return m.focused
+}
+
+// input returns the text input of the current field, if it has one
+func (m *ImportModel) input() *textinput.Model {
+	switch m.field {
+	case importFile:
+		return &m.fileInput
+	case importTable:
+		return &m.tableInput
+	}
+	return nil
+}
+
+// editingText reports whether keys are typed into the file or table name
+func (m *ImportModel) editingText() bool {
+	return m.input() != nil
+}
+
+func (m *ImportModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m *ImportModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if !m.focused {
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case ToggleHelpMsg:
+		m.showFullHelp = !m.showFullHelp
+		return m, nil
+
+	case tea.KeyMsg:
+		return m, m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m *ImportModel) handleKey(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.keyMap.Cancel):
+		return func() tea.Msg { return NavigateBackMsg{} }
+
+	case key.Matches(msg, m.keyMap.Import):
+		return m.runImport()
+
+	case key.Matches(msg, m.keyMap.NextField):
+		m.moveField(1)
+		return nil
+
+	case key.Matches(msg, m.keyMap.PrevField):
+		m.moveField(-1)
+		return nil
+	}
+
+	if input := m.input(); input != nil {
+		var cmd tea.Cmd
+		*input, cmd = input.Update(msg)
+		return cmd
+	}
+
+	switch {
+	case key.Matches(msg, m.keyMap.NextChoice):
+		m.cycle(1)
+	case key.Matches(msg, m.keyMap.PrevChoice):
+		m.cycle(-1)
+	}
+	return nil
+}
+
+// fieldCount returns the number of lines, counting the column mappings
+func (m *ImportModel) fieldCount() int {
+	if m.plan == nil {
+		return importFieldCount
+	}
+	return importFieldCount + len(m.plan.Columns)
+}
+
+// moveField moves to the next or previous line, skipping options that do
+// not apply to the format. Leaving the file or table name applies it.
+func (m *ImportModel) moveField(delta int) {
+	m.sync()
+	for {
+		m.field = wrapIndex(m.field+delta, m.fieldCount())
+		if m.applies(m.field) {
+			break
+		}
+	}
+	m.fileInput.Blur()
+	m.tableInput.Blur()
+	if input := m.input(); input != nil {
+		input.Focus()
+		input.CursorEnd()
+	}
+}
+
+// applies reports whether a field is used by the selected format
+func (m *ImportModel) applies(field int) bool {
+	switch format := m.opts.Format; field {
+	case importHeader, importNull:
+		return format == FormatCSV || format == FormatTSV
+	case importDelimiter:
+		return format == FormatCSV
+	}
+	return true
+}
+
+// sync reloads the file or the plan when their names were edited
+func (m *ImportModel) sync() {
+	path := strings.TrimSpace(m.fileInput.Value())
+	switch {
+	case path != m.loadedPath:
+		m.loadFile()
+	case strings.TrimSpace(m.tableInput.Value()) != m.loadedTable:
+		m.planImport()
+	}
+}
+
+// loadFile reads the file, taking the format from its extension when it
+// has a known one
+func (m *ImportModel) loadFile() {
+	path := strings.TrimSpace(m.fileInput.Value())
+	if path != m.loadedPath {
+		if format, err := ParseImportFormat(filepath.Ext(path)); err == nil {
+			m.opts.Format = format
+		}
+		if m.tableInput.Value() == "" || m.tableInput.Value() == tableNameFor(m.loadedPath) {
+			m.tableInput.SetValue(tableNameFor(path))
+		}
+	}
+	m.loadedPath = path
+	m.data, m.plan = nil, nil
+	if path == "" {
+		m.err = nil
+		return
+	}
+
+	f, err := os.Open(expandHome(path))
+	if err != nil {
+		m.err = err
+		return
+	}
+	defer f.Close()
+	if m.data, m.err = ReadImport(f, m.opts); m.err != nil {
+		return
+	}
+	m.planImport()
+}
+
+// tableNameFor suggests a table name for a file: its base name without
+// the extension
+func tableNameFor(path string) string {
+	if path == "" {
+		return ""
+	}
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// planImport maps the columns of the loaded file to the named table
+func (m *ImportModel) planImport() {
+	m.loadedTable = strings.TrimSpace(m.tableInput.Value())
+	m.plan = nil
+	if m.data == nil {
+		return
+	}
+	m.plan, m.err = PlanImport(m.Shared.DB, m.data, m.loadedTable)
+	if m.plan != nil {
+		m.names = make([]string, len(m.plan.Columns))
+		for i, col := range m.plan.Columns {
+			m.names[i] = col.Target
+		}
+	}
+	if m.field >= m.fieldCount() {
+		m.field = importTable
+	}
+}
+
+// cycle changes the option of the current field
+func (m *ImportModel) cycle(delta int) {
+	switch m.field {
+	case importFormat:
+		formats := ImportFormats()
+		m.opts.Format = formats[wrapIndex(indexOf(formats, m.opts.Format)+delta, len(formats))]
+	case importHeader:
+		m.opts.Header = !m.opts.Header
+	case importDelimiter:
+		m.opts.Delimiter = exportDelimiters[wrapIndex(indexOf(exportDelimiters, m.opts.Delimiter)+delta, len(exportDelimiters))]
+	case importNull:
+		m.opts.Null = exportNulls[wrapIndex(indexOf(exportNulls, m.opts.Null)+delta, len(exportNulls))]
+	default:
+		if m.plan != nil && m.field >= importFieldCount {
+			m.cycleMapping(m.field-importFieldCount, delta)
+		}
+		return
+	}
+	m.loadFile()
+}
+
+// cycleMapping picks the next type of a new column, or the next table
+// column for a file column. Both include skipping the column.
+func (m *ImportModel) cycleMapping(i, delta int) {
+	col := &m.plan.Columns[i]
+	if m.plan.Create {
+		current := col.Type
+		if col.Target == "" {
+			current = ""
+		}
+		typ := importTypes[wrapIndex(indexOf(importTypes, current)+delta, len(importTypes))]
+		if typ == "" {
+			col.Target = ""
+			return
+		}
+		col.Target, col.Type = m.names[i], typ
+		return
+	}
+
+	targets := []string{""}
+	for _, c := range m.plan.Existing {
+		targets = append(targets, c.Name)
+	}
+	col.Target = targets[wrapIndex(indexOf(targets, col.Target)+delta, len(targets))]
+}
+
+func (m *ImportModel) runImport() tea.Cmd {
+	m.sync()
+	if m.err != nil {
+		return NotifyError(m.err)
+	}
+	if m.data == nil || m.plan == nil {
+		return Notify(SeverityError, "enter the file to import")
+	}
+	data, plan, db := m.data, m.plan, m.Shared.DB
+	return tea.Batch(
+		importCmd(db, data, plan),
+		func() tea.Msg { return NavigateBackMsg{} },
+	)
+}
+
+// importCmd imports rows in the background
+func importCmd(db *sql.DB, data *ImportData, plan *ImportPlan) tea.Cmd {
+	return func() tea.Msg {
+		result, err := Import(db, data, plan)
+		return importDoneMsg{Table: plan.Table, Result: result, Err: err}
+	}
+}
+
+// importNotes reports an import: a summary followed by the rejected rows,
+// which land in the message log
+func importNotes(msg importDoneMsg) []tea.Cmd {
+	if msg.Err != nil {
+		return []tea.Cmd{NotifyError(fmt.Errorf("import failed: %w", msg.Err))}
+	}
+	r := msg.Result
+	if len(r.Rejected) == 0 {
+		return []tea.Cmd{Notify(SeverityInfo, "imported %d rows into %s", r.Inserted, msg.Table)}
+	}
+	cmds := []tea.Cmd{Notify(SeverityWarning, "imported %d rows into %s, rejected %d (see the message log)",
+		r.Inserted, msg.Table, len(r.Rejected))}
+	for i, rejected := range r.Rejected {
+		if i == maxRejectedNotes {
+			cmds = append(cmds, Notify(SeverityWarning, "%d more rows rejected", len(r.Rejected)-i))
+			break
+		}
+		cmds = append(cmds, Notify(SeverityWarning, "%s %d rejected: %v", r.Unit, rejected.Line, rejected.Err))
+	}
+	return cmds
+}
+
+func (m *ImportModel) View() string {
+	theme := m.Shared.theme()
+	var content strings.Builder
+
+	content.WriteString(theme.Title.Render("Import"))
+	content.WriteString("\n\n")
+
+	delimiter := map[rune]string{',': "comma", ';': "semicolon", '|': "pipe", '\t': "tab"}[m.opts.Delimiter]
+	null := m.opts.Null
+	if null == "" {
+		null = "(empty)"
+	}
+	onOff := map[bool]string{true: "on", false: "off"}
+	table := m.tableInput.View()
+	if m.plan != nil {
+		if m.plan.Create {
+			table += " (new)"
+		} else {
+			table += " (existing)"
+		}
+	}
+
+	lines := []struct {
+		field int
+		label string
+		value string
+	}{
+		{importFile, "File", m.fileInput.View()},
+		{importFormat, "Format", string(m.opts.Format)},
+		{importHeader, "Header", onOff[m.opts.Header]},
+		{importDelimiter, "Delimiter", delimiter},
+		{importNull, "NULL as", null},
+		{importTable, "Table", table},
+	}
+	for _, line := range lines {
+		if !m.applies(line.field) {
+			continue
+		}
+		value := line.value
+		if line.field != importFile && line.field != importTable {
+			value = "‹ " + value + " ›"
+		}
+		m.writeLine(&content, line.field, fmt.Sprintf("%-10s %s", line.label+":", value))
+	}
+
+	if m.err != nil {
+		content.WriteString("\n")
+		content.WriteString(theme.Error.Render("Error: " + m.err.Error()))
+		content.WriteString("\n")
+	}
+
+	if m.plan != nil {
+		content.WriteString("\n")
+		content.WriteString(theme.Title.Render("Columns"))
+		content.WriteString("\n")
+		for i, col := range m.plan.Columns {
+			target := "(skip)"
+			if col.Target != "" {
+				target = col.Target
+				if m.plan.Create {
+					target += " " + col.Type
+				}
+			}
+			m.writeLine(&content, importFieldCount+i, fmt.Sprintf("%s → ‹ %s ›", col.Source, target))
+		}
+	}
+
+	if m.data != nil {
+		content.WriteString("\n")
+		content.WriteString(theme.Title.Render(fmt.Sprintf("Preview (%d rows)", len(m.data.Rows))))
+		content.WriteString("\n")
+		content.WriteString(m.preview())
+	}
+
+	content.WriteString("\n")
+	if m.showFullHelp {
+		content.WriteString(m.help.FullHelpView(m.keyMap.FullHelp()))
+	} else {
+		content.WriteString(m.help.ShortHelpView(m.keyMap.ShortHelp()))
+	}
+
+	return content.String()
+}
+
+func (m *ImportModel) writeLine(content *strings.Builder, field int, text string) {
+	theme := m.Shared.theme()
+	if field == m.field {
+		content.WriteString(theme.Selected.Render("> " + text))
+	} else {
+		content.WriteString(theme.Normal.Render("  " + text))
+	}
+	content.WriteString("\n")
+}
+
+// preview draws the first rows of the file as a table cut to the width of
+// the screen
+func (m *ImportModel) preview() string {
+	var b strings.Builder
+	if err := m.data.Preview(&b, importPreviewRows); err != nil {
+		return err.Error() + "\n"
+	}
+	lines := strings.Split(b.String(), "\n")
+	for i, line := range lines {
+		lines[i] = runewidth.Truncate(line, max(m.Shared.Width-2, 20), "…")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// importFile opens the import dialog, into the open table when there is
+// one
+func (m *Model) importFile(args []string) tea.Cmd {
+	path := strings.Join(args, " ")
+	table := ""
+	if v, ok := m.currentView.(*TableDataModel); ok {
+		table = v.Shared.currentTableName()
+	}
+	return func() tea.Msg { return SwitchToImportMsg{Path: path, Table: table} }
+}
@@ -0,0 +1,262 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// pane identifies a part of the split layout
+type pane int
+
+const (
+	paneMain pane = iota
+	paneSidebar
+	paneDetail
+)
+
+const (
+	minMainPaneWidth = 30
+	sidebarMinWidth  = 18
+	sidebarMaxWidth  = 30
+)
+
+// focusable is implemented by every view
+type focusable interface {
+	Focus()
+	Blur()
+}
+
+// paneSizes returns the outer widths of the sidebar, main and detail panes
+// and their height. The detail pane is dropped when the terminal is narrow.
+func (m *Model) paneSizes() (sidebar, main, detail, height int) {
+	height = m.height - m.tabBarHeight()
+	sidebar = Min(sidebarMaxWidth, Max(sidebarMinWidth, m.width/5))
+	detail = m.width / 3
+	if m.width-sidebar-detail < minMainPaneWidth {
+		detail = 0
+	}
+	main = m.width - sidebar - detail
+	return sidebar, main, detail, height
+}
+
+// toggleSplit switches between the full screen and the split layout
+func (m *Model) toggleSplit() tea.Cmd {
+	m.split = !m.split
+	m.focus = paneMain
+	m.detail, m.detailKey = nil, ""
+	if !m.split {
+		m.sidebar = nil
+		m.resize()
+		return nil
+	}
+
+	// The sidebar gets its own shared data so searching it does not change
+	// the table shown in the main pane
+	shared := m.newSharedData()
+	if err := shared.LoadTables(); err != nil {
+		m.split = false
+		return NotifyError(fmt.Errorf("failed to load tables: %w", err))
+	}
+	m.sidebar = NewTableListModel(shared)
+	m.resize()
+	return nil
+}
+
+// cycleFocus moves the focus to the next visible pane
+func (m *Model) cycleFocus() {
+	_, _, detail, _ := m.paneSizes()
+	switch m.focus {
+	case paneSidebar:
+		m.focus = paneMain
+	case paneMain:
+		if detail > 0 && m.detail != nil {
+			m.focus = paneDetail
+		} else {
+			m.focus = paneSidebar
+		}
+	default:
+		m.focus = paneSidebar
+	}
+}
+
+// syncPanes sizes the panes, rebuilds the detail pane when the main view
+// moved to another row or table and applies the pane focus
+func (m *Model) syncPanes() {
+	if m.err != nil {
+		return
+	}
+	if m.split {
+		m.resize()
+		m.refreshDetail()
+		if m.focus == paneDetail && m.detail == nil {
+			m.focus = paneMain
+		}
+		setFocus(m.sidebar, m.focus == paneSidebar)
+		setFocus(m.detail, m.focus == paneDetail)
+	}
+	setFocus(m.currentView, !m.split || m.focus == paneMain)
+}
+
+func setFocus(view tea.Model, focused bool) {
+	v, ok := view.(focusable)
+	if !ok {
+		return
+	}
+	if focused {
+		v.Focus()
+	} else {
+		v.Blur()
+	}
+}
+
+// refreshDetail shows the selected row of the main grid in the detail pane,
+// or the schema of the current table
+func (m *Model) refreshDetail() {
+	_, _, width, height := m.paneSizes()
+	if width == 0 {
+		m.detail, m.detailKey = nil, ""
+		return
+	}
+
+	main := m.getSharedData()
+	table := ""
+	switch m.currentView.(type) {
+	case *TableDataModel, *RowDetailModel, *EditCellModel:
+		if !main.IsQueryResult {
+			table = main.currentTableName()
+		}
+	}
+	row := -1
+	if grid, ok := m.currentView.(*TableDataModel); ok && !m.showSchema && len(main.FilteredData) > 0 {
+		row = grid.selectedRow
+	}
+
+	// The data pointer changes whenever rows are reloaded or filtered
+	key := fmt.Sprintf("%s/%t/%d/%p/%d", table, m.showSchema, row, main.FilteredData, main.CurrentPage)
+	if key == m.detailKey && m.detail != nil {
+		return
+	}
+	m.detailKey = key
+
+	// A copy of the shared data lets the pane use its own size
+	shared := *main
+	shared.Width, shared.Height = width-2, height-2
+	switch {
+	case row >= 0:
+		m.detail = NewRowDetailModel(&shared, row)
+	case table != "":
+		m.detail = NewSchemaModel(&shared, table)
+	default:
+		m.detail = nil
+	}
+}
+
+// handlePaneMsg deals with messages sent by the sidebar or detail pane.
+// It reports whether the message was fully handled.
+func (m *Model) handlePaneMsg(msg tea.Msg) (tea.Cmd, bool) {
+	if !m.split || m.focus == paneMain {
+		return nil, false
+	}
+
+	switch msg := msg.(type) {
+	case SwitchToTableDataMsg:
+		if m.focus != paneSidebar {
+			return nil, false
+		}
+		// The index refers to the sidebar's filtered list
+		tables := m.sidebar.Shared.FilteredTables
+		if msg.TableIndex >= len(tables) {
+			return nil, true
+		}
+		shared := m.getSharedData()
+		entry := m.current()
+		if err := shared.OpenTable(tables[msg.TableIndex], nil); err != nil {
+			*shared = entry.shared
+			return NotifyError(fmt.Errorf("failed to load table: %w", err)), true
+		}
+		m.record(entry)
+		m.currentView = NewTableDataModel(shared)
+		m.focus = paneMain
+		return nil, true
+
+	case NavigateBackMsg:
+		// Leaving a side pane returns to the main pane
+		m.focus = paneMain
+		return nil, true
+
+	case SwitchToEditCellMsg, SwitchToRowDetailMsg, SwitchToQueryMsg,
+		SwitchToExportMsg, SwitchToImportMsg, SwitchToStatusMsg, FollowForeignKeyMsg, OpenReferencesMsg:
+		m.focus = paneMain
+	}
+	return nil, false
+}
+
+// updateFocusedPane sends a key to the sidebar or detail pane when one of
+// them has the focus
+func (m *Model) updateFocusedPane(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if !m.split {
+		return nil, false
+	}
+	var cmd tea.Cmd
+	switch m.focus {
+	case paneSidebar:
+		_, cmd = m.sidebar.Update(msg)
+	case paneDetail:
+		if m.detail == nil {
+			return nil, false
+		}
+		m.detail, cmd = m.detail.Update(msg)
+	default:
+		return nil, false
+	}
+	return cmd, true
+}
+
+// handleLayoutKeys runs the layout key bindings, reporting whether msg was one
+func (m *Model) handleLayoutKeys(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, m.keyMap.ToggleSplit):
+		return m.toggleSplit(), true
+	case key.Matches(msg, m.keyMap.NextPane):
+		if m.split {
+			m.cycleFocus()
+		}
+		return nil, true
+	case key.Matches(msg, m.keyMap.ToggleSchema):
+		if m.split {
+			m.showSchema = !m.showSchema
+		}
+		return nil, true
+	}
+	return nil, false
+}
+
+// splitView renders the sidebar, main and detail panes side by side
+func (m *Model) splitView() string {
+	sidebar, main, detail, height := m.paneSizes()
+	panes := []string{
+		renderPane(m.sidebar.View(), sidebar, height, m.focus == paneSidebar, m.settings.Theme),
+		renderPane(m.currentView.View(), main, height, m.focus == paneMain, m.settings.Theme),
+	}
+	if detail > 0 {
+		content := m.settings.Theme.Help.Render("No details")
+		if m.detail != nil {
+			content = m.detail.View()
+		}
+		panes = append(panes, renderPane(content, detail, height, m.focus == paneDetail, m.settings.Theme))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, panes...)
+}
+
+// renderPane clips content to a bordered box of the given outer size
+func renderPane(content string, width, height int, focused bool, theme *Theme) string {
+	inner := lipgloss.NewStyle().MaxWidth(width - 2).MaxHeight(height - 2).Render(content)
+	style := theme.Pane
+	if focused {
+		style = theme.FocusedPane
+	}
+	return style.Width(width - 2).Height(height - 2).Render(inner)
+}
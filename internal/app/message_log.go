@@ -0,0 +1,219 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-runewidth"
+)
+
+// Message log navigation messages
+type (
+	SwitchToMessageLogMsg struct{}
+	CloseMessageLogMsg    struct{}
+	ClearMessageLogMsg    struct{}
+)
+
+// MessageLogModel lists every notification raised during the session
+type MessageLogModel struct {
+	Shared       *SharedData
+	notifier     *Notifier
+	selected     int
+	gPressed     bool
+	keyMap       MessageLogKeyMap
+	help         help.Model
+	showFullHelp bool
+	focused      bool
+	id           int
+}
+
+// MessageLogOption is a functional option for configuring MessageLogModel
+type MessageLogOption func(*MessageLogModel)
+
+// WithMessageLogKeyMap sets the key map
+func WithMessageLogKeyMap(km MessageLogKeyMap) MessageLogOption {
+	return func(m *MessageLogModel) {
+		m.keyMap = km
+	}
+}
+
+func NewMessageLogModel(shared *SharedData, notifier *Notifier, opts ...MessageLogOption) *MessageLogModel {
+	m := &MessageLogModel{
+		Shared:   shared,
+		notifier: notifier,
+		keyMap:   shared.keyMaps().MessageLog,
+		help:     shared.theme().newHelp(),
+		focused:  true,
+		id:       nextID(),
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	// Start at the most recent entry
+	m.selected = Max(0, len(notifier.Log())-1)
+
+	return m
+}
+
+// ID returns the unique ID of the model
+func (m MessageLogModel) ID() int {
+	return m.id
+}
+
+// Focus sets the focus state
+func (m *MessageLogModel) Focus() {
+	m.focused = true
+}
+
+// Blur removes focus
+func (m *MessageLogModel) Blur() {
+	m.focused = false
+}
+
+// Focused returns the focus state
+func (m MessageLogModel) Focused() bool {
+	return m.focused
+}
+
+func (m *MessageLogModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *MessageLogModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if !m.focused {
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case ToggleHelpMsg:
+		m.showFullHelp = !m.showFullHelp
+		return m, nil
+
+	case tea.KeyMsg:
+		return m.handleNavigation(msg)
+
+	case tea.MouseMsg:
+		// The wheel moves the selection
+		if delta := wheelDelta(msg); delta != 0 {
+			m.selected = Max(0, Min(len(m.notifier.Log())-1, m.selected+delta))
+		}
+		return m, nil
+	}
+	return m, nil
+}
+
+func (m *MessageLogModel) handleNavigation(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	entries := m.notifier.Log()
+
+	switch {
+	case key.Matches(msg, m.keyMap.Back):
+		m.gPressed = false
+		return m, func() tea.Msg { return CloseMessageLogMsg{} }
+
+	case key.Matches(msg, m.keyMap.Clear):
+		m.gPressed = false
+		m.selected = 0
+		return m, func() tea.Msg { return ClearMessageLogMsg{} }
+
+	case key.Matches(msg, m.keyMap.CommandLine):
+		m.gPressed = false
+		return m, func() tea.Msg { return OpenCommandLineMsg{} }
+
+	case key.Matches(msg, m.keyMap.GoToStart):
+		if m.gPressed {
+			// Second g - go to beginning (gg pattern like vim)
+			m.selected = 0
+			m.gPressed = false
+		} else {
+			// First g - wait for second g to complete gg sequence
+			m.gPressed = true
+		}
+
+	case key.Matches(msg, m.keyMap.GoToEnd):
+		m.gPressed = false
+		m.selected = Max(0, len(entries)-1)
+
+	case key.Matches(msg, m.keyMap.Up):
+		m.gPressed = false
+		if m.selected > 0 {
+			m.selected--
+		}
+
+	case key.Matches(msg, m.keyMap.Down):
+		m.gPressed = false
+		if m.selected < len(entries)-1 {
+			m.selected++
+		}
+
+	default:
+		// Any other key resets the g state
+		m.gPressed = false
+	}
+	return m, nil
+}
+
+func (m *MessageLogModel) View() string {
+	theme := m.Shared.theme()
+	var content strings.Builder
+
+	content.WriteString(theme.Title.Render("Messages"))
+	content.WriteString("\n\n")
+
+	entries := m.notifier.Log()
+	if len(entries) == 0 {
+		content.WriteString("No messages")
+		content.WriteString("\n")
+	} else {
+		visibleCount := Max(1, m.Shared.Height-8)
+		startIdx := 0
+		if m.selected >= visibleCount {
+			startIdx = m.selected - visibleCount + 1
+		}
+		endIdx := Min(len(entries), startIdx+visibleCount)
+
+		for i := startIdx; i < endIdx; i++ {
+			entry := entries[i]
+			line := fmt.Sprintf("%s %-7s %s %s",
+				entry.Time.Format("15:04:05"),
+				entry.Severity,
+				severityIcon(entry.Severity),
+				strings.ReplaceAll(entry.Message, "\n", " "))
+			line = runewidth.Truncate(line, Max(20, m.Shared.Width-4), "...")
+
+			switch {
+			case i == m.selected:
+				content.WriteString(theme.Selected.Render("> " + line))
+			case entry.Severity == SeverityError:
+				content.WriteString(theme.Error.Render("  " + line))
+			default:
+				content.WriteString(theme.Normal.Render("  " + line))
+			}
+			content.WriteString("\n")
+		}
+
+		// Show the full text of the selected entry when it was truncated
+		if m.selected < len(entries) {
+			selected := entries[m.selected].Message
+			if len(selected) > m.Shared.Width-30 {
+				content.WriteString("\n")
+				content.WriteString(strings.Join(WrapText(selected, Max(20, m.Shared.Width-2)), "\n"))
+				content.WriteString("\n")
+			}
+		}
+	}
+
+	content.WriteString("\n")
+	if m.showFullHelp {
+		content.WriteString(m.help.FullHelpView(m.keyMap.FullHelp()))
+	} else {
+		content.WriteString(m.help.ShortHelpView(m.keyMap.ShortHelp()))
+	}
+
+	return content.String()
+}
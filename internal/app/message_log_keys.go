@@ -0,0 +1,66 @@
+package app
+
+import "github.com/charmbracelet/bubbles/key"
+
+// MessageLogKeyMap defines keybindings for the message log view
+type MessageLogKeyMap struct {
+	Up          key.Binding
+	Down        key.Binding
+	GoToStart   key.Binding
+	GoToEnd     key.Binding
+	Clear       key.Binding
+	Back        key.Binding
+	CommandLine key.Binding
+	ToggleHelp  key.Binding
+}
+
+// DefaultMessageLogKeyMap returns the default keybindings for the message log
+func DefaultMessageLogKeyMap() MessageLogKeyMap {
+	return MessageLogKeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		GoToStart: key.NewBinding(
+			key.WithKeys("g"),
+			key.WithHelp("gg", "go to start"),
+		),
+		GoToEnd: key.NewBinding(
+			key.WithKeys("G"),
+			key.WithHelp("G", "go to end"),
+		),
+		Clear: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "clear log"),
+		),
+		Back: key.NewBinding(
+			key.WithKeys("esc", "q"),
+			key.WithHelp("esc/q", "back"),
+		),
+		CommandLine: key.NewBinding(
+			key.WithKeys(":"),
+			key.WithHelp(":", "command"),
+		),
+		ToggleHelp: key.NewBinding(
+			key.WithKeys("ctrl+g"),
+			key.WithHelp("ctrl+g", "toggle help"),
+		),
+	}
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view
+func (k MessageLogKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Clear, k.Back, k.ToggleHelp}
+}
+
+// FullHelp returns keybindings for the expanded help view
+func (k MessageLogKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.GoToStart, k.GoToEnd},
+		{k.Clear, k.Back, k.ToggleHelp, k.CommandLine},
+	}
+}
@@ -0,0 +1,226 @@
+package app
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-runewidth"
+)
+
+// doubleClickInterval is the longest gap between two clicks on the same
+// item that still counts as a double click
+const doubleClickInterval = 400 * time.Millisecond
+
+const (
+	defaultColumnWidth = 15
+	minColumnWidth     = 3
+	// gridIndent is the width of the "> " selection marker before a row
+	gridIndent = 2
+	// columnSeparator is drawn between grid cells
+	columnSeparator = " | "
+)
+
+// clickTracker turns two clicks on the same item into a double click
+type clickTracker struct {
+	at   time.Time
+	item int
+}
+
+// click records a click on item and reports whether it completes a double
+// click
+func (c *clickTracker) click(item int) bool {
+	now := time.Now()
+	double := item == c.item && now.Sub(c.at) < doubleClickInterval
+	c.at, c.item = now, item
+	if double {
+		// A third click starts over
+		c.at = time.Time{}
+	}
+	return double
+}
+
+// itemLines maps the lines of a rendered view back to the items drawn on
+// them, so a click can be resolved to the item under the pointer
+type itemLines []int
+
+// mark records that the next n lines written to b show item
+func (l *itemLines) mark(b *strings.Builder, item, n int) {
+	y := strings.Count(b.String(), "\n")
+	for len(*l) < y+n {
+		*l = append(*l, -1)
+	}
+	for i := y; i < y+n; i++ {
+		(*l)[i] = item
+	}
+}
+
+// at returns the item drawn on line y, or -1
+func (l itemLines) at(y int) int {
+	if y < 0 || y >= len(l) {
+		return -1
+	}
+	return l[y]
+}
+
+// columnWidths holds the grid column widths changed by dragging a column
+// border; other columns use defaultColumnWidth
+type columnWidths map[int]int
+
+func (w columnWidths) width(col int) int {
+	if width, ok := w[col]; ok {
+		return width
+	}
+	return defaultColumnWidth
+}
+
+// hit returns the column under x for a grid of n columns and whether x is
+// on the border to the right of it, where dragging resizes the column
+func (w columnWidths) hit(x, n int) (col int, border bool) {
+	start := gridIndent
+	for col = 0; col < n; col++ {
+		end := start + w.width(col)
+		if x < start {
+			return -1, false
+		}
+		if x < end {
+			return col, false
+		}
+		if x < end+len(columnSeparator) {
+			return col, true
+		}
+		start = end + len(columnSeparator)
+	}
+	return -1, false
+}
+
+// start returns the x position where a column's cells begin
+func (w columnWidths) start(col int) int {
+	x := gridIndent
+	for i := 0; i < col; i++ {
+		x += w.width(i) + len(columnSeparator)
+	}
+	return x
+}
+
+// fitCell truncates or pads s to exactly width cells
+func fitCell(s string, width int) string {
+	if runewidth.StringWidth(s) > width {
+		s = runewidth.Truncate(s, width, "...")
+	}
+	return runewidth.FillRight(s, width)
+}
+
+// gridHeader renders the column names lined up with the rows of a grid.
+// mark returns a suffix for a column name, e.g. a sort indicator.
+func (t *Theme) gridHeader(columns []string, widths columnWidths, mark func(col int) string) string {
+	cells := make([]string, len(columns))
+	for i, col := range columns {
+		suffix := ""
+		if mark != nil {
+			suffix = mark(i)
+		}
+		width := widths.width(i)
+		cells[i] = fitCell(runewidth.Truncate(col, width-runewidth.StringWidth(suffix), "...")+suffix, width)
+	}
+	return t.Title.UnsetPadding().Render(strings.Repeat(" ", gridIndent) + strings.Join(cells, columnSeparator) + " ")
+}
+
+// columnDrag tracks a column border being dragged with the mouse
+type columnDrag struct {
+	active bool
+	col    int
+}
+
+// update resizes the dragged column to follow the pointer, reporting
+// whether msg belonged to the drag
+func (d *columnDrag) update(msg tea.MouseMsg, widths columnWidths) bool {
+	if !d.active {
+		return false
+	}
+	switch msg.Action {
+	case tea.MouseActionMotion:
+		widths[d.col] = Max(minColumnWidth, msg.X-widths.start(d.col))
+	case tea.MouseActionRelease:
+		d.active = false
+	}
+	return true
+}
+
+// isClick reports whether msg is a left button press
+func isClick(msg tea.MouseMsg) bool {
+	return msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft
+}
+
+// wheelDelta returns -1 or 1 for a wheel scroll up or down, and 0 for any
+// other mouse event
+func wheelDelta(msg tea.MouseMsg) int {
+	if msg.Action != tea.MouseActionPress {
+		return 0
+	}
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		return -1
+	case tea.MouseButtonWheelDown:
+		return 1
+	}
+	return 0
+}
+
+// handleMouse sends a mouse event to the view under the pointer, with the
+// coordinates made relative to that view. Clicking a tab selects it and
+// pressing a button in a pane focuses the pane; the following motion and
+// release events go to the same pane so drags can leave it.
+func (m *Model) handleMouse(msg tea.MouseMsg) tea.Cmd {
+	if m.err != nil || m.renaming || m.palette != nil || m.cmdLine.active {
+		return nil
+	}
+	if m.tabBarView() != "" {
+		if msg.Y == 0 {
+			if i := m.tabAt(msg.X); i >= 0 && isClick(msg) {
+				m.selectTab(i)
+			}
+			return nil
+		}
+		msg.Y--
+	}
+	if !m.split {
+		var cmd tea.Cmd
+		m.currentView, cmd = m.currentView.Update(msg)
+		return cmd
+	}
+
+	sidebar, main, _, _ := m.paneSizes()
+	if msg.Action == tea.MouseActionPress {
+		switch {
+		case msg.X < sidebar:
+			m.mousePane = paneSidebar
+		case msg.X < sidebar+main || m.detail == nil:
+			m.mousePane = paneMain
+		default:
+			m.mousePane = paneDetail
+		}
+		if m.focus != m.mousePane {
+			m.focus = m.mousePane
+			m.syncPanes()
+		}
+	}
+
+	// Inside the pane border
+	msg.Y--
+	var cmd tea.Cmd
+	switch m.mousePane {
+	case paneSidebar:
+		msg.X--
+		_, cmd = m.sidebar.Update(msg)
+	case paneDetail:
+		msg.X -= sidebar + main + 1
+		if m.detail != nil {
+			m.detail, cmd = m.detail.Update(msg)
+		}
+	default:
+		msg.X -= sidebar + 1
+		m.currentView, cmd = m.currentView.Update(msg)
+	}
+	return cmd
+}
@@ -0,0 +1,166 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+// Severity is the importance of a notification
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+// String returns the label shown for the severity
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
+// Notification is a message shown as a toast and kept in the message log
+type Notification struct {
+	ID       int
+	Severity Severity
+	Message  string
+	Time     time.Time
+}
+
+// Notification messages
+type (
+	NotifyMsg struct {
+		Severity Severity
+		Message  string
+	}
+	dismissNotificationMsg struct{ ID int }
+)
+
+// Notify returns a command that shows a toast with the given severity
+func Notify(severity Severity, format string, args ...any) tea.Cmd {
+	msg := fmt.Sprintf(format, args...)
+	return func() tea.Msg {
+		return NotifyMsg{Severity: severity, Message: msg}
+	}
+}
+
+// NotifyError returns a command that reports err as an error toast, or nil
+// when err is nil
+func NotifyError(err error) tea.Cmd {
+	if err == nil {
+		return nil
+	}
+	return Notify(SeverityError, "%v", err)
+}
+
+// Toast display durations per severity
+var toastDurations = map[Severity]time.Duration{
+	SeverityInfo:    3 * time.Second,
+	SeverityWarning: 6 * time.Second,
+	SeverityError:   10 * time.Second,
+}
+
+const (
+	maxToasts     = 3
+	maxLogEntries = 500
+)
+
+// Notifier keeps the visible toasts and the history of all notifications
+type Notifier struct {
+	toasts []Notification
+	log    []Notification
+	nextID int
+}
+
+// Add records a notification and returns the command that dismisses its
+// toast once it expires
+func (n *Notifier) Add(severity Severity, message string) tea.Cmd {
+	n.nextID++
+	note := Notification{ID: n.nextID, Severity: severity, Message: message, Time: time.Now()}
+
+	n.log = append(n.log, note)
+	if len(n.log) > maxLogEntries {
+		n.log = n.log[len(n.log)-maxLogEntries:]
+	}
+	n.toasts = append(n.toasts, note)
+	if len(n.toasts) > maxToasts {
+		n.toasts = n.toasts[len(n.toasts)-maxToasts:]
+	}
+
+	id := note.ID
+	return tea.Tick(toastDurations[severity], func(time.Time) tea.Msg {
+		return dismissNotificationMsg{ID: id}
+	})
+}
+
+// Dismiss hides the toast with the given ID; it stays in the log
+func (n *Notifier) Dismiss(id int) {
+	for i, t := range n.toasts {
+		if t.ID == id {
+			n.toasts = append(n.toasts[:i], n.toasts[i+1:]...)
+			return
+		}
+	}
+}
+
+// DismissAll hides every toast
+func (n *Notifier) DismissAll() {
+	n.toasts = nil
+}
+
+// Log returns all recorded notifications, oldest first
+func (n *Notifier) Log() []Notification {
+	return n.log
+}
+
+// ClearLog empties the notification history
+func (n *Notifier) ClearLog() {
+	n.log = nil
+}
+
+func toastStyle(theme *Theme, severity Severity) lipgloss.Style {
+	switch severity {
+	case SeverityWarning:
+		return theme.WarningToast
+	case SeverityError:
+		return theme.ErrorToast
+	default:
+		return theme.InfoToast
+	}
+}
+
+func severityIcon(severity Severity) string {
+	switch severity {
+	case SeverityWarning:
+		return "⚠"
+	case SeverityError:
+		return "✗"
+	default:
+		return "ℹ"
+	}
+}
+
+// View renders the visible toasts, newest last, one per line
+func (n *Notifier) View(width int, theme *Theme) string {
+	if len(n.toasts) == 0 {
+		return ""
+	}
+	var lines []string
+	for _, t := range n.toasts {
+		text := fmt.Sprintf("%s %s", severityIcon(t.Severity), strings.ReplaceAll(t.Message, "\n", " "))
+		lines = append(lines, toastStyle(theme, t.Severity).Render(runewidth.Truncate(text, Max(10, width-2), "...")))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,37 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestNotifierViewTruncatesByWidth(t *testing.T) {
+	tests := []struct {
+		name     string
+		severity Severity
+		message  string
+	}{
+		{"ascii", SeverityInfo, strings.Repeat("a", 100)},
+		{"accents", SeverityWarning, strings.Repeat("é", 100)},
+		{"wide", SeverityError, strings.Repeat("表", 100)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var n Notifier
+			n.Add(tt.severity, tt.message)
+			view := n.View(40, DarkTheme())
+			if !utf8.ValidString(view) {
+				t.Fatalf("view cuts a character: %q", view)
+			}
+			if !strings.Contains(view, "...") {
+				t.Errorf("view is not truncated: %q", view)
+			}
+			if got := lipgloss.Width(view); got < 37 || got > 40 {
+				t.Errorf("view is %d columns wide, want 37 to 40: %q", got, view)
+			}
+		})
+	}
+}
@@ -0,0 +1,353 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sqliteHeader starts every SQLite database file
+const sqliteHeader = "SQLite format 3\x00"
+
+// IsSQLiteFile reports whether a file starts with the SQLite header
+func IsSQLiteFile(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	header := make([]byte, len(sqliteHeader))
+	if _, err := io.ReadFull(f, header); err != nil {
+		return false
+	}
+	return string(header) == sqliteHeader
+}
+
+// OpenFunc opens the database at path and returns the model that replaces
+// the picker
+type OpenFunc func(path string) (tea.Model, error)
+
+// pickerEntryKind tells the picker what choosing an entry does
+type pickerEntryKind int
+
+const (
+	pickerRecent pickerEntryKind = iota
+	pickerDir
+	pickerFile
+)
+
+// pickerEntry is one line of the picker
+type pickerEntry struct {
+	kind  pickerEntryKind
+	path  string
+	label string
+}
+
+// PickerModel chooses the database to open when teaqlite is started
+// without one: a recently used database or a file found by browsing
+// directories, which only lists SQLite files
+type PickerModel struct {
+	Shared       *SharedData
+	open         OpenFunc
+	recent       *RecentDatabases
+	dir          string
+	showHidden   bool
+	entries      []pickerEntry
+	selected     int
+	offset       int
+	err          error
+	keyMap       PickerKeyMap
+	help         help.Model
+	showFullHelp bool
+	focused      bool
+	id           int
+}
+
+// PickerOption is a functional option for configuring PickerModel
+type PickerOption func(*PickerModel)
+
+// WithPickerKeyMap sets the key map
+func WithPickerKeyMap(km PickerKeyMap) PickerOption {
+	return func(m *PickerModel) {
+		m.keyMap = km
+	}
+}
+
+// WithPickerConfig applies the theme and key bindings of a configuration
+func WithPickerConfig(c *Config) PickerOption {
+	return func(m *PickerModel) {
+		m.Shared.Settings = c.Settings
+		m.keyMap = c.Settings.KeyMaps.Picker
+		m.help = m.Shared.theme().newHelp()
+	}
+}
+
+// WithRecentDatabases sets the recently used databases to offer
+func WithRecentDatabases(r *RecentDatabases) PickerOption {
+	return func(m *PickerModel) {
+		m.recent = r
+	}
+}
+
+// WithPickerDir sets the directory to browse first
+func WithPickerDir(dir string) PickerOption {
+	return func(m *PickerModel) {
+		m.dir = dir
+	}
+}
+
+func NewPickerModel(open OpenFunc, opts ...PickerOption) *PickerModel {
+	shared := NewSharedData(nil)
+	m := &PickerModel{
+		Shared:  shared,
+		open:    open,
+		recent:  NewRecentDatabases(),
+		dir:     ".",
+		keyMap:  shared.keyMaps().Picker,
+		help:    shared.theme().newHelp(),
+		focused: true,
+		id:      nextID(),
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if dir, err := filepath.Abs(m.dir); err == nil {
+		m.dir = dir
+	}
+	m.load()
+	return m
+}
+
+// ID returns the unique ID of the model
+func (m PickerModel) ID() int {
+	return m.id
+}
+
+// Focus sets the focus state
+func (m *PickerModel) Focus() {
+	m.focused = true
+}
+
+// Blur removes focus
+func (m *PickerModel) Blur() {
+	m.focused = false
+}
+
+// Focused returns the focus state
+func (m PickerModel) Focused() bool {
+	return m.focused
+}
+
+func (m *PickerModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *PickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.Shared.Width, m.Shared.Height = msg.Width, msg.Height
+		return m, nil
+	}
+
+	if !m.focused {
+		return m, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch {
+	case key.Matches(keyMsg, m.keyMap.Quit):
+		return m, tea.Quit
+	case key.Matches(keyMsg, m.keyMap.ToggleHelp):
+		m.showFullHelp = !m.showFullHelp
+	case key.Matches(keyMsg, m.keyMap.Up):
+		if m.selected > 0 {
+			m.selected--
+		}
+	case key.Matches(keyMsg, m.keyMap.Down):
+		if m.selected < len(m.entries)-1 {
+			m.selected++
+		}
+	case key.Matches(keyMsg, m.keyMap.Parent):
+		m.changeDir(filepath.Dir(m.dir))
+	case key.Matches(keyMsg, m.keyMap.ToggleHidden):
+		m.showHidden = !m.showHidden
+		m.load()
+	case key.Matches(keyMsg, m.keyMap.Forget):
+		if m.selected < len(m.entries) && m.entries[m.selected].kind == pickerRecent {
+			if err := m.recent.Remove(m.entries[m.selected].path); err != nil {
+				m.err = fmt.Errorf("failed to update recent databases: %w", err)
+			}
+			m.load()
+		}
+	case key.Matches(keyMsg, m.keyMap.Open):
+		if m.selected >= len(m.entries) {
+			return m, nil
+		}
+		entry := m.entries[m.selected]
+		if entry.kind == pickerDir {
+			m.changeDir(entry.path)
+			return m, nil
+		}
+		return m.openDatabase(entry.path)
+	}
+	return m, nil
+}
+
+// openDatabase hands over to the model of the opened database, sized to
+// the terminal, or shows why the database cannot be opened
+func (m *PickerModel) openDatabase(path string) (tea.Model, tea.Cmd) {
+	next, err := m.open(path)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	size := tea.WindowSizeMsg{Width: m.Shared.Width, Height: m.Shared.Height}
+	return next, tea.Batch(next.Init(), func() tea.Msg { return size })
+}
+
+// changeDir browses another directory. Going up selects the directory
+// that was left.
+func (m *PickerModel) changeDir(dir string) {
+	previous := m.dir
+	m.dir = dir
+	m.load()
+	for i, e := range m.entries {
+		if e.kind == pickerDir && e.path == previous {
+			m.selected = i
+			return
+		}
+	}
+	// Otherwise select the first entry of the directory
+	for i, e := range m.entries {
+		if e.kind != pickerRecent {
+			m.selected = i
+			return
+		}
+	}
+}
+
+// load lists the recent databases that still exist, then the
+// subdirectories and SQLite files of the current directory
+func (m *PickerModel) load() {
+	m.err = nil
+	m.entries = m.entries[:0]
+	for _, path := range m.recent.Paths() {
+		if file := DatabaseFile(path); file != "" {
+			if _, err := os.Stat(file); err != nil {
+				continue
+			}
+		}
+		m.entries = append(m.entries, pickerEntry{kind: pickerRecent, path: path, label: path})
+	}
+
+	if parent := filepath.Dir(m.dir); parent != m.dir {
+		m.entries = append(m.entries, pickerEntry{kind: pickerDir, path: parent, label: "../"})
+	}
+	files, err := os.ReadDir(m.dir)
+	if err != nil {
+		m.err = err
+	}
+	var dirs, databases []pickerEntry
+	for _, f := range files {
+		if !m.showHidden && strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(m.dir, f.Name())
+		info, err := os.Stat(path)
+		switch {
+		case err != nil:
+		case info.IsDir():
+			dirs = append(dirs, pickerEntry{kind: pickerDir, path: path, label: f.Name() + "/"})
+		case info.Mode().IsRegular() && IsSQLiteFile(path):
+			label := fmt.Sprintf("%s  (%s)", f.Name(), formatSize(info.Size()))
+			databases = append(databases, pickerEntry{kind: pickerFile, path: path, label: label})
+		}
+	}
+	sort.Slice(dirs, func(i, j int) bool { return strings.ToLower(dirs[i].label) < strings.ToLower(dirs[j].label) })
+	m.entries = append(m.entries, dirs...)
+	m.entries = append(m.entries, databases...)
+
+	if m.selected >= len(m.entries) {
+		m.selected = Max(0, len(m.entries)-1)
+	}
+}
+
+func (m *PickerModel) View() string {
+	theme := m.Shared.theme()
+	var content strings.Builder
+
+	content.WriteString(theme.Title.Render("Open a database"))
+	content.WriteString("\n\n")
+
+	if m.err != nil {
+		content.WriteString(theme.Error.Render(fmt.Sprintf("Error: %v", m.err)))
+		content.WriteString("\n\n")
+	}
+
+	// Lay out all lines, then show the part around the selection
+	var lines []string
+	selectedLine := 0
+	section := pickerEntryKind(-1)
+	for i, e := range m.entries {
+		// Directories and files share the browse section
+		kind := e.kind
+		if kind == pickerFile {
+			kind = pickerDir
+		}
+		if kind != section {
+			section = kind
+			if len(lines) > 0 {
+				lines = append(lines, "")
+			}
+			title := "Recent:"
+			if kind == pickerDir {
+				title = "Browse " + m.dir + ":"
+			}
+			lines = append(lines, theme.Normal.Bold(true).Render(title))
+		}
+		label := TruncateString(e.label, Max(10, m.Shared.Width-2))
+		if i == m.selected {
+			selectedLine = len(lines)
+			lines = append(lines, theme.Selected.Render("> "+label))
+		} else {
+			lines = append(lines, theme.Normal.Render("  "+label))
+		}
+	}
+	if len(m.entries) == 0 || m.entries[len(m.entries)-1].kind != pickerFile {
+		lines = append(lines, theme.Help.Render("  No SQLite databases in this directory"))
+	}
+
+	visible := Max(3, m.Shared.Height-strings.Count(content.String(), "\n")-3)
+	if selectedLine < m.offset {
+		m.offset = selectedLine
+	} else if selectedLine >= m.offset+visible {
+		m.offset = selectedLine - visible + 1
+	}
+	m.offset = Max(0, Min(m.offset, len(lines)-visible))
+	for _, line := range lines[m.offset:Min(len(lines), m.offset+visible)] {
+		content.WriteString(line)
+		content.WriteString("\n")
+	}
+
+	content.WriteString("\n")
+	if m.showFullHelp {
+		content.WriteString(m.help.FullHelpView(m.keyMap.FullHelp()))
+	} else {
+		content.WriteString(m.help.ShortHelpView(m.keyMap.ShortHelp()))
+	}
+
+	return content.String()
+}
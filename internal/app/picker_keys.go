@@ -0,0 +1,66 @@
+package app
+
+import "github.com/charmbracelet/bubbles/key"
+
+// PickerKeyMap defines keybindings for the database picker
+type PickerKeyMap struct {
+	Up           key.Binding
+	Down         key.Binding
+	Open         key.Binding
+	Parent       key.Binding
+	ToggleHidden key.Binding
+	Forget       key.Binding
+	Quit         key.Binding
+	ToggleHelp   key.Binding
+}
+
+// DefaultPickerKeyMap returns the default keybindings for the database picker
+func DefaultPickerKeyMap() PickerKeyMap {
+	return PickerKeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		Open: key.NewBinding(
+			key.WithKeys("enter", "l"),
+			key.WithHelp("enter", "open"),
+		),
+		Parent: key.NewBinding(
+			key.WithKeys("backspace", "h"),
+			key.WithHelp("backspace", "parent directory"),
+		),
+		ToggleHidden: key.NewBinding(
+			key.WithKeys("."),
+			key.WithHelp(".", "toggle hidden files"),
+		),
+		Forget: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "remove from recent"),
+		),
+		Quit: key.NewBinding(
+			key.WithKeys("q", "esc", "ctrl+c"),
+			key.WithHelp("q", "quit"),
+		),
+		ToggleHelp: key.NewBinding(
+			key.WithKeys("ctrl+g"),
+			key.WithHelp("ctrl+g", "toggle help"),
+		),
+	}
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view
+func (k PickerKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Open, k.Parent, k.Quit, k.ToggleHelp}
+}
+
+// FullHelp returns keybindings for the expanded help view
+func (k PickerKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Open, k.Parent},
+		{k.ToggleHidden, k.Forget, k.Quit, k.ToggleHelp},
+	}
+}
@@ -2,30 +2,52 @@ package app
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
-	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 type QueryModel struct {
 	Shared       *SharedData
-	queryInput   textarea.Model
+	queryInput   SQLEditor
 	FocusOnInput bool
 	selectedRow  int
 	results      [][]string
 	columns      []string
+	types        []string
 	err          error
+	lastQuery    string
 	blinkState   bool
+	// Completion popup state
+	completions      []CompletionItem
+	completionPrefix string
+	completionIdx    int
+	showCompletions  bool
 	gPressed     bool
 	keyMap       QueryKeyMap
 	help         help.Model
 	showFullHelp bool
 	focused      bool
 	id           int
+	// Mouse state; the line positions are those drawn by the last View
+	widths      columnWidths
+	drag        columnDrag
+	clicks      clickTracker
+	sortCol     int
+	sortDesc    bool
+	unsorted    [][]string
+	editorLines [2]int
+	headerLine  int
+	lines       itemLines
+	// Result rows marked for copying
+	marks rowMarks
+	// Risky statements safe mode asks about before running the query
+	confirming []string
 }
 
 // QueryOption is a functional option for configuring QueryModel
@@ -39,10 +61,10 @@ func WithQueryKeyMap(km QueryKeyMap) QueryOption {
 }
 
 func NewQueryModel(shared *SharedData, opts ...QueryOption) *QueryModel {
-	queryInput := textarea.New()
+	queryInput := NewSQLEditor()
+	queryInput.KeyMap = shared.keyMaps().SQLEditor
+	queryInput.Theme = shared.theme()
 	queryInput.Placeholder = "Enter SQL query..."
-	queryInput.SetWidth(60)
-	queryInput.SetHeight(3)
 	queryInput.Focus()
 
 	m := &QueryModel{
@@ -51,10 +73,13 @@ func NewQueryModel(shared *SharedData, opts ...QueryOption) *QueryModel {
 		FocusOnInput: true,
 		selectedRow:  0,
 		blinkState:   true,
-		keyMap:       DefaultQueryKeyMap(),
-		help:         help.New(),
+		keyMap:       shared.keyMaps().Query,
+		help:         shared.theme().newHelp(),
 		focused:      true,
 		id:           nextID(),
+		widths:       columnWidths{},
+		sortCol:      -1,
+		headerLine:   -1,
 	}
 
 	// Apply options
@@ -62,6 +87,16 @@ func NewQueryModel(shared *SharedData, opts ...QueryOption) *QueryModel {
 		opt(m)
 	}
 
+	// The editor shares the cursor bindings of the query key map
+	m.queryInput.KeyMap.CharLeft = m.keyMap.CursorLeft
+	m.queryInput.KeyMap.CharRight = m.keyMap.CursorRight
+	m.queryInput.KeyMap.WordLeft = m.keyMap.WordLeft
+	m.queryInput.KeyMap.WordRight = m.keyMap.WordRight
+	m.queryInput.KeyMap.LineStart = m.keyMap.LineStart
+	m.queryInput.KeyMap.LineEnd = m.keyMap.LineEnd
+	m.queryInput.KeyMap.DeleteWord = m.keyMap.DeleteWord
+	m.queryInput.KeyMap.NewLine = m.keyMap.NewLine
+
 	return m
 }
 
@@ -90,12 +125,9 @@ func (m QueryModel) Focused() bool {
 }
 
 func (m *QueryModel) Init() tea.Cmd {
-	return tea.Batch(
-		textarea.Blink,
-		tea.Tick(time.Millisecond*500, func(t time.Time) tea.Msg {
-			return blinkMsg{}
-		}),
-	)
+	return tea.Tick(time.Millisecond*500, func(t time.Time) tea.Msg {
+		return blinkMsg{}
+	})
 }
 
 func (m *QueryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -116,48 +148,171 @@ func (m *QueryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return blinkMsg{}
 		}))
 
+	case ExternalEditorMsg:
+		if msg.ID != m.id {
+			return m, nil
+		}
+		if msg.Err != nil {
+			m.err = msg.Err
+			return m, nil
+		}
+		m.queryInput.SetValue(msg.Value)
+		m.FocusOnInput = true
+		m.queryInput.Focus()
+		m.closeCompletions()
+		return m, nil
+
 	case tea.KeyMsg:
+		if m.confirming != nil {
+			return m, m.handleConfirm(msg)
+		}
+		if key.Matches(msg, m.keyMap.OpenEditor) {
+			return m, openExternalEditor(m.id, m.queryInput.Value(), ".sql")
+		}
 		if m.FocusOnInput {
 			return m.handleQueryInput(msg)
 		}
 		return m.handleResultsNavigation(msg)
-	}
 
-	// Update query input for non-key messages when focused on input
-	if m.FocusOnInput {
-		var cmd tea.Cmd
-		m.queryInput, cmd = m.queryInput.Update(msg)
-		if cmd != nil {
-			cmds = append(cmds, cmd)
-		}
+	case tea.MouseMsg:
+		return m, m.handleMouse(msg)
 	}
 
 	return m, tea.Batch(cmds...)
 }
 
 func (m *QueryModel) handleQueryInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.showCompletions {
+		switch {
+		case key.Matches(msg, m.keyMap.AcceptCompletion):
+			m.acceptCompletion()
+			return m, nil
+		case key.Matches(msg, m.keyMap.NextCompletion):
+			m.completionIdx = (m.completionIdx + 1) % len(m.completions)
+			return m, nil
+		case key.Matches(msg, m.keyMap.PrevCompletion):
+			m.completionIdx = (m.completionIdx - 1 + len(m.completions)) % len(m.completions)
+			return m, nil
+		case key.Matches(msg, m.keyMap.Escape):
+			m.closeCompletions()
+			return m, nil
+		}
+	}
+
 	switch {
 	case key.Matches(msg, m.keyMap.Escape):
-		return m, func() tea.Msg { return SwitchToTableListClearMsg{} }
+		return m, func() tea.Msg { return NavigateBackMsg{} }
+
+	case key.Matches(msg, m.keyMap.YankQuery) && msg.Type != tea.KeyRunes:
+		// Letters are typed into the query
+		return m, func() tea.Msg { return RunCommandMsg{Line: "yank query"} }
 
 	case key.Matches(msg, m.keyMap.Execute):
 		if strings.TrimSpace(m.queryInput.Value()) != "" {
 			return m, m.executeQuery()
 		}
 
+	case key.Matches(msg, m.keyMap.Complete):
+		m.updateCompletions()
+		if len(m.completions) == 1 {
+			// A single candidate is inserted straight away
+			m.acceptCompletion()
+		}
+
 	default:
-		var cmd tea.Cmd
-		m.queryInput, cmd = m.queryInput.Update(msg)
+		cmd := m.queryInput.Update(msg)
+		if m.showCompletions {
+			m.updateCompletions()
+		}
 		return m, cmd
 	}
 	return m, nil
 }
 
+// updateCompletions recomputes the candidates for the word under the cursor
+// and shows the popup if there are any
+func (m *QueryModel) updateCompletions() {
+	m.completionPrefix, m.completions = m.Shared.Completer().Complete(m.queryInput.Value(), m.queryInput.CursorOffset())
+	m.showCompletions = len(m.completions) > 0
+	if m.completionIdx >= len(m.completions) {
+		m.completionIdx = 0
+	}
+}
+
+func (m *QueryModel) closeCompletions() {
+	m.showCompletions = false
+	m.completions = nil
+	m.completionIdx = 0
+}
+
+// acceptCompletion replaces the word under the cursor with the selected candidate
+func (m *QueryModel) acceptCompletion() {
+	if m.completionIdx >= len(m.completions) {
+		m.closeCompletions()
+		return
+	}
+	item := m.completions[m.completionIdx]
+
+	text := item.Text
+	switch item.Kind {
+	case CompletionKeyword:
+		// Follow the case the user started typing in
+		if m.completionPrefix != "" && m.completionPrefix == strings.ToLower(m.completionPrefix) {
+			text = strings.ToLower(text)
+		}
+	case CompletionFunction:
+		text += "("
+	case CompletionTable, CompletionView:
+		text = tableCompletionText(text)
+	case CompletionColumn:
+		if needsQuoting(text) {
+			text = quoteIdent(text)
+		}
+	}
+
+	m.queryInput.DeleteBefore(len([]rune(m.completionPrefix)))
+	m.queryInput.InsertString(text)
+	m.closeCompletions()
+}
+
+// tableCompletionText returns the text inserted for a table name from
+// qualifyTable, quoting the schema and the table separately where needed
+func tableCompletionText(name string) string {
+	parts := []string{name}
+	if strings.Contains(name, ".") {
+		schema, table := splitTable(name)
+		parts = []string{schema, table}
+	}
+	for i, part := range parts {
+		if needsQuoting(part) {
+			parts[i] = quoteIdent(part)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// needsQuoting reports whether name must be quoted to be used as an identifier
+func needsQuoting(name string) bool {
+	if name == "" || isSQLKeyword(name) {
+		return true
+	}
+	for i, r := range name {
+		if i == 0 && !isIdentStart(r) || !isIdentChar(r) {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *QueryModel) handleResultsNavigation(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
 	case key.Matches(msg, m.keyMap.Escape), key.Matches(msg, m.keyMap.Back):
 		m.gPressed = false
-		return m, func() tea.Msg { return SwitchToTableListClearMsg{} }
+		return m, func() tea.Msg { return NavigateBackMsg{} }
+
+	case key.Matches(msg, m.keyMap.CommandLine):
+		m.gPressed = false
+		return m, func() tea.Msg { return OpenCommandLineMsg{} }
 
 	case key.Matches(msg, m.keyMap.GoToStart):
 		if m.gPressed {
@@ -192,6 +347,30 @@ func (m *QueryModel) handleResultsNavigation(msg tea.KeyMsg) (tea.Model, tea.Cmd
 			}
 		}
 
+	case key.Matches(msg, m.keyMap.Mark):
+		m.gPressed = false
+		if m.selectedRow < len(m.results) {
+			m.marks.toggle(m.results[m.selectedRow])
+			if m.selectedRow < len(m.results)-1 {
+				m.selectedRow++
+			}
+		}
+
+	case key.Matches(msg, m.keyMap.YankRow):
+		m.gPressed = false
+		if len(m.marks.rows) > 0 {
+			return m, func() tea.Msg { return RunCommandMsg{Line: "yank rows"} }
+		}
+		return m, func() tea.Msg { return RunCommandMsg{Line: "yank row"} }
+
+	case key.Matches(msg, m.keyMap.YankQuery):
+		m.gPressed = false
+		return m, func() tea.Msg { return RunCommandMsg{Line: "yank query"} }
+
+	case key.Matches(msg, m.keyMap.Export):
+		m.gPressed = false
+		return m, func() tea.Msg { return RunCommandMsg{Line: "export"} }
+
 	case key.Matches(msg, m.keyMap.Up):
 		m.gPressed = false
 		if m.selectedRow > 0 {
@@ -293,7 +472,7 @@ func (m *QueryModel) extractTableName(query string) string {
 }
 
 func (m *QueryModel) getTablePrimaryKeys(tableName string) []string {
-	rows, err := m.Shared.DB.Query(fmt.Sprintf("PRAGMA table_info(%s)", tableName))
+	rows, err := m.Shared.DB.Query(tablePragma("table_info", tableName))
 	if err != nil {
 		return nil
 	}
@@ -318,21 +497,56 @@ func (m *QueryModel) getTablePrimaryKeys(tableName string) []string {
 	return primaryKeys
 }
 
+// executeQuery runs the query in the editor. In safe mode risky statements
+// are confirmed first.
 func (m *QueryModel) executeQuery() tea.Cmd {
+	m.closeCompletions()
+	if m.Shared.safeMode() && !m.Shared.readOnly() {
+		if risks := RiskyStatements(m.queryInput.Value()); len(risks) > 0 {
+			m.confirming = risks
+			return nil
+		}
+	}
+	return m.runQuery()
+}
+
+// handleConfirm runs or cancels a query waiting for confirmation
+func (m *QueryModel) handleConfirm(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.keyMap.ConfirmRun):
+		m.confirming = nil
+		return m.runQuery()
+	case key.Matches(msg, m.keyMap.CancelRun):
+		m.confirming = nil
+		return Notify(SeverityInfo, "query cancelled")
+	}
+	return nil
+}
+
+// runQuery runs the query in the editor in the background
+func (m *QueryModel) runQuery() tea.Cmd {
+	m.lastQuery = m.queryInput.Value()
 	return func() tea.Msg {
 		// Modify query to always include ID columns if it's a SELECT statement
-		modifiedQuery := m.ensureIDColumns(m.queryInput.Value())
+		modifiedQuery := m.ensureIDColumns(m.lastQuery)
 
-		rows, err := m.Shared.DB.Query(modifiedQuery)
+		// Dates are read as stored
+		stored, dates := storedQuery(m.Shared.DB, modifiedQuery)
+		rows, release, err := m.Shared.queryRows(stored)
+		if err != nil && stored != modifiedQuery {
+			rows, release, err = m.Shared.queryRows(modifiedQuery)
+		}
 		if err != nil {
-			return QueryCompletedMsg{Error: err}
+			return QueryCompletedMsg{ID: m.id, Error: err}
 		}
+		types := declaredTypes(rows, dates)
+		defer release()
 		defer rows.Close()
 
 		// Get column names
 		columns, err := rows.Columns()
 		if err != nil {
-			return QueryCompletedMsg{Error: err}
+			return QueryCompletedMsg{ID: m.id, Error: err}
 		}
 
 		// Get results
@@ -345,40 +559,162 @@ func (m *QueryModel) executeQuery() tea.Cmd {
 			}
 
 			if err := rows.Scan(valuePtrs...); err != nil {
-				return QueryCompletedMsg{Error: err}
+				return QueryCompletedMsg{ID: m.id, Error: err}
 			}
 
 			row := make([]string, len(columns))
 			for i, val := range values {
-				if val == nil {
-					row[i] = "NULL"
-				} else {
-					row[i] = fmt.Sprintf("%v", val)
-				}
+				row[i] = formatValue(val)
 			}
 			results = append(results, row)
 		}
 
 		return QueryCompletedMsg{
+			ID:      m.id,
 			Results: results,
 			Columns: columns,
+			Types:   types,
 			Error:   nil,
 		}
 	}
 }
 
+// handleMouse focuses the editor or the results, whichever was clicked.
+// Clicking a result row selects it and a double click opens it; clicking
+// a header sorts the results and dragging a header border resizes the
+// column.
+func (m *QueryModel) handleMouse(msg tea.MouseMsg) tea.Cmd {
+	if m.drag.update(msg, m.widths) {
+		return nil
+	}
+	if delta := wheelDelta(msg); delta != 0 {
+		if !m.FocusOnInput && len(m.results) > 0 {
+			m.selectedRow = Max(0, Min(len(m.results)-1, m.selectedRow+delta))
+		}
+		return nil
+	}
+	if !isClick(msg) {
+		return nil
+	}
+
+	if msg.Y >= m.editorLines[0] && msg.Y < m.editorLines[1] {
+		m.FocusOnInput = true
+		m.queryInput.Focus()
+		return nil
+	}
+
+	col, border := m.widths.hit(msg.X, len(m.columns))
+	if msg.Y == m.headerLine && col >= 0 {
+		if border {
+			m.drag = columnDrag{active: true, col: col}
+		} else {
+			m.sortBy(col)
+		}
+		return nil
+	}
+
+	row := m.lines.at(msg.Y)
+	if row < 0 {
+		return nil
+	}
+	m.FocusOnInput = false
+	m.queryInput.Blur()
+	m.closeCompletions()
+	m.selectedRow = row
+	if m.clicks.click(row) {
+		return func() tea.Msg { return SwitchToRowDetailFromQueryMsg{RowIndex: row} }
+	}
+	return nil
+}
+
+// sortBy cycles the order of a result column through ascending,
+// descending and the order the query returned
+func (m *QueryModel) sortBy(col int) {
+	if m.unsorted == nil {
+		m.unsorted = append([][]string(nil), m.results...)
+	}
+	switch {
+	case m.sortCol != col:
+		m.sortCol, m.sortDesc = col, false
+	case !m.sortDesc:
+		m.sortDesc = true
+	default:
+		m.sortCol = -1
+		copy(m.results, m.unsorted)
+		m.selectedRow = 0
+		return
+	}
+	// Sorting in place keeps the shared rows in the same order
+	sort.SliceStable(m.results, func(i, j int) bool {
+		if m.sortDesc {
+			return lessValue(m.results[j][col], m.results[i][col])
+		}
+		return lessValue(m.results[i][col], m.results[j][col])
+	})
+	m.selectedRow = 0
+}
+
+// lessValue orders NULL first, then numbers by value and other values as
+// text
+func lessValue(a, b string) bool {
+	if a == NullValue || b == NullValue {
+		return a == NullValue && b != NullValue
+	}
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+	switch {
+	case errA == nil && errB == nil:
+		return x < y
+	case errA == nil || errB == nil:
+		// Numbers sort before text, as in SQLite
+		return errA == nil
+	}
+	return a < b
+}
+
+// exportSource describes the last query and the selected results for
+// exporting
+func (m *QueryModel) exportSource() exportSource {
+	source := exportSource{
+		name:  "query",
+		grid:  newGridData(m.Shared),
+		query: m.lastQuery,
+	}
+	if len(m.marks.rows) > 0 {
+		source.selected = m.marks.rows
+	} else if m.selectedRow < len(m.results) {
+		source.selected = [][]string{m.results[m.selectedRow]}
+	}
+	return source
+}
+
 func (m *QueryModel) handleQueryCompletion(msg QueryCompletedMsg) {
+	// Pick up schema changes for completion and the table list
+	if isDDLStatement(m.lastQuery) {
+		if err := m.Shared.LoadTables(); err != nil && msg.Error == nil {
+			m.err = fmt.Errorf("query succeeded but reloading tables failed: %w", err)
+		}
+	}
+
 	if msg.Error != nil {
 		m.err = msg.Error
+		if start, end, ok := locateSQLError(m.lastQuery, msg.Error); ok && m.queryInput.Value() == m.lastQuery {
+			m.queryInput.MarkError(start, end)
+		}
 		return
 	}
 
 	m.results = msg.Results
 	m.columns = msg.Columns
+	m.types = msg.Types
+	m.widths = columnWidths{}
+	m.sortCol, m.unsorted = -1, nil
+	m.marks.clear()
 
 	// Update shared data for row detail view
 	m.Shared.FilteredData = m.results
 	m.Shared.Columns = m.columns
+	m.Shared.ResultTypes = m.types
 	m.Shared.IsQueryResult = true
 
 	m.FocusOnInput = false
@@ -387,38 +723,79 @@ func (m *QueryModel) handleQueryCompletion(msg QueryCompletedMsg) {
 	m.err = nil
 }
 
+// resume brings back the view's results when SQL mode is reopened
+func (m *QueryModel) resume() {
+	if m.results != nil {
+		m.Shared.FilteredData = m.results
+		m.Shared.Columns = m.columns
+		m.Shared.ResultTypes = m.types
+		m.Shared.IsQueryResult = true
+	}
+	m.FocusOnInput = true
+	m.queryInput.Focus()
+}
+
+// layoutEditor sizes the editor to the terminal, growing with the query up
+// to a third of the screen height
+func (m *QueryModel) layoutEditor() {
+	maxHeight := Max(3, m.Shared.Height/3)
+	height := Max(3, Min(m.queryInput.LineCount(), maxHeight))
+	m.queryInput.SetSize(m.Shared.Width-2, height)
+}
+
 func (m *QueryModel) View() string {
+	theme := m.Shared.theme()
+	m.layoutEditor()
+
 	var content strings.Builder
+	m.lines = m.lines[:0]
+	m.headerLine = -1
 
-	content.WriteString(TitleStyle.Render("SQL Query"))
+	content.WriteString(theme.Title.Render("SQL Query"))
 	content.WriteString("\n\n")
 
 	// Query input
 	content.WriteString("Query:\n")
+	m.editorLines[0] = strings.Count(content.String(), "\n")
 	content.WriteString(m.queryInput.View())
-	content.WriteString("\n\n")
+	content.WriteString("\n")
+	m.editorLines[1] = strings.Count(content.String(), "\n")
+	if m.showCompletions {
+		content.WriteString(m.completionView())
+		content.WriteString("\n")
+	}
+	content.WriteString("\n")
+
+	if m.confirming != nil {
+		content.WriteString(theme.WarningToast.Render("Safe mode: " + strings.Join(m.confirming, "; ")))
+		content.WriteString("\n")
+		content.WriteString(m.help.ShortHelpView([]key.Binding{m.keyMap.ConfirmRun, m.keyMap.CancelRun}))
+		content.WriteString("\n\n")
+	}
 
 	// Error display
 	if m.err != nil {
-		content.WriteString(ErrorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		content.WriteString(theme.Error.Render(fmt.Sprintf("Error: %v", m.err)))
 		content.WriteString("\n\n")
 	}
 
 	// Results
 	if len(m.results) > 0 {
 		// Column headers
-		headerRow := ""
-		for i, col := range m.columns {
-			if i > 0 {
-				headerRow += " | "
+		m.headerLine = strings.Count(content.String(), "\n")
+		content.WriteString(theme.gridHeader(m.columns, m.widths, func(col int) string {
+			switch {
+			case col != m.sortCol:
+				return ""
+			case m.sortDesc:
+				return "▼"
 			}
-			headerRow += TruncateString(col, 15)
-		}
-		content.WriteString(TitleStyle.Render(headerRow))
+			return "▲"
+		}))
 		content.WriteString("\n")
 
 		// Data rows with scrolling
-		visibleCount := Max(1, m.Shared.Height-10)
+		visibleCount := Max(1, m.Shared.Height-10-(m.queryInput.Height()-3))
 		startIdx := 0
 
 		// Adjust start index if selected row is out of view
@@ -433,28 +810,32 @@ func (m *QueryModel) View() string {
 				continue
 			}
 			row := m.results[i]
-			rowStr := ""
-			for j, cell := range row {
-				if j > 0 {
-					rowStr += " | "
-				}
-				rowStr += TruncateString(cell, 15)
-			}
-
+			m.lines.mark(&content, i, 1)
 			if i == m.selectedRow && !m.FocusOnInput {
-				content.WriteString(SelectedStyle.Render("> " + rowStr))
+				rowStr := ""
+				for j, cell := range row {
+					if j > 0 {
+						rowStr += " | "
+					}
+					rowStr += fitCell(m.Shared.displayValue(j, cell), m.widths.width(j))
+				}
+				content.WriteString(theme.Selected.Render(rowMarker(">", m.marks.has(row)) + rowStr))
 			} else {
-				content.WriteString(NormalStyle.Render("  " + rowStr))
+				content.WriteString(theme.renderRow(i, row, m.widths, m.marks.has(row), m.Shared.displayValue, nil))
 			}
 			content.WriteString("\n")
 		}
 
-		content.WriteString(fmt.Sprintf("\n%d rows returned\n", len(m.results)))
+		content.WriteString(fmt.Sprintf("\n%d rows returned", len(m.results)))
+		if len(m.marks.rows) > 0 {
+			content.WriteString(fmt.Sprintf(", %d marked", len(m.marks.rows)))
+		}
+		content.WriteString("\n")
 	}
 
 	content.WriteString("\n")
 	if m.FocusOnInput {
-		content.WriteString(HelpStyle.Render("enter: execute • esc: back • ctrl+g: toggle help"))
+		content.WriteString(theme.Help.Render("enter: execute • alt+enter: new line • tab: complete • ctrl+o: $EDITOR • esc: back • ctrl+g: toggle help"))
 	} else {
 		if m.showFullHelp {
 			content.WriteString(m.help.FullHelpView(m.keyMap.FullHelp()))
@@ -466,3 +847,38 @@ func (m *QueryModel) View() string {
 	return content.String()
 }
 
+// completionView renders the completion popup below the query input
+func (m *QueryModel) completionView() string {
+	theme := m.Shared.theme()
+	const maxVisible = 8
+
+	start := 0
+	if m.completionIdx >= maxVisible {
+		start = m.completionIdx - maxVisible + 1
+	}
+	end := Min(len(m.completions), start+maxVisible)
+
+	width := 0
+	for _, item := range m.completions[start:end] {
+		width = Max(width, len(item.Text))
+	}
+
+	var lines []string
+	for i := start; i < end; i++ {
+		item := m.completions[i]
+		label := item.Kind.String()
+		if item.Detail != "" {
+			label += " " + item.Detail
+		}
+		line := fmt.Sprintf(" %-*s  %s ", width, item.Text, theme.Help.Render(label))
+		if i == m.completionIdx {
+			line = theme.Selected.Render(fmt.Sprintf(" %-*s  %s ", width, item.Text, label))
+		}
+		lines = append(lines, line)
+	}
+	if len(m.completions) > maxVisible {
+		lines = append(lines, theme.Help.Render(fmt.Sprintf(" %d/%d", m.completionIdx+1, len(m.completions))))
+	}
+
+	return strings.Join(lines, "\n")
+}
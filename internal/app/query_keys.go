@@ -17,6 +17,13 @@ type QueryKeyMap struct {
 	LineStart     key.Binding
 	LineEnd       key.Binding
 	DeleteWord    key.Binding
+	NewLine       key.Binding
+
+	// Completion keys
+	Complete         key.Binding
+	AcceptCompletion key.Binding
+	NextCompletion   key.Binding
+	PrevCompletion   key.Binding
 	
 	// Results mode keys
 	Up            key.Binding
@@ -26,7 +33,19 @@ type QueryKeyMap struct {
 	GoToStart     key.Binding
 	GoToEnd       key.Binding
 	Back          key.Binding
+	OpenEditor    key.Binding
+	CommandLine   key.Binding
 	ToggleHelp    key.Binding
+
+	// Copying and exporting
+	Mark      key.Binding
+	YankRow   key.Binding
+	YankQuery key.Binding
+	Export    key.Binding
+
+	// Safe mode confirmation
+	ConfirmRun key.Binding
+	CancelRun  key.Binding
 }
 
 // DefaultQueryKeyMap returns the default keybindings for query view
@@ -69,7 +88,39 @@ func DefaultQueryKeyMap() QueryKeyMap {
 			key.WithKeys("ctrl+w"),
 			key.WithHelp("ctrl+w", "delete word"),
 		),
+		NewLine: key.NewBinding(
+			key.WithKeys("alt+enter", "ctrl+j"),
+			key.WithHelp("alt+enter/ctrl+j", "new line"),
+		),
+
+		// Completion
+		Complete: key.NewBinding(
+			key.WithKeys("tab", "ctrl+@"),
+			key.WithHelp("tab/ctrl+space", "complete"),
+		),
+		AcceptCompletion: key.NewBinding(
+			key.WithKeys("tab", "enter"),
+			key.WithHelp("tab/enter", "accept completion"),
+		),
+		NextCompletion: key.NewBinding(
+			key.WithKeys("down", "ctrl+n"),
+			key.WithHelp("↓/ctrl+n", "next completion"),
+		),
+		PrevCompletion: key.NewBinding(
+			key.WithKeys("up", "ctrl+p"),
+			key.WithHelp("↑/ctrl+p", "previous completion"),
+		),
 		
+		// Safe mode confirmation
+		ConfirmRun: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "run anyway"),
+		),
+		CancelRun: key.NewBinding(
+			key.WithKeys("n", "esc"),
+			key.WithHelp("n/esc", "cancel"),
+		),
+
 		// Results mode
 		Up: key.NewBinding(
 			key.WithKeys("up", "k"),
@@ -99,10 +150,34 @@ func DefaultQueryKeyMap() QueryKeyMap {
 			key.WithKeys("q"),
 			key.WithHelp("q", "back"),
 		),
+		OpenEditor: key.NewBinding(
+			key.WithKeys("ctrl+o"),
+			key.WithHelp("ctrl+o", "open in $EDITOR"),
+		),
+		CommandLine: key.NewBinding(
+			key.WithKeys(":"),
+			key.WithHelp(":", "command"),
+		),
 		ToggleHelp: key.NewBinding(
 			key.WithKeys("ctrl+g"),
 			key.WithHelp("ctrl+g", "toggle help"),
 		),
+		Mark: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "mark row"),
+		),
+		YankRow: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "copy row/marked rows"),
+		),
+		YankQuery: key.NewBinding(
+			key.WithKeys("Y", "ctrl+y"),
+			key.WithHelp("Y/ctrl+y", "copy query"),
+		),
+		Export: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "export results"),
+		),
 	}
 }
 
@@ -114,9 +189,11 @@ func (k QueryKeyMap) ShortHelp() []key.Binding {
 // FullHelp returns keybindings for the expanded help view
 func (k QueryKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Execute, k.Escape, k.EditQuery, k.Back},
+		{k.Execute, k.Escape, k.EditQuery, k.OpenEditor, k.Back, k.CommandLine},
 		{k.Up, k.Down, k.Enter, k.GoToStart, k.GoToEnd},
+		{k.Mark, k.YankRow, k.YankQuery, k.Export},
 		{k.CursorLeft, k.CursorRight, k.WordLeft, k.WordRight},
-		{k.LineStart, k.LineEnd, k.DeleteWord, k.ToggleHelp},
+		{k.LineStart, k.LineEnd, k.DeleteWord, k.NewLine, k.ToggleHelp},
+		{k.Complete, k.AcceptCompletion, k.NextCompletion, k.PrevCompletion},
 	}
 }
\ No newline at end of file
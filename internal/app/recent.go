@@ -0,0 +1,95 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// maxRecentDatabases is how many databases the recent list keeps
+const maxRecentDatabases = 20
+
+// RecentDatabases lists recently opened databases, most recent first,
+// stored as JSON when it has a path
+type RecentDatabases struct {
+	path  string
+	paths []string
+}
+
+// NewRecentDatabases returns an empty in-memory list
+func NewRecentDatabases() *RecentDatabases {
+	return &RecentDatabases{}
+}
+
+// DefaultRecentDatabasesPath returns the recent list in the user config dir
+func DefaultRecentDatabasesPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "teaqlite", "recent.json"), nil
+}
+
+// LoadRecentDatabases reads the list at path; a missing file is empty
+func LoadRecentDatabases(path string) (*RecentDatabases, error) {
+	r := &RecentDatabases{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &r.paths); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Paths returns the databases, most recent first
+func (r *RecentDatabases) Paths() []string {
+	return slices.Clone(r.paths)
+}
+
+// Add moves a database to the top of the list. File names are made
+// absolute so the entry works from any directory; URIs are kept as given.
+// In-memory databases are not added, as there is nothing to reopen.
+func (r *RecentDatabases) Add(path string) error {
+	if DatabaseFile(path) == "" {
+		return nil
+	}
+	if !strings.HasPrefix(path, "file:") {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+	}
+	r.paths = slices.DeleteFunc(r.paths, func(p string) bool { return p == path })
+	r.paths = append([]string{path}, r.paths...)
+	if len(r.paths) > maxRecentDatabases {
+		r.paths = r.paths[:maxRecentDatabases]
+	}
+	return r.write()
+}
+
+// Remove drops a database from the list
+func (r *RecentDatabases) Remove(path string) error {
+	r.paths = slices.DeleteFunc(r.paths, func(p string) bool { return p == path })
+	return r.write()
+}
+
+func (r *RecentDatabases) write() error {
+	if r.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(r.paths, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, data, 0o644)
+}
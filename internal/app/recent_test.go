@@ -0,0 +1,40 @@
+package app
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRecentDatabasesAdd(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "recent.json")
+	r, err := LoadRecentDatabases(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{
+		filepath.Join(dir, "a.db"),
+		":memory:",
+		"file::memory:?cache=shared",
+		"file:mem?mode=memory",
+		"file:b.db?mode=ro",
+		filepath.Join(dir, "a.db"),
+	} {
+		if err := r.Add(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{filepath.Join(dir, "a.db"), "file:b.db?mode=ro"}
+	if got := r.Paths(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	reloaded, err := LoadRecentDatabases(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.Paths(); !reflect.DeepEqual(got, want) {
+		t.Errorf("reloaded %q, want %q", got, want)
+	}
+}
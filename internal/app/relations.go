@@ -0,0 +1,174 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RowFilter restricts the rows loaded for a table to those where each
+// column equals the matching value. NullValue matches NULL.
+type RowFilter struct {
+	Columns []string
+	Values  []string
+}
+
+// where builds the SQL condition and arguments for the filter
+func (f *RowFilter) where() (string, []any) {
+	var conds []string
+	var args []any
+	for i, col := range f.Columns {
+		if f.Values[i] == NullValue {
+			conds = append(conds, fmt.Sprintf("%s IS NULL", quoteIdent(col)))
+			continue
+		}
+		conds = append(conds, fmt.Sprintf("%s = ?", quoteIdent(col)))
+		args = append(args, f.Values[i])
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+// String describes the filter, e.g. "customer_id = 5"
+func (f *RowFilter) String() string {
+	var parts []string
+	for i, col := range f.Columns {
+		parts = append(parts, fmt.Sprintf("%s = %s", col, DisplayValue(f.Values[i])))
+	}
+	return strings.Join(parts, " AND ")
+}
+
+// RowReference is a child table whose foreign key points at a row
+type RowReference struct {
+	ForeignKey ForeignKey
+	Filter     *RowFilter
+	Count      int
+}
+
+// loadReferencingKeys returns the foreign keys of all tables that point at
+// the given table
+func (s *SharedData) loadReferencingKeys(tableName string) ([]ForeignKey, error) {
+	var refs []ForeignKey
+	for _, t := range s.Tables {
+		fks, err := loadForeignKeys(s.DB, t)
+		if err != nil {
+			return nil, err
+		}
+		for _, fk := range fks {
+			if strings.EqualFold(fk.RefTable, tableName) {
+				refs = append(refs, fk)
+			}
+		}
+	}
+	return refs, nil
+}
+
+// OpenTable selects a table by name and loads its first page, restricted
+// to the rows matching filter when it is not nil
+func (s *SharedData) OpenTable(tableName string, filter *RowFilter) error {
+	index := -1
+	for i, t := range s.Tables {
+		if strings.EqualFold(t, tableName) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("no such table: %s", tableName)
+	}
+
+	s.FilteredTables = make([]string, len(s.Tables))
+	copy(s.FilteredTables, s.Tables)
+	s.SelectedTable = index
+	s.CurrentPage = 0
+	s.Filter = filter
+	s.Sort = nil
+	return s.LoadTableData()
+}
+
+// ForeignKeyFor returns the foreign key that includes a displayed column,
+// or nil when the column is not part of one. Query results have no
+// foreign keys.
+func (s *SharedData) ForeignKeyFor(colIndex int) *ForeignKey {
+	if s.IsQueryResult || colIndex >= len(s.Columns) {
+		return nil
+	}
+	name := s.Columns[colIndex]
+	for i, fk := range s.ForeignKeys {
+		for _, c := range fk.Columns {
+			if c == name {
+				return &s.ForeignKeys[i]
+			}
+		}
+	}
+	return nil
+}
+
+// rowValue returns the value of a named column in a displayed row
+func (s *SharedData) rowValue(rowIndex int, columnName string) (string, bool) {
+	if rowIndex >= len(s.FilteredData) {
+		return "", false
+	}
+	for i, col := range s.Columns {
+		if strings.EqualFold(col, columnName) && i < len(s.FilteredData[rowIndex]) {
+			return s.FilteredData[rowIndex][i], true
+		}
+	}
+	return "", false
+}
+
+// ForeignKeyTarget returns the filter that selects the parent row
+// referenced by a foreign key in the given row
+func (s *SharedData) ForeignKeyTarget(rowIndex int, fk ForeignKey) (*RowFilter, error) {
+	filter := &RowFilter{}
+	for i, col := range fk.Columns {
+		value, ok := s.rowValue(rowIndex, col)
+		if !ok {
+			return nil, fmt.Errorf("column %s not found in current data", col)
+		}
+		if value == NullValue {
+			return nil, fmt.Errorf("%s is NULL and does not reference a row in %s", col, fk.RefTable)
+		}
+		filter.Columns = append(filter.Columns, fk.RefColumns[i])
+		filter.Values = append(filter.Values, value)
+	}
+	return filter, nil
+}
+
+// ReferencesTo lists the child rows pointing at a row of the current table,
+// one entry per referencing foreign key with the number of matching rows
+func (s *SharedData) ReferencesTo(rowIndex int) ([]RowReference, error) {
+	if s.IsQueryResult || s.SelectedTable >= len(s.FilteredTables) {
+		return nil, nil
+	}
+	fks, err := s.loadReferencingKeys(s.FilteredTables[s.SelectedTable])
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []RowReference
+	for _, fk := range fks {
+		filter := &RowFilter{}
+		hasNull := false
+		for i, refCol := range fk.RefColumns {
+			value, ok := s.rowValue(rowIndex, refCol)
+			if !ok || value == NullValue {
+				hasNull = true
+				break
+			}
+			filter.Columns = append(filter.Columns, fk.Columns[i])
+			filter.Values = append(filter.Values, value)
+		}
+		// A NULL parent key cannot be referenced
+		if hasNull {
+			continue
+		}
+
+		where, args := filter.where()
+		var count int
+		query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", quoteTable(fk.Table), where)
+		if err := s.DB.QueryRow(query, args...).Scan(&count); err != nil {
+			return nil, err
+		}
+		refs = append(refs, RowReference{ForeignKey: fk, Filter: filter, Count: count})
+	}
+	return refs, nil
+}
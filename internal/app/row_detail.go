@@ -13,6 +13,8 @@ type RowDetailModel struct {
 	Shared       *SharedData
 	rowIndex     int
 	selectedCol  int
+	references   []RowReference
+	refErr       error
 	FromQuery    bool
 	gPressed     bool
 	keyMap       RowDetailKeyMap
@@ -20,6 +22,8 @@ type RowDetailModel struct {
 	showFullHelp bool
 	focused      bool
 	id           int
+	clicks       clickTracker
+	lines        itemLines // items drawn by the last View
 }
 
 // RowDetailOption is a functional option for configuring RowDetailModel
@@ -38,8 +42,8 @@ func NewRowDetailModel(shared *SharedData, rowIndex int, opts ...RowDetailOption
 		rowIndex:    rowIndex,
 		selectedCol: 0,
 		FromQuery:   false,
-		keyMap:      DefaultRowDetailKeyMap(),
-		help:        help.New(),
+		keyMap:      shared.keyMaps().RowDetail,
+		help:        shared.theme().newHelp(),
 		focused:     true,
 		id:          nextID(),
 	}
@@ -49,6 +53,8 @@ func NewRowDetailModel(shared *SharedData, rowIndex int, opts ...RowDetailOption
 		opt(m)
 	}
 
+	m.references, m.refErr = shared.ReferencesTo(rowIndex)
+
 	return m
 }
 
@@ -88,6 +94,9 @@ func (m *RowDetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.KeyMsg:
 		return m.handleNavigation(msg)
+
+	case tea.MouseMsg:
+		return m, m.handleMouse(msg)
 	}
 	return m, nil
 }
@@ -96,10 +105,11 @@ func (m *RowDetailModel) handleNavigation(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
 	case key.Matches(msg, m.keyMap.Escape), key.Matches(msg, m.keyMap.Back):
 		m.gPressed = false
-		if m.FromQuery {
-			return m, func() tea.Msg { return ReturnToQueryMsg{} }
-		}
-		return m, func() tea.Msg { return SwitchToTableDataMsg{TableIndex: m.Shared.SelectedTable} }
+		return m, func() tea.Msg { return NavigateBackMsg{} }
+
+	case key.Matches(msg, m.keyMap.CommandLine):
+		m.gPressed = false
+		return m, func() tea.Msg { return OpenCommandLineMsg{} }
 
 	case key.Matches(msg, m.keyMap.GoToStart):
 		if m.gPressed {
@@ -114,18 +124,36 @@ func (m *RowDetailModel) handleNavigation(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case key.Matches(msg, m.keyMap.GoToEnd):
 		// Go to end (G pattern like vim)
-		if len(m.Shared.Columns) > 0 {
-			m.selectedCol = len(m.Shared.Columns) - 1
+		if m.itemCount() > 0 {
+			m.selectedCol = m.itemCount() - 1
 		}
 		m.gPressed = false
 		return m, nil
 
 	case key.Matches(msg, m.keyMap.Enter):
 		m.gPressed = false
-		return m, func() tea.Msg {
-			return SwitchToEditCellMsg{RowIndex: m.rowIndex, ColIndex: m.selectedCol}
+		return m, m.open()
+
+	case key.Matches(msg, m.keyMap.Follow):
+		m.gPressed = false
+		if m.selectedCol < len(m.Shared.Columns) {
+			return m, func() tea.Msg {
+				return FollowForeignKeyMsg{RowIndex: m.rowIndex, ColIndex: m.selectedCol}
+			}
 		}
 
+	case key.Matches(msg, m.keyMap.NavigateBack):
+		m.gPressed = false
+		return m, func() tea.Msg { return NavigateBackMsg{} }
+
+	case key.Matches(msg, m.keyMap.Yank):
+		m.gPressed = false
+		return m, func() tea.Msg { return RunCommandMsg{Line: "yank cell"} }
+
+	case key.Matches(msg, m.keyMap.YankRow):
+		m.gPressed = false
+		return m, func() tea.Msg { return RunCommandMsg{Line: "yank row"} }
+
 	case key.Matches(msg, m.keyMap.Up):
 		m.gPressed = false
 		if m.selectedCol > 0 {
@@ -134,7 +162,7 @@ func (m *RowDetailModel) handleNavigation(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case key.Matches(msg, m.keyMap.Down):
 		m.gPressed = false
-		if m.selectedCol < len(m.Shared.Columns)-1 {
+		if m.selectedCol < m.itemCount()-1 {
 			m.selectedCol++
 		}
 
@@ -145,10 +173,58 @@ func (m *RowDetailModel) handleNavigation(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// open edits the selected column or opens the selected referencing rows
+func (m *RowDetailModel) open() tea.Cmd {
+	if ref, ok := m.selectedReference(); ok {
+		return func() tea.Msg { return OpenReferencesMsg{Reference: ref} }
+	}
+	return func() tea.Msg {
+		return SwitchToEditCellMsg{RowIndex: m.rowIndex, ColIndex: m.selectedCol}
+	}
+}
+
+// handleMouse selects the clicked line and opens it on a double click; the
+// wheel moves the selection
+func (m *RowDetailModel) handleMouse(msg tea.MouseMsg) tea.Cmd {
+	if delta := wheelDelta(msg); delta != 0 {
+		m.selectedCol = Max(0, Min(m.itemCount()-1, m.selectedCol+delta))
+		return nil
+	}
+	if !isClick(msg) {
+		return nil
+	}
+	i := m.lines.at(msg.Y)
+	if i < 0 {
+		return nil
+	}
+	m.selectedCol = i
+	if m.clicks.click(i) {
+		return m.open()
+	}
+	return nil
+}
+
+// itemCount returns the number of selectable lines: the columns followed by
+// the referencing tables
+func (m *RowDetailModel) itemCount() int {
+	return len(m.Shared.Columns) + len(m.references)
+}
+
+// selectedReference returns the referencing table under the cursor
+func (m *RowDetailModel) selectedReference() (RowReference, bool) {
+	i := m.selectedCol - len(m.Shared.Columns)
+	if i < 0 || i >= len(m.references) {
+		return RowReference{}, false
+	}
+	return m.references[i], true
+}
+
 func (m *RowDetailModel) View() string {
+	theme := m.Shared.theme()
 	var content strings.Builder
+	m.lines = m.lines[:0]
 
-	content.WriteString(TitleStyle.Render("Row Details"))
+	content.WriteString(theme.Title.Render("Row Details"))
 	content.WriteString("\n\n")
 
 	if m.rowIndex >= len(m.Shared.FilteredData) {
@@ -164,7 +240,7 @@ func (m *RowDetailModel) View() string {
 			break
 		}
 
-		value := row[i]
+		value := m.Shared.displayValue(i, row[i])
 		// Calculate available width for value display
 		// Account for column name, ": ", and indentation
 		availableWidth := m.Shared.Width - len(col) - 4 // 4 for ": " and "> " prefix
@@ -178,15 +254,48 @@ func (m *RowDetailModel) View() string {
 			value = strings.Join(lines, "\n    ")
 		}
 
+		if i != m.selectedCol {
+			if style, ok := theme.cellStyle(row[i], m.Shared.ColumnInfoFor(i).PrimaryKey > 0, m.Shared.isModified(m.rowIndex, i)); ok {
+				value = style.Inherit(theme.Normal).Render(value)
+			}
+		}
+
 		line := fmt.Sprintf("%s: %s", col, value)
+		if fk := m.Shared.ForeignKeyFor(i); fk != nil {
+			line += theme.Help.Render(fmt.Sprintf("  → %s(%s)", fk.RefTable, strings.Join(fk.RefColumns, ", ")))
+		}
+		m.lines.mark(&content, i, strings.Count(line, "\n")+1)
 		if i == m.selectedCol {
-			content.WriteString(SelectedStyle.Render("> " + line))
+			content.WriteString(theme.Selected.Render("> " + line))
 		} else {
-			content.WriteString(NormalStyle.Render("  " + line))
+			content.WriteString(theme.Normal.Render("  " + line))
 		}
 		content.WriteString("\n")
 	}
 
+	if m.refErr != nil {
+		content.WriteString("\n")
+		content.WriteString(theme.Error.Render(fmt.Sprintf("Error loading references: %v", m.refErr)))
+		content.WriteString("\n")
+	} else if len(m.references) > 0 {
+		content.WriteString("\nReferenced by:\n")
+		for i, ref := range m.references {
+			fk := ref.ForeignKey
+			rows := "rows"
+			if ref.Count == 1 {
+				rows = "row"
+			}
+			line := fmt.Sprintf("%s(%s): %d %s", fk.Table, strings.Join(fk.Columns, ", "), ref.Count, rows)
+			m.lines.mark(&content, len(m.Shared.Columns)+i, 1)
+			if len(m.Shared.Columns)+i == m.selectedCol {
+				content.WriteString(theme.Selected.Render("> " + line))
+			} else {
+				content.WriteString(theme.Normal.Render("  " + line))
+			}
+			content.WriteString("\n")
+		}
+	}
+
 	content.WriteString("\n")
 	if m.showFullHelp {
 		content.WriteString(m.help.FullHelpView(m.keyMap.FullHelp()))
@@ -7,14 +7,19 @@ import "github.com/charmbracelet/bubbles/key"
 // - gg: go to start (requires two 'g' presses)
 // - G: go to end (single 'G' press)
 type RowDetailKeyMap struct {
-	Up         key.Binding
-	Down       key.Binding
-	Enter      key.Binding
-	Escape     key.Binding
-	Back       key.Binding
-	GoToStart  key.Binding
-	GoToEnd    key.Binding
-	ToggleHelp key.Binding
+	Up           key.Binding
+	Down         key.Binding
+	Enter        key.Binding
+	Follow       key.Binding
+	NavigateBack key.Binding
+	Escape       key.Binding
+	Back         key.Binding
+	GoToStart    key.Binding
+	GoToEnd      key.Binding
+	CommandLine  key.Binding
+	ToggleHelp   key.Binding
+	Yank         key.Binding
+	YankRow      key.Binding
 }
 
 // DefaultRowDetailKeyMap returns the default keybindings for row detail
@@ -30,7 +35,15 @@ func DefaultRowDetailKeyMap() RowDetailKeyMap {
 		),
 		Enter: key.NewBinding(
 			key.WithKeys("enter"),
-			key.WithHelp("enter", "edit cell"),
+			key.WithHelp("enter", "edit cell/open references"),
+		),
+		Follow: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "follow foreign key"),
+		),
+		NavigateBack: key.NewBinding(
+			key.WithKeys("backspace"),
+			key.WithHelp("backspace", "previous row"),
 		),
 		Escape: key.NewBinding(
 			key.WithKeys("esc"),
@@ -48,22 +61,35 @@ func DefaultRowDetailKeyMap() RowDetailKeyMap {
 			key.WithKeys("G"),
 			key.WithHelp("G", "go to end"),
 		),
+		CommandLine: key.NewBinding(
+			key.WithKeys(":"),
+			key.WithHelp(":", "command"),
+		),
 		ToggleHelp: key.NewBinding(
 			key.WithKeys("ctrl+g"),
 			key.WithHelp("ctrl+g", "toggle help"),
 		),
+		Yank: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "copy value"),
+		),
+		YankRow: key.NewBinding(
+			key.WithKeys("Y"),
+			key.WithHelp("Y", "copy row"),
+		),
 	}
 }
 
 // ShortHelp returns keybindings to be shown in the mini help view
 func (k RowDetailKeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Up, k.Down, k.Enter, k.GoToStart, k.GoToEnd, k.Back, k.ToggleHelp}
+	return []key.Binding{k.Up, k.Down, k.Enter, k.Follow, k.NavigateBack, k.Back, k.ToggleHelp}
 }
 
 // FullHelp returns keybindings for the expanded help view
 func (k RowDetailKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Up, k.Down, k.Enter},
-		{k.Escape, k.Back, k.GoToStart, k.GoToEnd, k.ToggleHelp},
+		{k.Up, k.Down, k.Enter, k.Follow, k.NavigateBack},
+		{k.Yank, k.YankRow},
+		{k.Escape, k.Back, k.GoToStart, k.GoToEnd, k.ToggleHelp, k.CommandLine},
 	}
 }
\ No newline at end of file
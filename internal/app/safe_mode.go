@@ -0,0 +1,82 @@
+package app
+
+import "strings"
+
+// RiskyStatements describes each statement of query that safe mode asks
+// about: UPDATE and DELETE without a WHERE clause, DROP and ALTER
+func RiskyStatements(query string) []string {
+	var risks []string
+	for _, stmt := range splitStatements(query) {
+		if risk := statementRisk(significantTokens(lexSQL(stmt))); risk != "" {
+			risks = append(risks, risk)
+		}
+	}
+	return risks
+}
+
+// statementRisk describes what makes a statement risky, or returns ""
+func statementRisk(tokens []sqlToken) string {
+	verb := mainVerb(tokens)
+	if verb < 0 {
+		return ""
+	}
+	switch t := tokens[verb]; {
+	case t.IsKeyword("DROP"), t.IsKeyword("ALTER"):
+		return statementHead(tokens[verb:])
+	case t.IsKeyword("UPDATE"), t.IsKeyword("DELETE"):
+		depth := 0
+		for _, t := range tokens[verb+1:] {
+			switch {
+			case t.Text == "(":
+				depth++
+			case t.Text == ")":
+				depth--
+			case depth == 0 && t.IsKeyword("WHERE"):
+				return ""
+			}
+		}
+		return statementHead(tokens[verb:]) + " without WHERE"
+	}
+	return ""
+}
+
+// mainVerb returns the index of the keyword that says what a statement
+// does, skipping a WITH clause, or -1
+func mainVerb(tokens []sqlToken) int {
+	if len(tokens) == 0 {
+		return -1
+	}
+	if !tokens[0].IsKeyword("WITH") {
+		return 0
+	}
+	depth := 0
+	for i, t := range tokens {
+		switch {
+		case t.Text == "(":
+			depth++
+		case t.Text == ")":
+			depth--
+		case depth == 0 && (t.IsKeyword("SELECT") || t.IsKeyword("INSERT") || t.IsKeyword("REPLACE") ||
+			t.IsKeyword("UPDATE") || t.IsKeyword("DELETE")):
+			return i
+		}
+	}
+	return -1
+}
+
+// statementHead returns the keywords of a statement up to and including
+// the name of the object it changes, e.g. DELETE FROM main.users
+func statementHead(tokens []sqlToken) string {
+	var words []string
+	for i, t := range tokens {
+		text := t.Text
+		if t.Kind == tokenKeyword {
+			text = strings.ToUpper(text)
+		}
+		words = append(words, text)
+		if t.Kind != tokenKeyword && (i+1 >= len(tokens) || tokens[i+1].Text != ".") && t.Text != "." {
+			break
+		}
+	}
+	return strings.ReplaceAll(strings.Join(words, " "), " . ", ".")
+}
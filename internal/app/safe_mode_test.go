@@ -0,0 +1,34 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRiskyStatements(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"select", "SELECT * FROM t", nil},
+		{"update with where", "UPDATE t SET a = 1 WHERE id = 2", nil},
+		{"update without where", "update t set a = 1", []string{"UPDATE t without WHERE"}},
+		{"where in a subquery", "DELETE FROM t WHERE id IN (SELECT id FROM u WHERE x)", nil},
+		{"where only in a subquery", "UPDATE t SET a = (SELECT b FROM u WHERE u.id = 1)", []string{"UPDATE t without WHERE"}},
+		{"qualified delete", "DELETE FROM main.t", []string{"DELETE FROM main.t without WHERE"}},
+		{"drop", "DROP TABLE IF EXISTS t", []string{"DROP TABLE IF EXISTS t"}},
+		{"alter", "ALTER TABLE t ADD COLUMN c", []string{"ALTER TABLE t"}},
+		{"with clause", "WITH x AS (SELECT 1) DELETE FROM t", []string{"DELETE FROM t without WHERE"}},
+		{"several statements", "SELECT 1; DROP VIEW v; DELETE FROM t WHERE 1", []string{"DROP VIEW v"}},
+		{"inside a string", "SELECT 'DROP TABLE t'", nil},
+		{"trigger body", "CREATE TRIGGER tr AFTER INSERT ON t BEGIN DELETE FROM u; END", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RiskyStatements(tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
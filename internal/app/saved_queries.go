@@ -0,0 +1,87 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// SavedQueries is a set of named queries, stored as JSON when it has a path
+type SavedQueries struct {
+	path    string
+	queries map[string]string
+}
+
+// NewSavedQueries returns an empty in-memory store
+func NewSavedQueries() *SavedQueries {
+	return &SavedQueries{queries: map[string]string{}}
+}
+
+// DefaultSavedQueriesPath returns the queries file in the user config dir
+func DefaultSavedQueriesPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "teaqlite", "queries.json"), nil
+}
+
+// LoadSavedQueries reads the store at path; a missing file is empty
+func LoadSavedQueries(path string) (*SavedQueries, error) {
+	q := &SavedQueries{path: path, queries: map[string]string{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return q, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &q.queries); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// Names returns the query names in alphabetical order
+func (q *SavedQueries) Names() []string {
+	names := make([]string, 0, len(q.queries))
+	for name := range q.queries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns the query saved under name
+func (q *SavedQueries) Get(name string) (string, bool) {
+	query, ok := q.queries[name]
+	return query, ok
+}
+
+// Set saves a query under name
+func (q *SavedQueries) Set(name, query string) error {
+	q.queries[name] = query
+	return q.write()
+}
+
+// Delete removes the query saved under name
+func (q *SavedQueries) Delete(name string) error {
+	delete(q.queries, name)
+	return q.write()
+}
+
+func (q *SavedQueries) write() error {
+	if q.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(q.queries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(q.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(q.path, data, 0o644)
+}
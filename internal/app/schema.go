@@ -0,0 +1,371 @@
+package app
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NullValue marks a NULL cell in the string based table data so it can be
+// told apart from the text 'NULL'. Use DisplayValue to render cells.
+const NullValue = "\x00NULL"
+
+// How cell values are shown; the user configuration may change them
+var (
+	// NullText is shown for NULL cells
+	NullText = "NULL"
+	// DateFormat is the Go time layout for dates without a time of day
+	DateFormat = "2006-01-02"
+	// DateTimeFormat is the Go time layout for other date and time values
+	DateTimeFormat = "2006-01-02 15:04:05"
+)
+
+// storedTimeFormat is how SQLite writes a time, keeping fractional seconds
+// and the offset, for output that must not lose any of a parsed time
+const storedTimeFormat = "2006-01-02 15:04:05.999999999-07:00"
+
+// DisplayValue returns the text shown for a cell value
+func DisplayValue(v string) string {
+	if v == NullValue {
+		return NullText
+	}
+	return v
+}
+
+// DisplayDate returns the text shown for a cell of a date column: dates in
+// the configured format, other text as it is
+func DisplayDate(v string) string {
+	if v == NullValue {
+		return NullText
+	}
+	t, ok := parseStoredTime(v)
+	if !ok {
+		return v
+	}
+	if h, m, s := t.Clock(); h == 0 && m == 0 && s == 0 && t.Nanosecond() == 0 {
+		return t.Format(DateFormat)
+	}
+	return t.Format(DateTimeFormat)
+}
+
+// storedTimeLayouts are the date formats SQLite understands, as the driver
+// parses them
+var storedTimeLayouts = []string{
+	storedTimeFormat,
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// parseStoredTime parses the text of a date column
+func parseStoredTime(s string) (time.Time, bool) {
+	s = strings.TrimSuffix(s, "Z")
+	for _, layout := range storedTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// isDateType reports whether the driver parses the text of a column with
+// this declared type into time.Time. Cells keep the stored text instead,
+// see storedQuery, and only show it formatted.
+func isDateType(declared string) bool {
+	switch strings.ToUpper(declared) {
+	case "DATE", "DATETIME", "TIMESTAMP":
+		return true
+	}
+	return false
+}
+
+// formatValue converts a scanned database value to the cell representation.
+// Dates are normally read as stored; those the driver parsed anyway are
+// written back in the format SQLite stores dates in.
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return NullValue
+	case time.Time:
+		return v.Format(storedTimeFormat)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// Affinity is the SQLite type affinity of a column
+type Affinity int
+
+const (
+	AffinityBlob Affinity = iota
+	AffinityText
+	AffinityNumeric
+	AffinityInteger
+	AffinityReal
+)
+
+// String returns the SQLite name of the affinity
+func (a Affinity) String() string {
+	switch a {
+	case AffinityText:
+		return "TEXT"
+	case AffinityNumeric:
+		return "NUMERIC"
+	case AffinityInteger:
+		return "INTEGER"
+	case AffinityReal:
+		return "REAL"
+	default:
+		return "BLOB"
+	}
+}
+
+// columnAffinity determines the affinity of a declared column type using
+// the rules from https://www.sqlite.org/datatype3.html#determination_of_column_affinity
+func columnAffinity(declType string) Affinity {
+	t := strings.ToUpper(declType)
+	switch {
+	case strings.Contains(t, "INT"):
+		return AffinityInteger
+	case strings.Contains(t, "CHAR"), strings.Contains(t, "CLOB"), strings.Contains(t, "TEXT"):
+		return AffinityText
+	case t == "", strings.Contains(t, "BLOB"):
+		return AffinityBlob
+	case strings.Contains(t, "REAL"), strings.Contains(t, "FLOA"), strings.Contains(t, "DOUB"):
+		return AffinityReal
+	default:
+		return AffinityNumeric
+	}
+}
+
+// ColumnInfo describes a table column as reported by PRAGMA table_info
+type ColumnInfo struct {
+	Name       string
+	Type       string
+	NotNull    bool
+	Default    sql.NullString
+	PrimaryKey int // 1-based position in the primary key, 0 if not part of it
+}
+
+// Affinity returns the type affinity of the column
+func (c ColumnInfo) Affinity() Affinity {
+	return columnAffinity(c.Type)
+}
+
+// qualifyTable returns the name a table is listed as. Tables of attached
+// databases are prefixed with their schema, and so are main tables with a
+// dot in their name, to keep the two apart.
+func qualifyTable(schema, table string) string {
+	if strings.EqualFold(schema, "main") && !strings.Contains(table, ".") {
+		return table
+	}
+	return schema + "." + table
+}
+
+// splitTable splits a name from qualifyTable into its schema and table
+func splitTable(name string) (schema, table string) {
+	if schema, table, ok := strings.Cut(name, "."); ok {
+		return schema, table
+	}
+	return "main", name
+}
+
+// quoteTable quotes a name from qualifyTable for use in SQL
+func quoteTable(name string) string {
+	if !strings.Contains(name, ".") {
+		return quoteIdent(name)
+	}
+	schema, table := splitTable(name)
+	return quoteIdent(schema) + "." + quoteIdent(table)
+}
+
+// tablePragma builds a PRAGMA statement such as table_info that takes a
+// table name from qualifyTable, run on the table's schema
+func tablePragma(pragma, name string) string {
+	if !strings.Contains(name, ".") {
+		return fmt.Sprintf("PRAGMA %s(%s)", pragma, quoteIdent(name))
+	}
+	schema, table := splitTable(name)
+	return fmt.Sprintf("PRAGMA %s.%s(%s)", quoteIdent(schema), pragma, quoteIdent(table))
+}
+
+// querier runs queries on a pool or in a transaction
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// loadColumnInfo reads the column definitions of a table or view
+func loadColumnInfo(db querier, tableName string) ([]ColumnInfo, error) {
+	rows, err := db.Query(tablePragma("table_info", tableName))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []ColumnInfo
+	for rows.Next() {
+		var cid, notNull, pk int
+		var col ColumnInfo
+		if err := rows.Scan(&cid, &col.Name, &col.Type, &notNull, &col.Default, &pk); err != nil {
+			return nil, err
+		}
+		col.NotNull = notNull != 0
+		col.PrimaryKey = pk
+		columns = append(columns, col)
+	}
+	return columns, rows.Err()
+}
+
+// selectColumns lists columns for a SELECT. Date columns are selected as
+// +column, which has no declared type, so the driver returns their text as
+// stored instead of parsing it.
+func selectColumns(columns []ColumnInfo) string {
+	list := make([]string, len(columns))
+	for i, col := range columns {
+		list[i] = quoteIdent(col.Name)
+		if isDateType(col.Type) {
+			list[i] = "+" + list[i] + " AS " + list[i]
+		}
+	}
+	return strings.Join(list, ", ")
+}
+
+// StoredValue describes how SQLite will store an edited value
+type StoredValue struct {
+	// Arg is the value bound to the UPDATE statement
+	Arg any
+	// Type is the storage class: NULL, INTEGER, REAL or TEXT
+	Type string
+	// Display is the cell text after storing
+	Display string
+	// Warning notes a value SQLite keeps as text in a numeric column
+	Warning string
+}
+
+// convertValue applies a column's affinity to an edited value, mirroring
+// how SQLite converts text on insert: text that looks like a number is
+// stored as one, other text stays TEXT. NUMERIC affinity is also what
+// DATE, DATETIME, BOOLEAN and DECIMAL columns get, so text is normal there;
+// INTEGER and REAL columns get a warning. STRICT tables are checked by
+// checkCellConstraints.
+func convertValue(col ColumnInfo, value string) StoredValue {
+	if value == NullValue {
+		return StoredValue{Arg: nil, Type: "NULL", Display: NullValue}
+	}
+
+	affinity := col.Affinity()
+	trimmed := strings.TrimSpace(value)
+
+	switch affinity {
+	case AffinityInteger, AffinityNumeric, AffinityReal:
+		if i, err := strconv.ParseInt(trimmed, 10, 64); err == nil {
+			if affinity == AffinityReal {
+				f := float64(i)
+				return StoredValue{Arg: f, Type: "REAL", Display: formatReal(f)}
+			}
+			return StoredValue{Arg: i, Type: "INTEGER", Display: strconv.FormatInt(i, 10)}
+		}
+		if f, ok := parseReal(trimmed); ok {
+			if affinity != AffinityReal && f == math.Trunc(f) && math.Abs(f) < 1<<63 {
+				// INTEGER and NUMERIC affinity store integral reals as integers
+				return StoredValue{Arg: int64(f), Type: "INTEGER", Display: strconv.FormatInt(int64(f), 10)}
+			}
+			return StoredValue{Arg: f, Type: "REAL", Display: formatReal(f)}
+		}
+		stored := StoredValue{Arg: value, Type: "TEXT", Display: value}
+		if affinity != AffinityNumeric {
+			stored.Warning = fmt.Sprintf("not a number, so the %s column %q stores it as text", affinity, col.Name)
+		}
+		return stored
+	}
+
+	return StoredValue{Arg: value, Type: "TEXT", Display: value}
+}
+
+// parseReal parses text SQLite would read as a real number. Go also
+// accepts forms SQLite keeps as text, such as Inf, NaN, hex floats and
+// underscores, so those are rejected.
+func parseReal(s string) (float64, bool) {
+	if s == "" || strings.ContainsAny(s, "_xXpPiInN") {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(f, 0) {
+		return 0, false
+	}
+	return f, true
+}
+
+// formatReal formats a float the same way scanned REAL values are shown
+func formatReal(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// IndexInfo describes an index as reported by PRAGMA index_list
+type IndexInfo struct {
+	Name    string
+	Unique  bool
+	Origin  string // c: CREATE INDEX, u: UNIQUE constraint, pk: PRIMARY KEY
+	Columns []string
+}
+
+// loadIndexes reads the indexes of a table
+func loadIndexes(db *sql.DB, tableName string) ([]IndexInfo, error) {
+	rows, err := db.Query(tablePragma("index_list", tableName))
+	if err != nil {
+		return nil, err
+	}
+
+	var indexes []IndexInfo
+	for rows.Next() {
+		var seq, unique, partial int
+		var idx IndexInfo
+		if err := rows.Scan(&seq, &idx.Name, &unique, &idx.Origin, &partial); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		idx.Unique = unique != 0
+		indexes = append(indexes, idx)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	schema, _ := splitTable(tableName)
+	for i := range indexes {
+		cols, err := db.Query(tablePragma("index_info", qualifyTable(schema, indexes[i].Name)))
+		if err != nil {
+			return nil, err
+		}
+		for cols.Next() {
+			var seqno, cid int
+			var name sql.NullString
+			if err := cols.Scan(&seqno, &cid, &name); err != nil {
+				cols.Close()
+				return nil, err
+			}
+			if !name.Valid {
+				// Expression indexes have no column name
+				name.String = "<expr>"
+			}
+			indexes[i].Columns = append(indexes[i].Columns, name.String)
+		}
+		cols.Close()
+	}
+	return indexes, nil
+}
+
+// loadCreateSQL returns the CREATE statement of a table or view
+func loadCreateSQL(db *sql.DB, tableName string) (string, error) {
+	var stmt sql.NullString
+	schema, table := splitTable(tableName)
+	query := fmt.Sprintf(`SELECT sql FROM %s.sqlite_master WHERE name = ? COLLATE NOCASE AND type IN ('table', 'view')`, quoteIdent(schema))
+	err := db.QueryRow(query, table).Scan(&stmt)
+	return stmt.String, err
+}
@@ -0,0 +1,41 @@
+package app
+
+import "testing"
+
+func TestConvertValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		declared string
+		value    string
+		arg      any
+		typ      string
+		warning  bool
+	}{
+		{"date", "DATE", "2024-01-02", "2024-01-02", "TEXT", false},
+		{"datetime", "DATETIME", "2024-01-02 03:04:05", "2024-01-02 03:04:05", "TEXT", false},
+		{"boolean number", "BOOLEAN", "1", int64(1), "INTEGER", false},
+		{"boolean text", "BOOLEAN", "true", "true", "TEXT", false},
+		{"decimal", "DECIMAL(10,2)", "12.50", 12.5, "REAL", false},
+		{"decimal integral", "DECIMAL(10,2)", "12.0", int64(12), "INTEGER", false},
+		{"decimal text", "DECIMAL(10,2)", "n/a", "n/a", "TEXT", false},
+		{"integer", "INTEGER", " 42 ", int64(42), "INTEGER", false},
+		{"integer text", "INTEGER", "abc", "abc", "TEXT", true},
+		{"integer empty", "INTEGER", "", "", "TEXT", true},
+		{"real from integer", "REAL", "3", 3.0, "REAL", false},
+		{"real inf", "REAL", "Inf", "Inf", "TEXT", true},
+		{"real hex", "REAL", "0x1p-2", "0x1p-2", "TEXT", true},
+		{"text", "TEXT", "007", "007", "TEXT", false},
+		{"null", "DATE", NullValue, nil, "NULL", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := convertValue(ColumnInfo{Name: "c", Type: tt.declared}, tt.value)
+			if got.Arg != tt.arg || got.Type != tt.typ {
+				t.Errorf("convertValue(%s, %q) = %#v (%s), want %#v (%s)", tt.declared, tt.value, got.Arg, got.Type, tt.arg, tt.typ)
+			}
+			if (got.Warning != "") != tt.warning {
+				t.Errorf("convertValue(%s, %q) warning = %q, want warning %v", tt.declared, tt.value, got.Warning, tt.warning)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SchemaModel shows the columns, keys, indexes and CREATE statement of a
+// table
+type SchemaModel struct {
+	Shared       *SharedData
+	tableName    string
+	columns      []ColumnInfo
+	foreignKeys  []ForeignKey
+	indexes      []IndexInfo
+	createSQL    string
+	err          error
+	offset       int
+	gPressed     bool
+	keyMap       SchemaKeyMap
+	help         help.Model
+	showFullHelp bool
+	focused      bool
+	id           int
+}
+
+// SchemaOption is a functional option for configuring SchemaModel
+type SchemaOption func(*SchemaModel)
+
+// WithSchemaKeyMap sets the key map
+func WithSchemaKeyMap(km SchemaKeyMap) SchemaOption {
+	return func(m *SchemaModel) {
+		m.keyMap = km
+	}
+}
+
+func NewSchemaModel(shared *SharedData, tableName string, opts ...SchemaOption) *SchemaModel {
+	m := &SchemaModel{
+		Shared:    shared,
+		tableName: tableName,
+		keyMap:    shared.keyMaps().Schema,
+		help:      shared.theme().newHelp(),
+		focused:   true,
+		id:        nextID(),
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	m.load()
+
+	return m
+}
+
+// load reads the schema of the table, keeping the first error
+func (m *SchemaModel) load() {
+	var err error
+	if m.columns, err = loadColumnInfo(m.Shared.DB, m.tableName); err != nil {
+		m.err = err
+		return
+	}
+	if m.foreignKeys, err = loadForeignKeys(m.Shared.DB, m.tableName); err != nil {
+		m.err = err
+		return
+	}
+	if m.indexes, err = loadIndexes(m.Shared.DB, m.tableName); err != nil {
+		m.err = err
+		return
+	}
+	m.createSQL, m.err = loadCreateSQL(m.Shared.DB, m.tableName)
+}
+
+// ID returns the unique ID of the model
+func (m SchemaModel) ID() int {
+	return m.id
+}
+
+// Focus sets the focus state
+func (m *SchemaModel) Focus() {
+	m.focused = true
+}
+
+// Blur removes focus
+func (m *SchemaModel) Blur() {
+	m.focused = false
+}
+
+// Focused returns the focus state
+func (m SchemaModel) Focused() bool {
+	return m.focused
+}
+
+func (m *SchemaModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *SchemaModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if !m.focused {
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case ToggleHelpMsg:
+		m.showFullHelp = !m.showFullHelp
+		return m, nil
+
+	case tea.KeyMsg:
+		return m.handleNavigation(msg)
+
+	case tea.MouseMsg:
+		// The wheel scrolls the schema
+		if delta := wheelDelta(msg); delta != 0 {
+			m.offset = Max(0, Min(m.maxOffset(), m.offset+delta))
+		}
+		return m, nil
+	}
+	return m, nil
+}
+
+func (m *SchemaModel) handleNavigation(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keyMap.Back):
+		m.gPressed = false
+		return m, func() tea.Msg { return NavigateBackMsg{} }
+
+	case key.Matches(msg, m.keyMap.CommandLine):
+		m.gPressed = false
+		return m, func() tea.Msg { return OpenCommandLineMsg{} }
+
+	case key.Matches(msg, m.keyMap.GoToStart):
+		if m.gPressed {
+			// Second g - go to beginning (gg pattern like vim)
+			m.offset = 0
+			m.gPressed = false
+		} else {
+			// First g - wait for second g to complete gg sequence
+			m.gPressed = true
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keyMap.GoToEnd):
+		m.offset = m.maxOffset()
+		m.gPressed = false
+		return m, nil
+
+	case key.Matches(msg, m.keyMap.Up):
+		m.gPressed = false
+		if m.offset > 0 {
+			m.offset--
+		}
+
+	case key.Matches(msg, m.keyMap.Down):
+		m.gPressed = false
+		if m.offset < m.maxOffset() {
+			m.offset++
+		}
+
+	default:
+		// Any other key resets the g state
+		m.gPressed = false
+	}
+	return m, nil
+}
+
+// visibleLines returns how many schema lines fit on screen
+func (m *SchemaModel) visibleLines() int {
+	return Max(1, m.Shared.Height-6)
+}
+
+func (m *SchemaModel) maxOffset() int {
+	return Max(0, len(m.lines())-m.visibleLines())
+}
+
+// lines renders the schema description, one entry per line
+func (m *SchemaModel) lines() []string {
+	var lines []string
+
+	lines = append(lines, "Columns:")
+	for _, col := range m.columns {
+		declType := col.Type
+		if declType == "" {
+			declType = "ANY"
+		}
+		parts := []string{declType}
+		if col.PrimaryKey > 0 {
+			parts = append(parts, "PRIMARY KEY")
+		}
+		if col.NotNull {
+			parts = append(parts, "NOT NULL")
+		}
+		if col.Default.Valid {
+			parts = append(parts, "DEFAULT "+col.Default.String)
+		}
+		lines = append(lines, fmt.Sprintf("  %s %s", col.Name, strings.Join(parts, " ")))
+	}
+
+	if len(m.foreignKeys) > 0 {
+		lines = append(lines, "", "Foreign keys:")
+		for _, fk := range m.foreignKeys {
+			lines = append(lines, fmt.Sprintf("  (%s) → %s(%s)",
+				strings.Join(fk.Columns, ", "), fk.RefTable, strings.Join(fk.RefColumns, ", ")))
+		}
+	}
+
+	if len(m.indexes) > 0 {
+		lines = append(lines, "", "Indexes:")
+		for _, idx := range m.indexes {
+			unique := ""
+			if idx.Unique {
+				unique = "UNIQUE "
+			}
+			lines = append(lines, fmt.Sprintf("  %s%s (%s)", unique, idx.Name, strings.Join(idx.Columns, ", ")))
+		}
+	}
+
+	if m.createSQL != "" {
+		lines = append(lines, "", "SQL:")
+		for _, line := range strings.Split(m.createSQL, "\n") {
+			lines = append(lines, "  "+line)
+		}
+	}
+	return lines
+}
+
+func (m *SchemaModel) View() string {
+	theme := m.Shared.theme()
+	var content strings.Builder
+
+	content.WriteString(theme.Title.Render(fmt.Sprintf("Schema: %s", m.tableName)))
+	content.WriteString("\n\n")
+
+	if m.err != nil {
+		content.WriteString(theme.Error.Render(fmt.Sprintf("Error: %v", m.err)))
+		content.WriteString("\n\n")
+	} else {
+		lines := m.lines()
+		end := Min(len(lines), m.offset+m.visibleLines())
+		for _, line := range lines[Min(m.offset, end):end] {
+			content.WriteString(theme.Normal.Render(line))
+			content.WriteString("\n")
+		}
+		content.WriteString("\n")
+	}
+
+	if m.showFullHelp {
+		content.WriteString(m.help.FullHelpView(m.keyMap.FullHelp()))
+	} else {
+		content.WriteString(m.help.ShortHelpView(m.keyMap.ShortHelp()))
+	}
+
+	return content.String()
+}
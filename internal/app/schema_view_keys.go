@@ -0,0 +1,61 @@
+package app
+
+import "github.com/charmbracelet/bubbles/key"
+
+// SchemaKeyMap defines keybindings for the schema view
+type SchemaKeyMap struct {
+	Up          key.Binding
+	Down        key.Binding
+	GoToStart   key.Binding
+	GoToEnd     key.Binding
+	Back        key.Binding
+	CommandLine key.Binding
+	ToggleHelp  key.Binding
+}
+
+// DefaultSchemaKeyMap returns the default keybindings for the schema view
+func DefaultSchemaKeyMap() SchemaKeyMap {
+	return SchemaKeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "scroll up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "scroll down"),
+		),
+		GoToStart: key.NewBinding(
+			key.WithKeys("g"),
+			key.WithHelp("gg", "go to start"),
+		),
+		GoToEnd: key.NewBinding(
+			key.WithKeys("G"),
+			key.WithHelp("G", "go to end"),
+		),
+		Back: key.NewBinding(
+			key.WithKeys("esc", "q"),
+			key.WithHelp("esc/q", "back"),
+		),
+		CommandLine: key.NewBinding(
+			key.WithKeys(":"),
+			key.WithHelp(":", "command"),
+		),
+		ToggleHelp: key.NewBinding(
+			key.WithKeys("ctrl+g"),
+			key.WithHelp("ctrl+g", "toggle help"),
+		),
+	}
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view
+func (k SchemaKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Back, k.ToggleHelp}
+}
+
+// FullHelp returns keybindings for the expanded help view
+func (k SchemaKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.GoToStart, k.GoToEnd},
+		{k.Back, k.ToggleHelp, k.CommandLine},
+	}
+}
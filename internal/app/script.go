@@ -0,0 +1,107 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+)
+
+// ParseOutputFormat looks up the output format of a script; "table" is an
+// ASCII table
+func ParseOutputFormat(name string) (ExportFormat, error) {
+	if name == "table" {
+		return FormatASCII, nil
+	}
+	format, err := ParseExportFormat(name)
+	if err != nil || format == FormatSQL {
+		return "", fmt.Errorf("unknown output format %q, use table, csv, tsv, json, ndjson, markdown or html", name)
+	}
+	return format, nil
+}
+
+// RunScript runs the statements of an SQL script in order and writes the
+// rows of every statement that returns any to w. It stops at the first
+// failing statement. The statements share one connection, so transactions
+// and temporary tables span the script.
+func RunScript(db *sql.DB, w io.Writer, script string, opts ExportOptions) error {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	results := 0
+	for i, stmt := range splitStatements(script) {
+		if len(significantTokens(lexSQL(stmt))) == 0 {
+			// Only comments
+			continue
+		}
+		// Temporary tables only exist on this connection, so look them up
+		// there
+		stored, _ := storedQuery(connQuerier{conn}, stmt)
+		rows, err := conn.QueryContext(ctx, stored)
+		if err != nil && stored != stmt {
+			rows, err = conn.QueryContext(ctx, stmt)
+		}
+		if err != nil {
+			return fmt.Errorf("statement %d: %w", i+1, err)
+		}
+		columns, err := rows.Columns()
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("statement %d: %w", i+1, err)
+		}
+		if len(columns) == 0 {
+			// Statements without results still run to completion
+			err = rows.Close()
+			if err == nil {
+				err = rows.Err()
+			}
+			if err != nil {
+				return fmt.Errorf("statement %d: %w", i+1, err)
+			}
+			continue
+		}
+
+		if results > 0 && separateResults(opts.Format) {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		results++
+		if _, err := ExportRows(w, rows, opts); err != nil {
+			return fmt.Errorf("statement %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// connQuerier runs queries on one connection
+type connQuerier struct {
+	*sql.Conn
+}
+
+// Query runs a query on the connection
+func (c connQuerier) Query(query string, args ...any) (*sql.Rows, error) {
+	return c.QueryContext(context.Background(), query, args...)
+}
+
+// isSelect reports whether a statement is a SELECT or VALUES, which only
+// reads
+func isSelect(stmt string) bool {
+	tokens := significantTokens(lexSQL(stmt))
+	verb := mainVerb(tokens)
+	return verb >= 0 && (tokens[verb].IsKeyword("SELECT") || tokens[verb].IsKeyword("VALUES"))
+}
+
+// separateResults reports whether a format needs a blank line between the
+// results of two statements
+func separateResults(format ExportFormat) bool {
+	switch format {
+	case FormatASCII, FormatMarkdown, FormatHTML:
+		return true
+	}
+	return false
+}
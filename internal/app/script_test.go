@@ -0,0 +1,70 @@
+package app
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunScript(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		format  ExportFormat
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "statements without rows",
+			script: "CREATE TABLE t(v); INSERT INTO t VALUES (1), (2); SELECT count(*) AS n FROM t",
+			format: FormatCSV,
+			want:   "n\n2\n",
+		},
+		{
+			name:   "comments only",
+			script: "SELECT 1 AS a; -- done\n/* nothing */;",
+			format: FormatCSV,
+			want:   "a\n1\n",
+		},
+		{
+			name:   "tables are separated",
+			script: "SELECT 1 AS a; SELECT 2 AS b",
+			format: FormatASCII,
+			want:   "+---+\n| a |\n+---+\n| 1 |\n+---+\n\n+---+\n| b |\n+---+\n| 2 |\n+---+\n",
+		},
+		{
+			name:   "transaction and temporary table",
+			script: "BEGIN; CREATE TEMP TABLE d(at DATE); INSERT INTO d VALUES ('2024-05-06T07:08:09Z'); SELECT at FROM d; COMMIT",
+			format: FormatNDJSON,
+			want:   `{"at":"2024-05-06T07:08:09Z"}` + "\n",
+		},
+		{
+			name:   "trigger body",
+			script: "CREATE TABLE t(v); CREATE TABLE log(v); CREATE TRIGGER tr AFTER INSERT ON t BEGIN INSERT INTO log VALUES (new.v); INSERT INTO log VALUES (-new.v); END; INSERT INTO t VALUES (3); SELECT group_concat(v) AS v FROM log",
+			format: FormatCSV,
+			want:   "v\n\"3,-3\"\n",
+		},
+		{
+			name:    "stops at the failing statement",
+			script:  "SELECT 1 AS a; SELECT * FROM missing; SELECT 2 AS b",
+			format:  FormatCSV,
+			want:    "a\n1\n",
+			wantErr: "statement 2:",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDatabase(t, "")
+			opts := DefaultExportOptions()
+			opts.Format = tt.format
+			var out bytes.Buffer
+			err := RunScript(db, &out, tt.script, opts)
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.HasPrefix(err.Error(), tt.wantErr)) {
+				t.Fatalf("got error %v, want %q", err, tt.wantErr)
+			}
+			if out.String() != tt.want {
+				t.Errorf("got %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
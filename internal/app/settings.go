@@ -0,0 +1,170 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+// Settings are user preferences shared by every tab
+type Settings struct {
+	// PageSize is the number of rows loaded per table page
+	PageSize int
+	// ReadOnly rejects edits and runs queries with PRAGMA query_only
+	ReadOnly bool
+	// SafeMode asks before running UPDATE or DELETE without WHERE, DROP
+	// and ALTER
+	SafeMode bool
+	// KeyMaps are the keybindings given to new views
+	KeyMaps KeyMaps
+	// Theme styles every view
+	Theme *Theme
+}
+
+// DefaultSettings returns the settings used when nothing is configured
+func DefaultSettings() *Settings {
+	return &Settings{PageSize: PageSize, KeyMaps: DefaultKeyMaps(), Theme: DarkTheme()}
+}
+
+// KeyMaps holds the keybindings of every view
+type KeyMaps struct {
+	App         AppKeyMap
+	TableList   TableListKeyMap
+	TableData   TableDataKeyMap
+	RowDetail   RowDetailKeyMap
+	EditCell    EditCellKeyMap
+	Query       QueryKeyMap
+	SQLEditor   SQLEditorKeyMap
+	MessageLog  MessageLogKeyMap
+	Schema      SchemaKeyMap
+	CommandLine CommandLineKeyMap
+	Palette     PaletteKeyMap
+	Export      ExportKeyMap
+	Import      ImportKeyMap
+	Status      StatusKeyMap
+	Picker      PickerKeyMap
+}
+
+// DefaultKeyMaps returns the default keybindings of every view
+func DefaultKeyMaps() KeyMaps {
+	return KeyMaps{
+		App:         DefaultAppKeyMap(),
+		TableList:   DefaultTableListKeyMap(),
+		TableData:   DefaultTableDataKeyMap(),
+		RowDetail:   DefaultRowDetailKeyMap(),
+		EditCell:    DefaultEditCellKeyMap(),
+		Query:       DefaultQueryKeyMap(),
+		SQLEditor:   DefaultSQLEditorKeyMap(),
+		MessageLog:  DefaultMessageLogKeyMap(),
+		Schema:      DefaultSchemaKeyMap(),
+		CommandLine: DefaultCommandLineKeyMap(),
+		Palette:     DefaultPaletteKeyMap(),
+		Export:      DefaultExportKeyMap(),
+		Import:      DefaultImportKeyMap(),
+		Status:      DefaultStatusKeyMap(),
+		Picker:      DefaultPickerKeyMap(),
+	}
+}
+
+// errReadOnly is returned for writes attempted in read-only mode
+var errReadOnly = errors.New("read-only mode: changes are disabled")
+
+// errQueryOnly is returned for a query that would lift PRAGMA query_only
+var errQueryOnly = errors.New("read-only query: PRAGMA query_only cannot be changed")
+
+// pageSize returns the configured page size
+func (s *SharedData) pageSize() int {
+	if s.Settings == nil || s.Settings.PageSize <= 0 {
+		return PageSize
+	}
+	return s.Settings.PageSize
+}
+
+// keyMaps returns the keybindings for new views
+func (s *SharedData) keyMaps() KeyMaps {
+	if s.Settings == nil {
+		return DefaultKeyMaps()
+	}
+	return s.Settings.KeyMaps
+}
+
+// readOnly reports whether changes are disabled
+func (s *SharedData) readOnly() bool {
+	return s.Settings != nil && s.Settings.ReadOnly
+}
+
+// safeMode reports whether risky statements need confirmation
+func (s *SharedData) safeMode() bool {
+	return s.Settings != nil && s.Settings.SafeMode
+}
+
+// queryRows runs a user query. In read-only mode it runs through
+// queryOnly, so SQLite rejects any write. release must be called after the
+// rows are closed.
+func (s *SharedData) queryRows(query string, args ...any) (*sql.Rows, func(), error) {
+	if !s.readOnly() {
+		rows, err := s.DB.Query(query, args...)
+		return rows, func() {}, err
+	}
+	rows, release, err := queryOnly(s.DB, query, args...)
+	if err != nil && (isReadOnlyError(err) || errors.Is(err, errQueryOnly)) {
+		err = errReadOnly
+	}
+	return rows, release, err
+}
+
+// isReadOnlyError reports whether SQLite refused a write to a read-only
+// connection or database
+func isReadOnlyError(err error) bool {
+	return strings.Contains(err.Error(), "attempt to write a readonly database")
+}
+
+// queryOnly runs a query on a dedicated connection with PRAGMA query_only
+// set, so running it cannot change the database. Queries that would set
+// the pragma themselves are rejected. release must be called after the
+// rows are closed.
+func queryOnly(db *sql.DB, query string, args ...any) (*sql.Rows, func(), error) {
+	if setsQueryOnly(query) {
+		return nil, nil, errQueryOnly
+	}
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := conn.ExecContext(ctx, "PRAGMA query_only = ON"); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	release := func() {
+		// The connection returns to the pool, so lift the restriction
+		conn.ExecContext(ctx, "PRAGMA query_only = OFF")
+		conn.Close()
+	}
+	rows, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+	return rows, release, nil
+}
+
+// setsQueryOnly reports whether any statement of query assigns PRAGMA
+// query_only, as in PRAGMA query_only = OFF or PRAGMA main.query_only(0)
+func setsQueryOnly(query string) bool {
+	for _, stmt := range splitStatements(query) {
+		tokens := significantTokens(lexSQL(stmt))
+		if len(tokens) < 2 || !tokens[0].IsKeyword("PRAGMA") {
+			continue
+		}
+		name := 1
+		if len(tokens) > 3 && tokens[2].Text == "." {
+			name = 3
+		}
+		if strings.EqualFold(tokens[name].Name(), "query_only") && len(tokens) > name+1 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package app
+
+import "testing"
+
+func TestReadOnlyQueriesCannotWrite(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"delete", "DELETE FROM t"},
+		{"lift query_only", "PRAGMA query_only = OFF; DELETE FROM t"},
+		{"lift query_only by call", "PRAGMA query_only(0); DELETE FROM t"},
+		{"qualified pragma", `PRAGMA "main".query_only = false; DELETE FROM t`},
+		{"after a select", "SELECT 1; PRAGMA QUERY_ONLY=0; DELETE FROM t"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSharedData(openTestDatabase(t, "CREATE TABLE t(v); INSERT INTO t VALUES (1)"))
+			s.Settings.ReadOnly = true
+			rows, release, err := s.queryRows(tt.query)
+			if err == nil {
+				for rows.Next() {
+				}
+				err = rows.Err()
+				rows.Close()
+				release()
+			}
+			if err == nil {
+				t.Error("expected the query to be rejected")
+			}
+			var count int
+			if err := s.DB.QueryRow("SELECT count(*) FROM t").Scan(&count); err != nil || count != 1 {
+				t.Errorf("table has %d rows, err %v", count, err)
+			}
+		})
+	}
+}
+
+func TestSetsQueryOnly(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"PRAGMA query_only", false},
+		{"PRAGMA main.query_only;", false},
+		{"PRAGMA query_only = 1", true},
+		{"PRAGMA table_info(t)", false},
+		{"SELECT 'PRAGMA query_only = 0'", false},
+	}
+	for _, tt := range tests {
+		if got := setsQueryOnly(tt.query); got != tt.want {
+			t.Errorf("setsQueryOnly(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,538 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+// SQLEditor is a multi-line SQL input with syntax highlighting, line
+// numbers, parenthesis matching and an error marker
+type SQLEditor struct {
+	Placeholder     string
+	ShowLineNumbers bool
+	KeyMap          SQLEditorKeyMap
+	Theme           *Theme
+
+	lines   [][]rune
+	row     int
+	col     int
+	width   int
+	height  int
+	yOffset int
+	xOffset int
+	focused bool
+
+	// Byte range of the last reported error, errStart < 0 when unset
+	errStart int
+	errEnd   int
+}
+
+// NewSQLEditor creates an empty editor
+func NewSQLEditor() SQLEditor {
+	return SQLEditor{
+		ShowLineNumbers: true,
+		KeyMap:          DefaultSQLEditorKeyMap(),
+		lines:           [][]rune{{}},
+		width:           60,
+		height:          3,
+		errStart:        -1,
+	}
+}
+
+// Value returns the editor contents
+func (e *SQLEditor) Value() string {
+	lines := make([]string, len(e.lines))
+	for i, l := range e.lines {
+		lines[i] = string(l)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SetValue replaces the contents and moves the cursor to the end
+func (e *SQLEditor) SetValue(s string) {
+	e.lines = nil
+	for _, l := range strings.Split(s, "\n") {
+		e.lines = append(e.lines, []rune(l))
+	}
+	e.row = len(e.lines) - 1
+	e.col = len(e.lines[e.row])
+	e.ClearError()
+	e.scrollToCursor()
+}
+
+// MoveTo moves the cursor to a line and column, clamped to the text
+func (e *SQLEditor) MoveTo(row, col int) {
+	e.row = Max(0, Min(row, len(e.lines)-1))
+	e.col = Max(0, Min(col, len(e.lines[e.row])))
+	e.scrollToCursor()
+}
+
+// InsertString inserts s at the cursor
+func (e *SQLEditor) InsertString(s string) {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\t", "    ")
+	parts := strings.Split(s, "\n")
+
+	line := e.lines[e.row]
+	tail := append([]rune(nil), line[e.col:]...)
+	head := append(line[:e.col:e.col], []rune(parts[0])...)
+
+	if len(parts) == 1 {
+		e.lines[e.row] = append(head, tail...)
+		e.col = len(head)
+	} else {
+		newLines := [][]rune{head}
+		for _, p := range parts[1 : len(parts)-1] {
+			newLines = append(newLines, []rune(p))
+		}
+		last := []rune(parts[len(parts)-1])
+		newLines = append(newLines, append(last, tail...))
+
+		e.lines = append(e.lines[:e.row], append(newLines, e.lines[e.row+1:]...)...)
+		e.row += len(parts) - 1
+		e.col = len(last)
+	}
+	e.ClearError()
+	e.scrollToCursor()
+}
+
+// DeleteBefore removes up to n runes before the cursor on the current line
+func (e *SQLEditor) DeleteBefore(n int) {
+	n = Min(n, e.col)
+	line := e.lines[e.row]
+	e.lines[e.row] = append(line[:e.col-n], line[e.col:]...)
+	e.col -= n
+	e.ClearError()
+	e.scrollToCursor()
+}
+
+// CursorOffset returns the byte offset of the cursor in Value()
+func (e *SQLEditor) CursorOffset() int {
+	offset := 0
+	for i := range e.row {
+		offset += len(string(e.lines[i])) + 1
+	}
+	return offset + len(string(e.lines[e.row][:e.col]))
+}
+
+// LineCount returns the number of lines in the editor
+func (e *SQLEditor) LineCount() int {
+	return len(e.lines)
+}
+
+// Focus enables keyboard input and shows the cursor
+func (e *SQLEditor) Focus() {
+	e.focused = true
+}
+
+// Blur disables keyboard input and hides the cursor
+func (e *SQLEditor) Blur() {
+	e.focused = false
+}
+
+// Focused returns the focus state
+func (e *SQLEditor) Focused() bool {
+	return e.focused
+}
+
+// SetSize sets the outer width (including the line number gutter) and the
+// number of visible lines
+func (e *SQLEditor) SetSize(width, height int) {
+	e.width = Max(10, width)
+	e.height = Max(1, height)
+	e.scrollToCursor()
+}
+
+// Height returns the number of visible lines
+func (e *SQLEditor) Height() int {
+	return e.height
+}
+
+// MarkError highlights the byte range [start, end) of Value() as the
+// location of an error. The mark is cleared by the next edit.
+func (e *SQLEditor) MarkError(start, end int) {
+	e.errStart = start
+	e.errEnd = Max(end, start+1)
+}
+
+// ClearError removes the error mark
+func (e *SQLEditor) ClearError() {
+	e.errStart = -1
+	e.errEnd = -1
+}
+
+// Update handles editing keys. Keys it does not recognise are ignored.
+func (e *SQLEditor) Update(msg tea.Msg) tea.Cmd {
+	if !e.focused {
+		return nil
+	}
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, e.KeyMap.NewLine):
+		e.InsertString("\n")
+	case key.Matches(keyMsg, e.KeyMap.DeleteBack):
+		e.deleteBack()
+	case key.Matches(keyMsg, e.KeyMap.DeleteForward):
+		e.deleteForward()
+	case key.Matches(keyMsg, e.KeyMap.DeleteWord):
+		start := e.col
+		e.wordLeft()
+		if e.col < start {
+			line := e.lines[e.row]
+			e.lines[e.row] = append(line[:e.col], line[start:]...)
+			e.ClearError()
+		}
+	case key.Matches(keyMsg, e.KeyMap.DeleteLineStart):
+		e.lines[e.row] = e.lines[e.row][e.col:]
+		e.col = 0
+		e.ClearError()
+	case key.Matches(keyMsg, e.KeyMap.DeleteLineEnd):
+		e.lines[e.row] = e.lines[e.row][:e.col]
+		e.ClearError()
+	case key.Matches(keyMsg, e.KeyMap.WordLeft):
+		e.wordLeft()
+	case key.Matches(keyMsg, e.KeyMap.WordRight):
+		e.wordRight()
+	case key.Matches(keyMsg, e.KeyMap.CharLeft):
+		if e.col > 0 {
+			e.col--
+		} else if e.row > 0 {
+			e.row--
+			e.col = len(e.lines[e.row])
+		}
+	case key.Matches(keyMsg, e.KeyMap.CharRight):
+		if e.col < len(e.lines[e.row]) {
+			e.col++
+		} else if e.row < len(e.lines)-1 {
+			e.row++
+			e.col = 0
+		}
+	case key.Matches(keyMsg, e.KeyMap.LineUp):
+		e.moveRows(-1)
+	case key.Matches(keyMsg, e.KeyMap.LineDown):
+		e.moveRows(1)
+	case key.Matches(keyMsg, e.KeyMap.PageUp):
+		e.moveRows(-e.height)
+	case key.Matches(keyMsg, e.KeyMap.PageDown):
+		e.moveRows(e.height)
+	case key.Matches(keyMsg, e.KeyMap.LineStart):
+		e.col = 0
+	case key.Matches(keyMsg, e.KeyMap.LineEnd):
+		e.col = len(e.lines[e.row])
+	case key.Matches(keyMsg, e.KeyMap.DocStart):
+		e.row, e.col = 0, 0
+	case key.Matches(keyMsg, e.KeyMap.DocEnd):
+		e.row = len(e.lines) - 1
+		e.col = len(e.lines[e.row])
+	case (keyMsg.Type == tea.KeyRunes || keyMsg.Type == tea.KeySpace) && !keyMsg.Alt:
+		// Alt+key is a shortcut, not text
+		e.InsertString(string(keyMsg.Runes))
+	}
+
+	e.scrollToCursor()
+	return nil
+}
+
+func (e *SQLEditor) deleteBack() {
+	if e.col > 0 {
+		e.DeleteBefore(1)
+		return
+	}
+	if e.row == 0 {
+		return
+	}
+	prev := e.lines[e.row-1]
+	e.col = len(prev)
+	e.lines[e.row-1] = append(prev, e.lines[e.row]...)
+	e.lines = append(e.lines[:e.row], e.lines[e.row+1:]...)
+	e.row--
+	e.ClearError()
+}
+
+func (e *SQLEditor) deleteForward() {
+	line := e.lines[e.row]
+	if e.col < len(line) {
+		e.lines[e.row] = append(line[:e.col], line[e.col+1:]...)
+	} else if e.row < len(e.lines)-1 {
+		e.lines[e.row] = append(line, e.lines[e.row+1]...)
+		e.lines = append(e.lines[:e.row+1], e.lines[e.row+2:]...)
+	}
+	e.ClearError()
+}
+
+func (e *SQLEditor) moveRows(n int) {
+	e.row = Max(0, Min(len(e.lines)-1, e.row+n))
+	e.col = Min(e.col, len(e.lines[e.row]))
+}
+
+func (e *SQLEditor) wordLeft() {
+	line := e.lines[e.row]
+	for e.col > 0 && unicode.IsSpace(line[e.col-1]) {
+		e.col--
+	}
+	for e.col > 0 && !unicode.IsSpace(line[e.col-1]) {
+		e.col--
+	}
+}
+
+func (e *SQLEditor) wordRight() {
+	line := e.lines[e.row]
+	for e.col < len(line) && unicode.IsSpace(line[e.col]) {
+		e.col++
+	}
+	for e.col < len(line) && !unicode.IsSpace(line[e.col]) {
+		e.col++
+	}
+}
+
+func (e *SQLEditor) gutterWidth() int {
+	if !e.ShowLineNumbers {
+		return 0
+	}
+	return len(fmt.Sprint(len(e.lines))) + 2
+}
+
+func (e *SQLEditor) textWidth() int {
+	return Max(1, e.width-e.gutterWidth())
+}
+
+// scrollToCursor adjusts the viewport so the cursor is visible
+func (e *SQLEditor) scrollToCursor() {
+	if e.row < e.yOffset {
+		e.yOffset = e.row
+	} else if e.row >= e.yOffset+e.height {
+		e.yOffset = e.row - e.height + 1
+	}
+	e.yOffset = Max(0, Min(e.yOffset, len(e.lines)-e.height))
+
+	cursorX := runewidth.StringWidth(string(e.lines[e.row][:e.col]))
+	if cursorX < e.xOffset {
+		e.xOffset = cursorX
+	} else if cursorX >= e.xOffset+e.textWidth() {
+		e.xOffset = cursorX - e.textWidth() + 1
+	}
+}
+
+// matchingParens returns the byte offsets of the parenthesis next to the
+// cursor and its partner. partner is -1 when the parenthesis is unbalanced.
+func (e *SQLEditor) matchingParens(tokens []sqlToken) (int, int) {
+	cursor := e.CursorOffset()
+	at := -1
+	for i, t := range tokens {
+		if t.Kind != tokenPunct || (t.Text != "(" && t.Text != ")") {
+			continue
+		}
+		if t.Start == cursor || (t.End == cursor && at < 0) {
+			at = i
+		}
+	}
+	if at < 0 {
+		return -1, -1
+	}
+
+	depth := 0
+	step := 1
+	if tokens[at].Text == ")" {
+		step = -1
+	}
+	for i := at; i >= 0 && i < len(tokens); i += step {
+		t := tokens[i]
+		if t.Kind != tokenPunct {
+			continue
+		}
+		switch t.Text {
+		case "(":
+			depth += step
+		case ")":
+			depth -= step
+		}
+		if depth == 0 {
+			return tokens[at].Start, t.Start
+		}
+	}
+	return tokens[at].Start, -1
+}
+
+func (t *Theme) tokenStyle(kind tokenKind) (lipgloss.Style, bool) {
+	switch kind {
+	case tokenKeyword:
+		return t.SQLKeyword, true
+	case tokenString, tokenBlob:
+		return t.SQLString, true
+	case tokenNumber:
+		return t.SQLNumber, true
+	case tokenComment:
+		return t.SQLComment, true
+	case tokenIdent, tokenQuotedIdent:
+		return t.SQLIdentifier, true
+	}
+	return lipgloss.Style{}, false
+}
+
+// View renders the visible lines with highlighting
+func (e *SQLEditor) View() string {
+	theme := e.Theme
+	if theme == nil {
+		theme = fallbackTheme
+	}
+	value := e.Value()
+	if value == "" && !e.focused && e.Placeholder != "" {
+		return theme.Help.Render(e.Placeholder)
+	}
+
+	tokens := lexSQL(value)
+	parenAt, parenMatch := -1, -1
+	if e.focused {
+		parenAt, parenMatch = e.matchingParens(tokens)
+	}
+
+	// Byte offset at which each line starts
+	lineStarts := make([]int, len(e.lines))
+	offset := 0
+	for i, l := range e.lines {
+		lineStarts[i] = offset
+		offset += len(string(l)) + 1
+	}
+
+	errorRow := -1
+	if e.errStart >= 0 {
+		for i := range e.lines {
+			if lineStarts[i] <= e.errStart {
+				errorRow = i
+			}
+		}
+	}
+
+	gutter := e.gutterWidth()
+	textWidth := e.textWidth()
+	var out []string
+	tokenIdx := 0
+
+	for row := e.yOffset; row < Min(len(e.lines), e.yOffset+e.height); row++ {
+		var b strings.Builder
+
+		if gutter > 0 {
+			num := fmt.Sprintf("%*d ", gutter-2, row+1)
+			if row == errorRow {
+				b.WriteString(theme.Error.Render(num + "▶"))
+			} else {
+				b.WriteString(theme.LineNumber.Render(num + "│"))
+			}
+		}
+
+		byteOffset := lineStarts[row]
+		x := 0
+		for col, r := range e.lines[row] {
+			w := runewidth.RuneWidth(r)
+			pos := byteOffset
+			byteOffset += len(string(r))
+			if x < e.xOffset {
+				x += w
+				continue
+			}
+			if x+w > e.xOffset+textWidth {
+				break
+			}
+			x += w
+
+			for tokenIdx < len(tokens) && tokens[tokenIdx].End <= pos {
+				tokenIdx++
+			}
+			ch := string(r)
+			style, styled := lipgloss.Style{}, false
+			if tokenIdx < len(tokens) && tokens[tokenIdx].Start <= pos {
+				style, styled = theme.tokenStyle(tokens[tokenIdx].Kind)
+			}
+
+			switch {
+			case e.focused && row == e.row && col == e.col:
+				b.WriteString(theme.Cursor.Render(ch))
+			case pos >= e.errStart && pos < e.errEnd && e.errStart >= 0:
+				b.WriteString(theme.ErrorMark.Render(ch))
+			case pos == parenAt && parenMatch < 0:
+				b.WriteString(theme.Error.Render(ch))
+			case pos == parenAt || pos == parenMatch:
+				b.WriteString(theme.MatchedParen.Render(ch))
+			case styled:
+				b.WriteString(style.Render(ch))
+			default:
+				b.WriteString(ch)
+			}
+		}
+		if e.focused && row == e.row && e.col == len(e.lines[row]) {
+			b.WriteString(theme.Cursor.Render(" "))
+		}
+		out = append(out, b.String())
+	}
+
+	return strings.Join(out, "\n")
+}
+
+var (
+	nearErrorRe    = regexp.MustCompile(`near "((?:[^"]|"")*)": syntax error`)
+	namedErrorRe   = regexp.MustCompile(`(?:no such (?:table|column|function|collation sequence)|ambiguous column name|wrong number of arguments to function|misuse of aggregate(?: function)?):\s*([^\s(]+)`)
+	unrecognizedRe = regexp.MustCompile(`unrecognized token: "((?:[^"]|"")*)"`)
+)
+
+// locateSQLError guesses the byte range in query that an SQLite error
+// message refers to. ok is false when no location can be determined.
+func locateSQLError(query string, err error) (start, end int, ok bool) {
+	if err == nil {
+		return 0, 0, false
+	}
+	msg := err.Error()
+	tokens := significantTokens(lexSQL(query))
+
+	if strings.Contains(msg, "incomplete input") {
+		if len(tokens) == 0 {
+			return 0, 0, false
+		}
+		last := tokens[len(tokens)-1]
+		return last.Start, last.End, true
+	}
+
+	if m := nearErrorRe.FindStringSubmatch(msg); m != nil {
+		near := strings.ReplaceAll(m[1], `""`, `"`)
+		for _, t := range tokens {
+			if strings.EqualFold(t.Text, near) {
+				return t.Start, t.End, true
+			}
+		}
+		if i := strings.Index(query, near); i >= 0 {
+			return i, i + len(near), true
+		}
+	}
+
+	if m := unrecognizedRe.FindStringSubmatch(msg); m != nil {
+		tok := strings.ReplaceAll(m[1], `""`, `"`)
+		if i := strings.Index(query, tok); i >= 0 {
+			return i, i + len(tok), true
+		}
+	}
+
+	if m := namedErrorRe.FindStringSubmatch(msg); m != nil {
+		name := m[1]
+		// "t.col" style names refer to the last component
+		parts := strings.Split(name, ".")
+		last := parts[len(parts)-1]
+		for _, t := range tokens {
+			if (t.Kind == tokenIdent || t.Kind == tokenQuotedIdent || t.Kind == tokenKeyword) &&
+				strings.EqualFold(t.Name(), last) {
+				return t.Start, t.End, true
+			}
+		}
+	}
+
+	return 0, 0, false
+}
@@ -0,0 +1,103 @@
+package app
+
+import "github.com/charmbracelet/bubbles/key"
+
+// SQLEditorKeyMap defines the editing keybindings of the SQL editor
+type SQLEditorKeyMap struct {
+	CharLeft        key.Binding
+	CharRight       key.Binding
+	WordLeft        key.Binding
+	WordRight       key.Binding
+	LineUp          key.Binding
+	LineDown        key.Binding
+	LineStart       key.Binding
+	LineEnd         key.Binding
+	PageUp          key.Binding
+	PageDown        key.Binding
+	DocStart        key.Binding
+	DocEnd          key.Binding
+	NewLine         key.Binding
+	DeleteBack      key.Binding
+	DeleteForward   key.Binding
+	DeleteWord      key.Binding
+	DeleteLineStart key.Binding
+	DeleteLineEnd   key.Binding
+}
+
+// DefaultSQLEditorKeyMap returns the default keybindings for the SQL editor
+func DefaultSQLEditorKeyMap() SQLEditorKeyMap {
+	return SQLEditorKeyMap{
+		CharLeft: key.NewBinding(
+			key.WithKeys("left", "ctrl+b"),
+			key.WithHelp("←", "cursor left"),
+		),
+		CharRight: key.NewBinding(
+			key.WithKeys("right", "ctrl+f"),
+			key.WithHelp("→", "cursor right"),
+		),
+		WordLeft: key.NewBinding(
+			key.WithKeys("ctrl+left", "alt+left", "alt+b"),
+			key.WithHelp("ctrl+←", "word left"),
+		),
+		WordRight: key.NewBinding(
+			key.WithKeys("ctrl+right", "alt+right", "alt+f"),
+			key.WithHelp("ctrl+→", "word right"),
+		),
+		LineUp: key.NewBinding(
+			key.WithKeys("up"),
+			key.WithHelp("↑", "line up"),
+		),
+		LineDown: key.NewBinding(
+			key.WithKeys("down"),
+			key.WithHelp("↓", "line down"),
+		),
+		LineStart: key.NewBinding(
+			key.WithKeys("home", "ctrl+a"),
+			key.WithHelp("home/ctrl+a", "line start"),
+		),
+		LineEnd: key.NewBinding(
+			key.WithKeys("end", "ctrl+e"),
+			key.WithHelp("end/ctrl+e", "line end"),
+		),
+		PageUp: key.NewBinding(
+			key.WithKeys("pgup"),
+			key.WithHelp("pgup", "page up"),
+		),
+		PageDown: key.NewBinding(
+			key.WithKeys("pgdown"),
+			key.WithHelp("pgdown", "page down"),
+		),
+		DocStart: key.NewBinding(
+			key.WithKeys("ctrl+home"),
+			key.WithHelp("ctrl+home", "start of query"),
+		),
+		DocEnd: key.NewBinding(
+			key.WithKeys("ctrl+end"),
+			key.WithHelp("ctrl+end", "end of query"),
+		),
+		NewLine: key.NewBinding(
+			key.WithKeys("alt+enter", "ctrl+j"),
+			key.WithHelp("alt+enter/ctrl+j", "new line"),
+		),
+		DeleteBack: key.NewBinding(
+			key.WithKeys("backspace", "ctrl+h"),
+			key.WithHelp("backspace", "delete char"),
+		),
+		DeleteForward: key.NewBinding(
+			key.WithKeys("delete", "ctrl+d"),
+			key.WithHelp("delete", "delete next char"),
+		),
+		DeleteWord: key.NewBinding(
+			key.WithKeys("ctrl+w", "alt+backspace"),
+			key.WithHelp("ctrl+w", "delete word"),
+		),
+		DeleteLineStart: key.NewBinding(
+			key.WithKeys("ctrl+u"),
+			key.WithHelp("ctrl+u", "delete to line start"),
+		),
+		DeleteLineEnd: key.NewBinding(
+			key.WithKeys("ctrl+k"),
+			key.WithHelp("ctrl+k", "delete to line end"),
+		),
+	}
+}
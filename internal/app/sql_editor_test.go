@@ -0,0 +1,31 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSQLEditorInsertsText(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []tea.KeyMsg
+		want string
+	}{
+		{"runes", []tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune("ab")}}, "ab"},
+		{"space", []tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune("a")}, {Type: tea.KeySpace, Runes: []rune(" ")}}, "a "},
+		{"alt key", []tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune("a")}, {Type: tea.KeyRunes, Runes: []rune("b"), Alt: true}}, "a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewSQLEditor()
+			e.Focus()
+			for _, k := range tt.keys {
+				e.Update(k)
+			}
+			if got := e.Value(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
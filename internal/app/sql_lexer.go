@@ -0,0 +1,347 @@
+package app
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tokenKind classifies a lexical SQL token
+type tokenKind int
+
+const (
+	tokenWhitespace tokenKind = iota
+	tokenComment
+	tokenKeyword
+	tokenIdent
+	tokenQuotedIdent
+	tokenString
+	tokenNumber
+	tokenBlob
+	tokenParam
+	tokenOperator
+	tokenPunct
+)
+
+// sqlToken is a single token with its byte offsets in the source text
+type sqlToken struct {
+	Kind  tokenKind
+	Text  string
+	Start int
+	End   int
+	// open is set for a string, blob, quoted identifier or block comment
+	// that runs to the end of the input
+	open bool
+}
+
+// IsKeyword reports whether the token is the given keyword (case-insensitive)
+func (t sqlToken) IsKeyword(kw string) bool {
+	return t.Kind == tokenKeyword && strings.EqualFold(t.Text, kw)
+}
+
+// Significant reports whether the token carries meaning (not whitespace or a comment)
+func (t sqlToken) Significant() bool {
+	return t.Kind != tokenWhitespace && t.Kind != tokenComment
+}
+
+// Closed reports whether a string, blob, quoted identifier or comment token
+// is terminated. Line comments are never considered closed.
+func (t sqlToken) Closed() bool {
+	if t.Kind == tokenComment && strings.HasPrefix(t.Text, "--") {
+		return false
+	}
+	return !t.open
+}
+
+// Name returns the identifier a token refers to, with any quoting removed
+func (t sqlToken) Name() string {
+	if t.Kind != tokenQuotedIdent || len(t.Text) < 2 {
+		return t.Text
+	}
+	inner := t.Text[1 : len(t.Text)-1]
+	switch t.Text[0] {
+	case '"':
+		return strings.ReplaceAll(inner, `""`, `"`)
+	case '`':
+		return strings.ReplaceAll(inner, "``", "`")
+	}
+	return inner
+}
+
+// sqlKeywords is the list of keywords recognised by SQLite
+var sqlKeywords = []string{
+	"ABORT", "ACTION", "ADD", "AFTER", "ALL", "ALTER", "ALWAYS", "ANALYZE", "AND", "AS",
+	"ASC", "ATTACH", "AUTOINCREMENT", "BEFORE", "BEGIN", "BETWEEN", "BY", "CASCADE", "CASE", "CAST",
+	"CHECK", "COLLATE", "COLUMN", "COMMIT", "CONFLICT", "CONSTRAINT", "CREATE", "CROSS", "CURRENT",
+	"CURRENT_DATE", "CURRENT_TIME", "CURRENT_TIMESTAMP", "DATABASE", "DEFAULT", "DEFERRABLE",
+	"DEFERRED", "DELETE", "DESC", "DETACH", "DISTINCT", "DO", "DROP", "EACH", "ELSE", "END",
+	"ESCAPE", "EXCEPT", "EXCLUDE", "EXCLUSIVE", "EXISTS", "EXPLAIN", "FAIL", "FILTER", "FIRST",
+	"FOLLOWING", "FOR", "FOREIGN", "FROM", "FULL", "GENERATED", "GLOB", "GROUP", "GROUPS", "HAVING",
+	"IF", "IGNORE", "IMMEDIATE", "IN", "INDEX", "INDEXED", "INITIALLY", "INNER", "INSERT", "INSTEAD",
+	"INTERSECT", "INTO", "IS", "ISNULL", "JOIN", "KEY", "LAST", "LEFT", "LIKE", "LIMIT", "MATCH",
+	"MATERIALIZED", "NATURAL", "NO", "NOT", "NOTHING", "NOTNULL", "NULL", "NULLS", "OF", "OFFSET",
+	"ON", "OR", "ORDER", "OTHERS", "OUTER", "OVER", "PARTITION", "PLAN", "PRAGMA", "PRECEDING",
+	"PRIMARY", "QUERY", "RAISE", "RANGE", "RECURSIVE", "REFERENCES", "REGEXP", "REINDEX", "RELEASE",
+	"RENAME", "REPLACE", "RESTRICT", "RETURNING", "RIGHT", "ROLLBACK", "ROW", "ROWS", "SAVEPOINT",
+	"SELECT", "SET", "STRICT", "TABLE", "TEMP", "TEMPORARY", "THEN", "TIES", "TO", "TRANSACTION",
+	"TRIGGER", "UNBOUNDED", "UNION", "UNIQUE", "UPDATE", "USING", "VACUUM", "VALUES", "VIEW",
+	"VIRTUAL", "WHEN", "WHERE", "WINDOW", "WITH", "WITHOUT",
+}
+
+// multiCharOperators lists operators longer than one character, longest first
+var multiCharOperators = []string{"->>", "||", "<<", ">>", "<=", ">=", "==", "!=", "<>", "->"}
+
+var sqlKeywordSet = func() map[string]bool {
+	set := make(map[string]bool, len(sqlKeywords))
+	for _, kw := range sqlKeywords {
+		set[kw] = true
+	}
+	return set
+}()
+
+// isSQLKeyword reports whether word is an SQLite keyword
+func isSQLKeyword(word string) bool {
+	return sqlKeywordSet[strings.ToUpper(word)]
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || r >= utf8.RuneSelf
+}
+
+func isIdentChar(r rune) bool {
+	return isIdentStart(r) || r == '$' || unicode.IsDigit(r)
+}
+
+// lexSQL splits src into tokens. It never fails: unterminated strings and
+// comments simply run to the end of the input.
+func lexSQL(src string) []sqlToken {
+	var tokens []sqlToken
+	i := 0
+	for i < len(src) {
+		start := i
+		r, size := utf8.DecodeRuneInString(src[i:])
+		kind := tokenPunct
+		closed := true
+
+		switch {
+		case unicode.IsSpace(r):
+			kind = tokenWhitespace
+			for i < len(src) {
+				r, size = utf8.DecodeRuneInString(src[i:])
+				if !unicode.IsSpace(r) {
+					break
+				}
+				i += size
+			}
+
+		case strings.HasPrefix(src[i:], "--"):
+			kind = tokenComment
+			if end := strings.IndexByte(src[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = len(src)
+			}
+
+		case strings.HasPrefix(src[i:], "/*"):
+			kind = tokenComment
+			if end := strings.Index(src[i+2:], "*/"); end >= 0 {
+				i += end + 4
+			} else {
+				i, closed = len(src), false
+			}
+
+		case (r == 'x' || r == 'X') && i+1 < len(src) && src[i+1] == '\'':
+			kind = tokenBlob
+			i, closed = scanQuoted(src, i+1, '\'')
+
+		case r == '\'':
+			kind = tokenString
+			i, closed = scanQuoted(src, i, '\'')
+
+		case r == '"' || r == '`':
+			kind = tokenQuotedIdent
+			i, closed = scanQuoted(src, i, byte(r))
+
+		case r == '[':
+			kind = tokenQuotedIdent
+			if end := strings.IndexByte(src[i:], ']'); end >= 0 {
+				i += end + 1
+			} else {
+				i, closed = len(src), false
+			}
+
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9'):
+			kind = tokenNumber
+			i = scanNumber(src, i)
+
+		case r == '?' || r == ':' || r == '@' || (r == '$' && i+1 < len(src) && isIdentStart(rune(src[i+1]))):
+			kind = tokenParam
+			i += size
+			for i < len(src) {
+				r, size = utf8.DecodeRuneInString(src[i:])
+				if !isIdentChar(r) {
+					break
+				}
+				i += size
+			}
+
+		case isIdentStart(r):
+			for i < len(src) {
+				r, size = utf8.DecodeRuneInString(src[i:])
+				if !isIdentChar(r) {
+					break
+				}
+				i += size
+			}
+			kind = tokenIdent
+			if isSQLKeyword(src[start:i]) {
+				kind = tokenKeyword
+			}
+
+		case strings.ContainsRune("(),;.", r):
+			i += size
+
+		default:
+			kind = tokenOperator
+			i += size
+			for _, op := range multiCharOperators {
+				if strings.HasPrefix(src[start:], op) {
+					i = start + len(op)
+					break
+				}
+			}
+		}
+
+		tokens = append(tokens, sqlToken{Kind: kind, Text: src[start:i], Start: start, End: i, open: !closed})
+	}
+	return tokens
+}
+
+// scanQuoted returns the offset just past a quoted section starting at
+// src[start], treating a doubled quote character as an escape, and whether
+// the closing quote was found.
+func scanQuoted(src string, start int, quote byte) (int, bool) {
+	i := start + 1
+	for i < len(src) {
+		if src[i] == quote {
+			if i+1 < len(src) && src[i+1] == quote {
+				i += 2
+				continue
+			}
+			return i + 1, true
+		}
+		i++
+	}
+	return len(src), false
+}
+
+func scanNumber(src string, start int) int {
+	i := start
+	if strings.HasPrefix(strings.ToLower(src[i:]), "0x") {
+		i += 2
+		for i < len(src) && strings.IndexByte("0123456789abcdefABCDEF", src[i]) >= 0 {
+			i++
+		}
+		return i
+	}
+	for i < len(src) && (src[i] >= '0' && src[i] <= '9' || src[i] == '.' || src[i] == '_') {
+		i++
+	}
+	if i < len(src) && (src[i] == 'e' || src[i] == 'E') {
+		j := i + 1
+		if j < len(src) && (src[j] == '+' || src[j] == '-') {
+			j++
+		}
+		if j < len(src) && src[j] >= '0' && src[j] <= '9' {
+			i = j
+			for i < len(src) && src[i] >= '0' && src[i] <= '9' {
+				i++
+			}
+		}
+	}
+	return i
+}
+
+// significantTokens filters out whitespace and comments
+func significantTokens(tokens []sqlToken) []sqlToken {
+	var out []sqlToken
+	for _, t := range tokens {
+		if t.Significant() {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// statementAt returns the tokens of the statement containing offset,
+// splitting on the semicolons that end statements
+func statementAt(tokens []sqlToken, offset int) []sqlToken {
+	start := 0
+	for _, i := range statementEnds(tokens) {
+		if tokens[i].End > offset {
+			return tokens[start:i]
+		}
+		start = i + 1
+	}
+	return tokens[start:]
+}
+
+// splitStatements splits SQL text into individual statements on top-level
+// semicolons. Semicolons inside a CREATE TRIGGER body do not split.
+func splitStatements(query string) []string {
+	var stmts []string
+	start := 0
+	tokens := lexSQL(query)
+	for _, i := range statementEnds(tokens) {
+		if s := strings.TrimSpace(query[start:tokens[i].Start]); s != "" {
+			stmts = append(stmts, s)
+		}
+		start = tokens[i].End
+	}
+	if s := strings.TrimSpace(query[start:]); s != "" {
+		stmts = append(stmts, s)
+	}
+	return stmts
+}
+
+// statementEnds returns the indexes of the semicolons that end statements,
+// leaving out those inside a CREATE TRIGGER body
+func statementEnds(tokens []sqlToken) []int {
+	var ends []int
+	first := sqlToken{}
+	sawTrigger, inBody := false, false
+	caseDepth := 0
+
+	for i, t := range tokens {
+		if !t.Significant() {
+			continue
+		}
+		if first.Text == "" {
+			first = t
+		}
+		switch {
+		case t.IsKeyword("TRIGGER") && first.IsKeyword("CREATE"):
+			sawTrigger = true
+		case t.IsKeyword("BEGIN") && sawTrigger:
+			inBody = true
+		case t.IsKeyword("CASE") && inBody:
+			caseDepth++
+		case t.IsKeyword("END") && inBody:
+			if caseDepth > 0 {
+				caseDepth--
+			} else {
+				inBody = false
+			}
+		case t.Kind == tokenPunct && t.Text == ";" && !inBody:
+			ends = append(ends, i)
+			first = sqlToken{}
+			sawTrigger = false
+		}
+	}
+	return ends
+}
+
+// quoteIdent quotes an SQL identifier for safe interpolation
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,127 @@
+package app
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLexSQL(t *testing.T) {
+	tests := []struct {
+		src   string
+		kinds []tokenKind
+		texts []string
+	}{
+		{
+			src:   "SELECT a, \"b c\" FROM t",
+			kinds: []tokenKind{tokenKeyword, tokenWhitespace, tokenIdent, tokenPunct, tokenWhitespace, tokenQuotedIdent, tokenWhitespace, tokenKeyword, tokenWhitespace, tokenIdent},
+			texts: []string{"SELECT", " ", "a", ",", " ", `"b c"`, " ", "FROM", " ", "t"},
+		},
+		{
+			src:   "x'00ff' 'it''s' 1.5e3 0x1F",
+			kinds: []tokenKind{tokenBlob, tokenWhitespace, tokenString, tokenWhitespace, tokenNumber, tokenWhitespace, tokenNumber},
+			texts: []string{"x'00ff'", " ", "'it''s'", " ", "1.5e3", " ", "0x1F"},
+		},
+		{
+			src:   "a<>? -- note\n/* block */:name",
+			kinds: []tokenKind{tokenIdent, tokenOperator, tokenParam, tokenWhitespace, tokenComment, tokenWhitespace, tokenComment, tokenParam},
+			texts: []string{"a", "<>", "?", " ", "-- note", "\n", "/* block */", ":name"},
+		},
+		{
+			src:   "[odd name].`x`",
+			kinds: []tokenKind{tokenQuotedIdent, tokenPunct, tokenQuotedIdent},
+			texts: []string{"[odd name]", ".", "`x`"},
+		},
+	}
+	for _, tt := range tests {
+		var kinds []tokenKind
+		var texts []string
+		for _, tok := range lexSQL(tt.src) {
+			kinds = append(kinds, tok.Kind)
+			texts = append(texts, tok.Text)
+		}
+		if !reflect.DeepEqual(kinds, tt.kinds) || !reflect.DeepEqual(texts, tt.texts) {
+			t.Errorf("lexSQL(%q) = %v %q, want %v %q", tt.src, kinds, texts, tt.kinds, tt.texts)
+		}
+	}
+}
+
+func TestTokenClosed(t *testing.T) {
+	tests := []struct {
+		src    string
+		closed bool
+	}{
+		{"'it'", true},
+		{"'it''s'", true},
+		{"''", true},
+		{"'", false},
+		{"'it''", false},
+		{"'it", false},
+		{"x''", true},
+		{"x'", false},
+		{"x'00", false},
+		{`"a""b"`, true},
+		{`"a""`, false},
+		{"`a`", true},
+		{"[a]", true},
+		{"[a", false},
+		{"/* a */", true},
+		{"/*/", false},
+		{"/* a", false},
+		{"-- a", false},
+		{"abc", true},
+	}
+	for _, tt := range tests {
+		tokens := lexSQL(tt.src)
+		if len(tokens) != 1 {
+			t.Errorf("lexSQL(%q) returned %d tokens, want 1", tt.src, len(tokens))
+			continue
+		}
+		if got := tokens[0].Closed(); got != tt.closed {
+			t.Errorf("Closed() of %q = %v, want %v", tt.src, got, tt.closed)
+		}
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"SELECT 1; SELECT ';'", []string{"SELECT 1", "SELECT ';'"}},
+		{"SELECT 1;;  ", []string{"SELECT 1"}},
+		{"CREATE TRIGGER t AFTER INSERT ON a BEGIN UPDATE a SET x = CASE WHEN 1 THEN 2 END; DELETE FROM b; END; SELECT 2",
+			[]string{"CREATE TRIGGER t AFTER INSERT ON a BEGIN UPDATE a SET x = CASE WHEN 1 THEN 2 END; DELETE FROM b; END", "SELECT 2"}},
+	}
+	for _, tt := range tests {
+		if got := splitStatements(tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitStatements(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestStatementAt(t *testing.T) {
+	trigger := "CREATE TRIGGER t AFTER INSERT ON a BEGIN DELETE FROM b; UPDATE c SET x = 1; END"
+	tests := []struct {
+		query string
+		at    string
+		want  string
+	}{
+		{"SELECT 1; SELECT 2", "2", "SELECT 2"},
+		{"SELECT 1; SELECT 2", "1", "SELECT 1"},
+		{"SELECT 1;", ";", "SELECT 1"},
+		{trigger + "; SELECT 3", "UPDATE", trigger},
+		{trigger + "; SELECT 3", "3", "SELECT 3"},
+	}
+	for _, tt := range tests {
+		tokens := lexSQL(tt.query)
+		stmt := statementAt(tokens, strings.Index(tt.query, tt.at))
+		var got string
+		if len(stmt) > 0 {
+			got = tt.query[stmt[0].Start:stmt[len(stmt)-1].End]
+		}
+		if strings.TrimSpace(got) != tt.want {
+			t.Errorf("statementAt(%q) at %q = %q, want %q", tt.query, tt.at, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SwitchToStatusMsg opens the connection status view
+type SwitchToStatusMsg struct{}
+
+// statusEntry is one setting shown by the status view
+type statusEntry struct {
+	name  string
+	value string
+}
+
+// statusSection is a titled group of settings
+type statusSection struct {
+	title   string
+	entries []statusEntry
+}
+
+// StatusModel shows how the database was opened and the effective
+// connection settings, as reported by SQLite
+type StatusModel struct {
+	Shared       *SharedData
+	dataSource   string
+	readOnlyDB   bool
+	sections     []statusSection
+	err          error
+	keyMap       StatusKeyMap
+	help         help.Model
+	showFullHelp bool
+	focused      bool
+	id           int
+}
+
+// StatusOption is a functional option for configuring StatusModel
+type StatusOption func(*StatusModel)
+
+// WithStatusKeyMap sets the key map
+func WithStatusKeyMap(km StatusKeyMap) StatusOption {
+	return func(m *StatusModel) {
+		m.keyMap = km
+	}
+}
+
+func NewStatusModel(shared *SharedData, dataSource string, readOnlyDB bool, opts ...StatusOption) *StatusModel {
+	m := &StatusModel{
+		Shared:     shared,
+		dataSource: dataSource,
+		readOnlyDB: readOnlyDB,
+		keyMap:     shared.keyMaps().Status,
+		help:       shared.theme().newHelp(),
+		focused:    true,
+		id:         nextID(),
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	m.load()
+	return m
+}
+
+// ID returns the unique ID of the model
+func (m StatusModel) ID() int {
+	return m.id
+}
+
+// Focus sets the focus state
+func (m *StatusModel) Focus() {
+	m.focused = true
+}
+
+// Blur removes focus
+func (m *StatusModel) Blur() {
+	m.focused = false
+}
+
+// Focused returns the focus state
+func (m StatusModel) Focused() bool {
+	return m.focused
+}
+
+func (m *StatusModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *StatusModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if !m.focused {
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case ToggleHelpMsg:
+		m.showFullHelp = !m.showFullHelp
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keyMap.Back):
+			return m, func() tea.Msg { return NavigateBackMsg{} }
+		case key.Matches(msg, m.keyMap.Refresh):
+			m.load()
+		case key.Matches(msg, m.keyMap.CommandLine):
+			return m, func() tea.Msg { return OpenCommandLineMsg{} }
+		}
+	}
+	return m, nil
+}
+
+// load reads the settings on one connection. Every connection of the pool
+// is opened with the same pragmas, so any of them will do.
+func (m *StatusModel) load() {
+	ctx := context.Background()
+	conn, err := m.Shared.DB.Conn(ctx)
+	if err != nil {
+		m.err = err
+		return
+	}
+	defer conn.Close()
+	m.err = nil
+
+	value := func(query string) string {
+		var v any
+		if err := conn.QueryRowContext(ctx, query).Scan(&v); err != nil {
+			return fmt.Sprintf("error: %v", err)
+		}
+		return formatValue(v)
+	}
+	pragma := func(name string) string {
+		return value("PRAGMA " + name)
+	}
+	named := func(name string, names ...string) string {
+		v := pragma(name)
+		var i int
+		if _, err := fmt.Sscan(v, &i); err == nil && i >= 0 && i < len(names) {
+			return names[i]
+		}
+		return v
+	}
+
+	file := ""
+	conn.QueryRowContext(ctx, "SELECT file FROM pragma_database_list WHERE name = 'main'").Scan(&file)
+	size := "-"
+	if info, err := os.Stat(file); err == nil && file != "" {
+		size = formatSize(info.Size())
+	}
+	if file == "" {
+		file = "(in memory)"
+	}
+
+	readOnly := "off"
+	switch {
+	case m.readOnlyDB:
+		readOnly = "on (opened with mode=ro)"
+	case m.Shared.readOnly():
+		readOnly = "on"
+	}
+	safeMode := "off"
+	if m.Shared.safeMode() {
+		safeMode = "on"
+	}
+
+	cacheSize := pragma("cache_size")
+	if strings.HasPrefix(cacheSize, "-") {
+		cacheSize += fmt.Sprintf(" (%s KiB)", cacheSize[1:])
+	} else {
+		cacheSize += " pages"
+	}
+
+	m.sections = []statusSection{
+		{"Database", []statusEntry{
+			{"File", file},
+			{"Size", size},
+			{"Opened as", m.dataSource},
+			{"SQLite version", value("SELECT sqlite_version()")},
+			{"Read-only mode", readOnly},
+			{"Safe mode", safeMode},
+		}},
+	}
+	if attached := m.loadAttached(ctx, conn); len(attached) > 0 {
+		m.sections = append(m.sections, statusSection{"Attached", attached})
+	}
+	m.sections = append(m.sections,
+		statusSection{"Connection", []statusEntry{
+			{"busy_timeout", pragma("busy_timeout") + " ms"},
+			{"foreign_keys", named("foreign_keys", "off", "on")},
+			{"journal_mode", pragma("journal_mode")},
+			{"synchronous", named("synchronous", "off", "normal", "full", "extra")},
+			{"cache_size", cacheSize},
+			{"locking_mode", pragma("locking_mode")},
+			{"temp_store", named("temp_store", "default", "file", "memory")},
+		}},
+		statusSection{"Storage", []statusEntry{
+			{"page_size", pragma("page_size")},
+			{"page_count", pragma("page_count")},
+			{"freelist_count", pragma("freelist_count")},
+			{"encoding", pragma("encoding")},
+			{"auto_vacuum", named("auto_vacuum", "none", "full", "incremental")},
+			{"user_version", pragma("user_version")},
+		}},
+	)
+}
+
+// loadAttached lists the attached databases with their files
+func (m *StatusModel) loadAttached(ctx context.Context, conn *sql.Conn) []statusEntry {
+	rows, err := conn.QueryContext(ctx, "SELECT name, file FROM pragma_database_list WHERE name NOT IN ('main', 'temp') ORDER BY seq")
+	if err != nil {
+		return []statusEntry{{"error", err.Error()}}
+	}
+	defer rows.Close()
+
+	var entries []statusEntry
+	for rows.Next() {
+		var e statusEntry
+		if err := rows.Scan(&e.name, &e.value); err != nil {
+			break
+		}
+		if e.value == "" {
+			e.value = "(in memory)"
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// formatSize renders a byte count with a binary unit
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func (m *StatusModel) View() string {
+	theme := m.Shared.theme()
+	var content strings.Builder
+
+	content.WriteString(theme.Title.Render("Status"))
+	content.WriteString("\n\n")
+
+	if m.err != nil {
+		content.WriteString(theme.Error.Render(fmt.Sprintf("Error: %v", m.err)))
+		content.WriteString("\n\n")
+	}
+
+	width := 0
+	for _, section := range m.sections {
+		for _, e := range section.entries {
+			width = Max(width, len(e.name))
+		}
+	}
+	for _, section := range m.sections {
+		content.WriteString(theme.Normal.Bold(true).Render(section.title + ":"))
+		content.WriteString("\n")
+		for _, e := range section.entries {
+			line := fmt.Sprintf("  %-*s  %s", width, e.name, e.value)
+			content.WriteString(theme.Normal.Render(TruncateString(line, Max(20, m.Shared.Width-2))))
+			content.WriteString("\n")
+		}
+		content.WriteString("\n")
+	}
+
+	if m.showFullHelp {
+		content.WriteString(m.help.FullHelpView(m.keyMap.FullHelp()))
+	} else {
+		content.WriteString(m.help.ShortHelpView(m.keyMap.ShortHelp()))
+	}
+
+	return content.String()
+}
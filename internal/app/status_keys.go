@@ -0,0 +1,46 @@
+package app
+
+import "github.com/charmbracelet/bubbles/key"
+
+// StatusKeyMap defines keybindings for the connection status view
+type StatusKeyMap struct {
+	Refresh     key.Binding
+	Back        key.Binding
+	CommandLine key.Binding
+	ToggleHelp  key.Binding
+}
+
+// DefaultStatusKeyMap returns the default keybindings for the status view
+func DefaultStatusKeyMap() StatusKeyMap {
+	return StatusKeyMap{
+		Refresh: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "refresh"),
+		),
+		Back: key.NewBinding(
+			key.WithKeys("esc", "q"),
+			key.WithHelp("esc/q", "back"),
+		),
+		CommandLine: key.NewBinding(
+			key.WithKeys(":"),
+			key.WithHelp(":", "command"),
+		),
+		ToggleHelp: key.NewBinding(
+			key.WithKeys("ctrl+g"),
+			key.WithHelp("ctrl+g", "toggle help"),
+		),
+	}
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view
+func (k StatusKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Refresh, k.Back, k.ToggleHelp}
+}
+
+// FullHelp returns keybindings for the expanded help view
+func (k StatusKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Refresh, k.Back},
+		{k.ToggleHelp, k.CommandLine},
+	}
+}
@@ -16,12 +16,23 @@ type TableDataModel struct {
 	searchInput  textinput.Model
 	searching    bool
 	selectedRow  int
+	selectedCol  int
+	err          error
 	gPressed     bool
 	keyMap       TableDataKeyMap
 	help         help.Model
 	showFullHelp bool
 	focused      bool
 	id           int
+	// Mouse state: column widths, the line of the header and the rows
+	// drawn by the last View
+	widths     columnWidths
+	drag       columnDrag
+	clicks     clickTracker
+	headerLine int
+	lines      itemLines
+	// Rows marked for copying
+	marks rowMarks
 }
 
 // TableDataOption is a functional option for configuring TableDataModel
@@ -44,10 +55,12 @@ func NewTableDataModel(shared *SharedData, opts ...TableDataOption) *TableDataMo
 		Shared:      shared,
 		searchInput: searchInput,
 		selectedRow: 0,
-		keyMap:      DefaultTableDataKeyMap(),
-		help:        help.New(),
+		keyMap:      shared.keyMaps().TableData,
+		help:        shared.theme().newHelp(),
 		focused:     true,
 		id:          nextID(),
+		widths:      columnWidths{},
+		headerLine:  -1,
 	}
 
 	// Apply options
@@ -103,6 +116,9 @@ func (m *TableDataModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleSearchInput(msg)
 		}
 		return m.handleNavigation(msg)
+
+	case tea.MouseMsg:
+		return m, m.handleMouse(msg)
 	}
 
 	// Update search input for non-key messages when searching
@@ -152,16 +168,20 @@ func (m *TableDataModel) handleNavigation(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.filterData()
 			return m, nil
 		}
-		return m, func() tea.Msg { return SwitchToTableListClearMsg{} }
+		return m, func() tea.Msg { return NavigateBackMsg{} }
+
+	case key.Matches(msg, m.keyMap.CommandLine):
+		m.gPressed = false
+		return m, func() tea.Msg { return OpenCommandLineMsg{} }
 
 	case key.Matches(msg, m.keyMap.GoToStart):
 		if m.gPressed {
 			// Second g - go to absolute beginning (gg pattern like vim)
-			m.Shared.CurrentPage = 0
-			m.Shared.LoadTableData()
-			m.filterData()
-			m.selectedRow = 0
 			m.gPressed = false
+			if cmd := m.loadPage(0); cmd != nil {
+				return m, cmd
+			}
+			m.selectedRow = 0
 		} else {
 			// First g - wait for second g to complete gg sequence
 			m.gPressed = true
@@ -170,12 +190,12 @@ func (m *TableDataModel) handleNavigation(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case key.Matches(msg, m.keyMap.GoToEnd):
 		// Go to absolute end (G pattern like vim)
-		maxPage := (m.Shared.TotalRows - 1) / PageSize
-		m.Shared.CurrentPage = maxPage
-		m.Shared.LoadTableData()
-		m.filterData()
-		m.selectedRow = len(m.Shared.FilteredData) - 1
+		maxPage := (m.Shared.TotalRows - 1) / m.Shared.pageSize()
 		m.gPressed = false
+		if cmd := m.loadPage(maxPage); cmd != nil {
+			return m, cmd
+		}
+		m.selectedRow = len(m.Shared.FilteredData) - 1
 		return m, nil
 
 	case key.Matches(msg, m.keyMap.Enter):
@@ -197,53 +217,84 @@ func (m *TableDataModel) handleNavigation(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.gPressed = false
 		return m, func() tea.Msg { return SwitchToQueryMsg{} }
 
-	case key.Matches(msg, m.keyMap.Refresh):
+	case key.Matches(msg, m.keyMap.NextColumn):
 		m.gPressed = false
-		if err := m.Shared.LoadTableData(); err == nil {
-			m.filterData()
+		if m.selectedCol < len(m.Shared.Columns)-1 {
+			m.selectedCol++
 		}
 
-	case key.Matches(msg, m.keyMap.Up):
+	case key.Matches(msg, m.keyMap.PrevColumn):
 		m.gPressed = false
-		if m.selectedRow > 0 {
-			m.selectedRow--
-		} else if m.Shared.CurrentPage > 0 {
-			// At top of current page, go to previous page
-			m.Shared.CurrentPage--
-			m.Shared.LoadTableData()
-			m.filterData()
-			m.selectedRow = len(m.Shared.FilteredData) - 1 // Go to last row of previous page
+		if m.selectedCol > 0 {
+			m.selectedCol--
 		}
 
-	case key.Matches(msg, m.keyMap.Down):
+	case key.Matches(msg, m.keyMap.Follow):
 		m.gPressed = false
-		if m.selectedRow < len(m.Shared.FilteredData)-1 {
-			m.selectedRow++
-		} else {
-			// At bottom of current page, try to go to next page
-			maxPage := (m.Shared.TotalRows - 1) / PageSize
-			if m.Shared.CurrentPage < maxPage {
-				m.Shared.CurrentPage++
-				m.Shared.LoadTableData()
-				m.filterData()
-				m.selectedRow = 0 // Go to first row of next page
+		if len(m.Shared.FilteredData) > 0 {
+			return m, func() tea.Msg {
+				return FollowForeignKeyMsg{RowIndex: m.selectedRow, ColIndex: m.selectedCol}
 			}
 		}
 
+	case key.Matches(msg, m.keyMap.NavigateBack):
+		m.gPressed = false
+		return m, func() tea.Msg { return NavigateBackMsg{} }
+
+	case key.Matches(msg, m.keyMap.Mark):
+		m.gPressed = false
+		if m.selectedRow < len(m.Shared.FilteredData) {
+			m.marks.toggle(m.Shared.FilteredData[m.selectedRow])
+			return m, m.selectNext()
+		}
+
+	case key.Matches(msg, m.keyMap.Yank):
+		m.gPressed = false
+		return m, func() tea.Msg { return RunCommandMsg{Line: "yank cell"} }
+
+	case key.Matches(msg, m.keyMap.YankRow):
+		m.gPressed = false
+		if len(m.marks.rows) > 0 {
+			return m, func() tea.Msg { return RunCommandMsg{Line: "yank rows"} }
+		}
+		return m, func() tea.Msg { return RunCommandMsg{Line: "yank row"} }
+
+	case key.Matches(msg, m.keyMap.Export):
+		m.gPressed = false
+		return m, func() tea.Msg { return RunCommandMsg{Line: "export"} }
+
+	case key.Matches(msg, m.keyMap.Import):
+		m.gPressed = false
+		return m, func() tea.Msg { return RunCommandMsg{Line: "import"} }
+
+	case key.Matches(msg, m.keyMap.Refresh):
+		m.gPressed = false
+		return m, m.loadPage(m.Shared.CurrentPage)
+
+	case key.Matches(msg, m.keyMap.Up):
+		m.gPressed = false
+		return m, m.selectPrev()
+
+	case key.Matches(msg, m.keyMap.Down):
+		m.gPressed = false
+		return m, m.selectNext()
+
 	case key.Matches(msg, m.keyMap.Left):
 		m.gPressed = false
 		if m.Shared.CurrentPage > 0 {
-			m.Shared.CurrentPage--
-			m.Shared.LoadTableData()
+			if cmd := m.loadPage(m.Shared.CurrentPage - 1); cmd != nil {
+				return m, cmd
+			}
 			m.selectedRow = 0
 		}
 
 	case key.Matches(msg, m.keyMap.Right):
 		m.gPressed = false
-		maxPage := (m.Shared.TotalRows - 1) / PageSize
+		maxPage := (m.Shared.TotalRows - 1) / m.Shared.pageSize()
 		if m.Shared.CurrentPage < maxPage {
-			m.Shared.CurrentPage++
-			m.Shared.LoadTableData()
+			if cmd := m.loadPage(m.Shared.CurrentPage + 1); cmd != nil {
+				return m, cmd
+			}
 			m.selectedRow = 0
 		}
 
@@ -254,6 +305,151 @@ func (m *TableDataModel) handleNavigation(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// selectPrev moves the selection up a row, continuing on the previous page
+func (m *TableDataModel) selectPrev() tea.Cmd {
+	if m.selectedRow > 0 {
+		m.selectedRow--
+	} else if m.Shared.CurrentPage > 0 {
+		// At top of current page, go to previous page
+		if cmd := m.loadPage(m.Shared.CurrentPage - 1); cmd != nil {
+			return cmd
+		}
+		m.selectedRow = len(m.Shared.FilteredData) - 1 // Go to last row of previous page
+	}
+	return nil
+}
+
+// selectNext moves the selection down a row, continuing on the next page
+func (m *TableDataModel) selectNext() tea.Cmd {
+	if m.selectedRow < len(m.Shared.FilteredData)-1 {
+		m.selectedRow++
+	} else {
+		// At bottom of current page, try to go to next page
+		maxPage := (m.Shared.TotalRows - 1) / m.Shared.pageSize()
+		if m.Shared.CurrentPage < maxPage {
+			if cmd := m.loadPage(m.Shared.CurrentPage + 1); cmd != nil {
+				return cmd
+			}
+			m.selectedRow = 0 // Go to first row of next page
+		}
+	}
+	return nil
+}
+
+// handleMouse selects the row and cell under a click and opens the row on
+// a double click. Clicking a header sorts by its column and dragging a
+// header border resizes the column.
+func (m *TableDataModel) handleMouse(msg tea.MouseMsg) tea.Cmd {
+	if m.drag.update(msg, m.widths) {
+		return nil
+	}
+	switch wheelDelta(msg) {
+	case -1:
+		return m.selectPrev()
+	case 1:
+		return m.selectNext()
+	}
+	if !isClick(msg) {
+		return nil
+	}
+
+	col, border := m.widths.hit(msg.X, len(m.Shared.Columns))
+	if msg.Y == m.headerLine && col >= 0 {
+		if border {
+			m.drag = columnDrag{active: true, col: col}
+			return nil
+		}
+		return m.sortBy(col)
+	}
+
+	row := m.lines.at(msg.Y)
+	if row < 0 {
+		return nil
+	}
+	m.selectedRow = row
+	if col >= 0 && !border {
+		m.selectedCol = col
+	}
+	if m.clicks.click(row) {
+		return func() tea.Msg { return SwitchToRowDetailMsg{RowIndex: row} }
+	}
+	return nil
+}
+
+// sortBy cycles the order of a column through ascending, descending and
+// unsorted, reloading from the first page
+func (m *TableDataModel) sortBy(col int) tea.Cmd {
+	previous := m.Shared.Sort
+	name := m.Shared.Columns[col]
+	switch {
+	case previous == nil || previous.Column != name:
+		m.Shared.Sort = &SortOrder{Column: name}
+	case !previous.Desc:
+		m.Shared.Sort = &SortOrder{Column: name, Desc: true}
+	default:
+		m.Shared.Sort = nil
+	}
+	if cmd := m.loadPage(0); cmd != nil {
+		m.Shared.Sort = previous
+		return cmd
+	}
+	m.selectedRow = 0
+	return nil
+}
+
+// exportSource describes the table, its filter and the selected rows for
+// exporting
+func (m *TableDataModel) exportSource() exportSource {
+	source := exportSource{
+		name:   m.Shared.currentTableName(),
+		grid:   newGridData(m.Shared),
+		table:  m.Shared.currentTableName(),
+		filter: m.Shared.Filter,
+		sort:   m.Shared.Sort,
+	}
+	if len(m.marks.rows) > 0 {
+		source.selected = m.marks.rows
+	} else if m.selectedRow < len(m.Shared.FilteredData) {
+		source.selected = [][]string{m.Shared.FilteredData[m.selectedRow]}
+	}
+	return source
+}
+
+// loadPage loads a page of table data. On failure the current page is kept,
+// the error is shown in the view and reported as a notification.
+func (m *TableDataModel) loadPage(page int) tea.Cmd {
+	previous := m.Shared.CurrentPage
+	m.Shared.CurrentPage = page
+	if err := m.Shared.LoadTableData(); err != nil {
+		m.Shared.CurrentPage = previous
+		m.err = err
+		return NotifyError(fmt.Errorf("failed to load page %d: %w", page+1, err))
+	}
+	m.err = nil
+	m.filterData()
+	return nil
+}
+
+// renderSelectedRow renders the highlighted row with the selected cell
+// marked
+func (m *TableDataModel) renderSelectedRow(row []string) string {
+	theme := m.Shared.theme()
+	var before, after string
+	cell := ""
+	for j, value := range row {
+		text := fitCell(m.Shared.displayValue(j, value), m.widths.width(j))
+		switch {
+		case j < m.selectedCol:
+			before += text + " | "
+		case j == m.selectedCol:
+			cell = text
+		default:
+			after += " | " + text
+		}
+	}
+	return theme.Selected.Render(rowMarker(">", m.marks.has(row))+before) + theme.SelectedCell.Render(cell) + theme.Selected.Render(after)
+}
+
 func (m *TableDataModel) filterData() {
 	searchValue := m.searchInput.Value()
 	if searchValue == "" {
@@ -272,8 +468,8 @@ func (m *TableDataModel) filterData() {
 		for _, row := range m.Shared.TableData {
 			bestScore := 0
 			// Check each cell in the row and take the best score
-			for _, cell := range row {
-				score := m.fuzzyScore(strings.ToLower(cell), searchLower)
+			for j, cell := range row {
+				score := m.fuzzyScore(strings.ToLower(m.Shared.displayValue(j, cell)), searchLower)
 				if score > bestScore {
 					bestScore = score
 				}
@@ -370,14 +566,17 @@ func (m *TableDataModel) fuzzyScore(text, pattern string) int {
 }
 
 func (m *TableDataModel) View() string {
+	theme := m.Shared.theme()
 	var content strings.Builder
+	m.lines = m.lines[:0]
+	m.headerLine = -1
 
 	tableName := ""
 	if m.Shared.SelectedTable < len(m.Shared.FilteredTables) {
 		tableName = m.Shared.FilteredTables[m.Shared.SelectedTable]
 	}
 
-	content.WriteString(TitleStyle.Render(fmt.Sprintf("Table: %s", tableName)))
+	content.WriteString(theme.Title.Render(fmt.Sprintf("Table: %s", tableName)))
 	content.WriteString("\n")
 
 	if m.searching {
@@ -389,23 +588,32 @@ func (m *TableDataModel) View() string {
 		content.WriteString("\n")
 	}
 
+	if m.Shared.Filter != nil {
+		content.WriteString(fmt.Sprintf("\nRows where %s\n", m.Shared.Filter))
+	}
+
 	// Show pagination info
-	totalPages := (m.Shared.TotalRows-1)/PageSize + 1
-	content.WriteString(fmt.Sprintf("Page %d/%d (%d total rows)\n\n",
+	totalPages := (m.Shared.TotalRows-1)/m.Shared.pageSize() + 1
+	content.WriteString(fmt.Sprintf("Page %d/%d (%d total rows",
 		m.Shared.CurrentPage+1, totalPages, m.Shared.TotalRows))
+	if len(m.marks.rows) > 0 {
+		content.WriteString(fmt.Sprintf(", %d marked", len(m.marks.rows)))
+	}
+	content.WriteString(")\n\n")
+
+	if m.err != nil {
+		content.WriteString(theme.Error.Render(fmt.Sprintf("Error: %v", m.err)))
+		content.WriteString("\n")
+		content.WriteString(theme.Help.Render("Press r to retry"))
+		content.WriteString("\n\n")
+	}
 
 	if len(m.Shared.FilteredData) == 0 {
 		content.WriteString("No data found")
 	} else {
 		// Show column headers
-		headerRow := ""
-		for i, col := range m.Shared.Columns {
-			if i > 0 {
-				headerRow += " | "
-			}
-			headerRow += TruncateString(col, 15)
-		}
-		content.WriteString(TitleStyle.Render(headerRow))
+		m.headerLine = strings.Count(content.String(), "\n")
+		content.WriteString(theme.gridHeader(m.Shared.Columns, m.widths, m.headerMark))
 		content.WriteString("\n")
 
 		// Show data rows with scrolling within current page
@@ -424,18 +632,13 @@ func (m *TableDataModel) View() string {
 
 		for i := startIdx; i < endIdx; i++ {
 			row := m.Shared.FilteredData[i]
-			rowStr := ""
-			for j, cell := range row {
-				if j > 0 {
-					rowStr += " | "
-				}
-				rowStr += TruncateString(cell, 15)
-			}
-
+			m.lines.mark(&content, i, 1)
 			if i == m.selectedRow {
-				content.WriteString(SelectedStyle.Render("> " + rowStr))
+				content.WriteString(m.renderSelectedRow(row))
 			} else {
-				content.WriteString(NormalStyle.Render("  " + rowStr))
+				content.WriteString(theme.renderRow(i, row, m.widths, m.marks.has(row), m.Shared.displayValue, func(col int) (bool, bool) {
+					return m.Shared.ColumnInfoFor(col).PrimaryKey > 0, m.Shared.isModified(i, col)
+				}))
 			}
 			content.WriteString("\n")
 		}
@@ -443,7 +646,7 @@ func (m *TableDataModel) View() string {
 
 	content.WriteString("\n")
 	if m.searching {
-		content.WriteString(HelpStyle.Render("Type to search • enter/esc: finish search"))
+		content.WriteString(theme.Help.Render("Type to search • enter/esc: finish search"))
 	} else {
 		if m.showFullHelp {
 			content.WriteString(m.help.FullHelpView(m.keyMap.FullHelp()))
@@ -453,4 +656,20 @@ func (m *TableDataModel) View() string {
 	}
 
 	return content.String()
+}
+
+// headerMark marks foreign key columns and the column the rows are sorted by
+func (m *TableDataModel) headerMark(col int) string {
+	mark := ""
+	if m.Shared.ForeignKeyFor(col) != nil {
+		mark = "→"
+	}
+	if sort := m.Shared.Sort; sort != nil && sort.Column == m.Shared.Columns[col] {
+		if sort.Desc {
+			mark += "▼"
+		} else {
+			mark += "▲"
+		}
+	}
+	return mark
 }
\ No newline at end of file
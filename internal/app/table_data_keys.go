@@ -7,19 +7,31 @@ import "github.com/charmbracelet/bubbles/key"
 // - gg: go to start (requires two 'g' presses)
 // - G: go to end (single 'G' press)
 type TableDataKeyMap struct {
-	Up         key.Binding
-	Down       key.Binding
-	Left       key.Binding
-	Right      key.Binding
-	Enter      key.Binding
-	Search     key.Binding
-	Escape     key.Binding
-	Back       key.Binding
-	GoToStart  key.Binding
-	GoToEnd    key.Binding
-	Refresh    key.Binding
-	SQLMode    key.Binding
-	ToggleHelp key.Binding
+	Up          key.Binding
+	Down        key.Binding
+	Left        key.Binding
+	Right       key.Binding
+	Enter       key.Binding
+	Search      key.Binding
+	Escape      key.Binding
+	Back        key.Binding
+	GoToStart   key.Binding
+	GoToEnd     key.Binding
+	Refresh     key.Binding
+	SQLMode     key.Binding
+	CommandLine key.Binding
+	ToggleHelp  key.Binding
+	// Column cursor and foreign key navigation
+	NextColumn   key.Binding
+	PrevColumn   key.Binding
+	Follow       key.Binding
+	NavigateBack key.Binding
+	// Copying, exporting and importing
+	Mark    key.Binding
+	Yank    key.Binding
+	YankRow key.Binding
+	Export  key.Binding
+	Import  key.Binding
 }
 
 // DefaultTableDataKeyMap returns the default keybindings for table data
@@ -73,10 +85,50 @@ func DefaultTableDataKeyMap() TableDataKeyMap {
 			key.WithKeys("s"),
 			key.WithHelp("s", "SQL mode"),
 		),
+		CommandLine: key.NewBinding(
+			key.WithKeys(":"),
+			key.WithHelp(":", "command"),
+		),
 		ToggleHelp: key.NewBinding(
 			key.WithKeys("ctrl+g"),
 			key.WithHelp("ctrl+g", "toggle help"),
 		),
+		NextColumn: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "next column"),
+		),
+		PrevColumn: key.NewBinding(
+			key.WithKeys("shift+tab"),
+			key.WithHelp("shift+tab", "prev column"),
+		),
+		Follow: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "follow foreign key"),
+		),
+		NavigateBack: key.NewBinding(
+			key.WithKeys("backspace"),
+			key.WithHelp("backspace", "back to previous rows"),
+		),
+		Mark: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "mark row"),
+		),
+		Yank: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "copy cell"),
+		),
+		YankRow: key.NewBinding(
+			key.WithKeys("Y"),
+			key.WithHelp("Y", "copy row/marked rows"),
+		),
+		Export: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "export rows"),
+		),
+		Import: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", "import rows"),
+		),
 	}
 }
 
@@ -88,8 +140,10 @@ func (k TableDataKeyMap) ShortHelp() []key.Binding {
 // FullHelp returns keybindings for the expanded help view
 func (k TableDataKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Up, k.Down, k.Left, k.Right},
+		{k.Up, k.Down, k.Left, k.Right, k.NextColumn, k.PrevColumn},
+		{k.Follow, k.NavigateBack},
+		{k.Mark, k.Yank, k.YankRow, k.Export, k.Import},
 		{k.Enter, k.Search, k.Escape, k.Back},
-		{k.GoToStart, k.GoToEnd, k.Refresh, k.SQLMode, k.ToggleHelp},
+		{k.GoToStart, k.GoToEnd, k.Refresh, k.SQLMode, k.ToggleHelp, k.CommandLine},
 	}
 }
\ No newline at end of file
@@ -16,6 +16,7 @@ type TableListModel struct {
 	searchInput   textinput.Model
 	searching     bool
 	selectedTable int
+	err           error
 	currentPage   int
 	gPressed      bool
 	keyMap        TableListKeyMap
@@ -23,6 +24,8 @@ type TableListModel struct {
 	showFullHelp  bool
 	focused       bool
 	id            int
+	clicks        clickTracker
+	lines         itemLines // tables drawn by the last View
 }
 
 // TableListOption is a functional option for configuring TableListModel
@@ -46,8 +49,8 @@ func NewTableListModel(shared *SharedData, opts ...TableListOption) *TableListMo
 		searchInput:   searchInput,
 		selectedTable: 0,
 		currentPage:   0,
-		keyMap:        DefaultTableListKeyMap(),
-		help:          help.New(),
+		keyMap:        shared.keyMaps().TableList,
+		help:          shared.theme().newHelp(),
 		focused:       true,
 		id:            nextID(),
 	}
@@ -105,6 +108,9 @@ func (m *TableListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleSearchInput(msg)
 		}
 		return m.handleNavigation(msg)
+
+	case tea.MouseMsg:
+		return m, m.handleMouse(msg)
 	}
 
 	// Update search input for non-key messages when searching
@@ -165,6 +171,10 @@ func (m *TableListModel) handleNavigation(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.gPressed = false
 		return m, nil
 
+	case key.Matches(msg, m.keyMap.CommandLine):
+		m.gPressed = false
+		return m, func() tea.Msg { return OpenCommandLineMsg{} }
+
 	case key.Matches(msg, m.keyMap.GoToStart):
 		if m.gPressed {
 			// Second g - go to beginning (gg pattern like vim)
@@ -198,11 +208,22 @@ func (m *TableListModel) handleNavigation(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.gPressed = false
 		return m, func() tea.Msg { return SwitchToQueryMsg{} }
 
+	case key.Matches(msg, m.keyMap.Import):
+		m.gPressed = false
+		return m, func() tea.Msg { return RunCommandMsg{Line: "import"} }
+
+	case key.Matches(msg, m.keyMap.CreateTable):
+		m.gPressed = false
+		return m, func() tea.Msg { return RunCommandMsg{Line: "create"} }
+
 	case key.Matches(msg, m.keyMap.Refresh):
 		m.gPressed = false
-		if err := m.Shared.LoadTables(); err == nil {
-			m.filterTables()
+		if err := m.Shared.LoadTables(); err != nil {
+			m.err = err
+			return m, NotifyError(fmt.Errorf("failed to load tables: %w", err))
 		}
+		m.err = nil
+		m.filterTables()
 
 	case key.Matches(msg, m.keyMap.Up):
 		m.gPressed = false
@@ -243,6 +264,28 @@ func (m *TableListModel) handleNavigation(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleMouse selects the clicked table and opens it on a double click;
+// the wheel moves the selection
+func (m *TableListModel) handleMouse(msg tea.MouseMsg) tea.Cmd {
+	if delta := wheelDelta(msg); delta != 0 {
+		m.selectedTable = Max(0, Min(len(m.Shared.FilteredTables)-1, m.selectedTable+delta))
+		m.adjustPage()
+		return nil
+	}
+	if !isClick(msg) {
+		return nil
+	}
+	i := m.lines.at(msg.Y)
+	if i < 0 {
+		return nil
+	}
+	m.selectedTable = i
+	if m.clicks.click(i) {
+		return func() tea.Msg { return SwitchToTableDataMsg{TableIndex: i} }
+	}
+	return nil
+}
+
 func (m *TableListModel) filterTables() {
 	searchValue := m.searchInput.Value()
 	if searchValue == "" {
@@ -269,6 +312,17 @@ func (m *TableListModel) filterTables() {
 		sort.Slice(matches, func(i, j int) bool {
 			return matches[i].score > matches[j].score
 		})
+
+		// Keep the tables of each schema together
+		rank := map[string]int{}
+		for i, schema := range m.Shared.Schemas {
+			rank[schema] = i
+		}
+		sort.SliceStable(matches, func(i, j int) bool {
+			si, _ := splitTable(matches[i].name)
+			sj, _ := splitTable(matches[j].name)
+			return rank[si] < rank[sj]
+		})
 		
 		// Extract sorted table names
 		m.Shared.FilteredTables = make([]string, len(matches))
@@ -356,18 +410,53 @@ func (m *TableListModel) getVisibleCount() int {
 	if m.searching {
 		reservedLines += 2
 	}
+	if m.grouped() {
+		// A header per schema
+		reservedLines += len(m.Shared.Schemas)
+	}
 	return Max(1, m.Shared.Height-reservedLines)
 }
 
+// grouped reports whether tables are listed under their schema, which is
+// when databases are attached
+func (m *TableListModel) grouped() bool {
+	return len(m.Shared.Schemas) > 1
+}
+
 func (m *TableListModel) adjustPage() {
 	visibleCount := m.getVisibleCount()
 	m.currentPage = m.selectedTable / visibleCount
 }
 
+// welcomeView is shown instead of the list when the database has no
+// tables yet
+func (m *TableListModel) welcomeView() string {
+	theme := m.Shared.theme()
+	var content strings.Builder
+	content.WriteString(theme.Normal.Render("This database is empty. To get started:"))
+	content.WriteString("\n\n")
+	shortcuts := []struct {
+		binding key.Binding
+		action  string
+	}{
+		{m.keyMap.CreateTable, "create the first table"},
+		{m.keyMap.Import, "import a CSV, TSV or JSON file into a new table"},
+		{m.keyMap.SQLMode, "write SQL"},
+	}
+	for _, s := range shortcuts {
+		if s.binding.Enabled() {
+			content.WriteString(fmt.Sprintf("  %s  %s\n", theme.Selected.Render(s.binding.Help().Key), theme.Normal.Render(s.action)))
+		}
+	}
+	return content.String()
+}
+
 func (m *TableListModel) View() string {
+	theme := m.Shared.theme()
 	var content strings.Builder
+	m.lines = m.lines[:0]
 
-	content.WriteString(TitleStyle.Render("SQLite TUI - Tables"))
+	content.WriteString(theme.Title.Render("SQLite TUI - Tables"))
 	content.WriteString("\n")
 
 	if m.searching {
@@ -380,23 +469,39 @@ func (m *TableListModel) View() string {
 	}
 	content.WriteString("\n")
 
+	if m.err != nil {
+		content.WriteString(theme.Error.Render(fmt.Sprintf("Error: %v", m.err)))
+		content.WriteString("\n\n")
+	}
+
 	if len(m.Shared.FilteredTables) == 0 {
 		if m.searchInput.Value() != "" {
 			content.WriteString("No tables match your search")
 		} else {
-			content.WriteString("No tables found in database")
+			content.WriteString(m.welcomeView())
 		}
 	} else {
 		visibleCount := m.getVisibleCount()
 		startIdx := m.currentPage * visibleCount
 		endIdx := Min(startIdx+visibleCount, len(m.Shared.FilteredTables))
 
+		schema := ""
 		for i := startIdx; i < endIdx; i++ {
 			table := m.Shared.FilteredTables[i]
+			if m.grouped() {
+				var tableSchema string
+				tableSchema, table = splitTable(table)
+				if tableSchema != schema {
+					schema = tableSchema
+					content.WriteString(theme.Normal.Bold(true).Render(schema + ":"))
+					content.WriteString("\n")
+				}
+			}
+			m.lines.mark(&content, i, 1)
 			if i == m.selectedTable {
-				content.WriteString(SelectedStyle.Render(fmt.Sprintf("> %s", table)))
+				content.WriteString(theme.Selected.Render(fmt.Sprintf("> %s", table)))
 			} else {
-				content.WriteString(NormalStyle.Render(fmt.Sprintf("  %s", table)))
+				content.WriteString(theme.Normal.Render(fmt.Sprintf("  %s", table)))
 			}
 			content.WriteString("\n")
 		}
@@ -409,7 +514,7 @@ func (m *TableListModel) View() string {
 
 	content.WriteString("\n")
 	if m.searching {
-		content.WriteString(HelpStyle.Render("Type to search • enter/esc: finish search"))
+		content.WriteString(theme.Help.Render("Type to search • enter/esc: finish search"))
 	} else {
 		if m.showFullHelp {
 			content.WriteString(m.help.FullHelpView(m.keyMap.FullHelp()))
@@ -7,18 +7,21 @@ import "github.com/charmbracelet/bubbles/key"
 // - gg: go to start (requires two 'g' presses)
 // - G: go to end (single 'G' press)
 type TableListKeyMap struct {
-	Up         key.Binding
-	Down       key.Binding
-	Left       key.Binding
-	Right      key.Binding
-	Enter      key.Binding
-	Search     key.Binding
-	Escape     key.Binding
-	GoToStart  key.Binding
-	GoToEnd    key.Binding
-	Refresh    key.Binding
-	SQLMode    key.Binding
-	ToggleHelp key.Binding
+	Up          key.Binding
+	Down        key.Binding
+	Left        key.Binding
+	Right       key.Binding
+	Enter       key.Binding
+	Search      key.Binding
+	Escape      key.Binding
+	GoToStart   key.Binding
+	GoToEnd     key.Binding
+	Refresh     key.Binding
+	SQLMode     key.Binding
+	Import      key.Binding
+	CreateTable key.Binding
+	CommandLine key.Binding
+	ToggleHelp  key.Binding
 }
 
 // DefaultTableListKeyMap returns the default keybindings for table list
@@ -68,6 +71,18 @@ func DefaultTableListKeyMap() TableListKeyMap {
 			key.WithKeys("s"),
 			key.WithHelp("s", "SQL mode"),
 		),
+		Import: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", "import file"),
+		),
+		CreateTable: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "new table"),
+		),
+		CommandLine: key.NewBinding(
+			key.WithKeys(":"),
+			key.WithHelp(":", "command"),
+		),
 		ToggleHelp: key.NewBinding(
 			key.WithKeys("ctrl+g"),
 			key.WithHelp("ctrl+g", "toggle help"),
@@ -85,6 +100,6 @@ func (k TableListKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.Left, k.Right},
 		{k.Enter, k.Search, k.Escape, k.Refresh},
-		{k.GoToStart, k.GoToEnd, k.SQLMode, k.ToggleHelp},
+		{k.GoToStart, k.GoToEnd, k.SQLMode, k.Import, k.CreateTable, k.ToggleHelp, k.CommandLine},
 	}
 }
\ No newline at end of file
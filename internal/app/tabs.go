@@ -0,0 +1,246 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// tab is an open workspace with its own view, shared data and history.
+// Model embeds the active tab.
+type tab struct {
+	title       string // set when the tab is renamed
+	currentView tea.Model
+	// Navigation history, most recent last
+	history []navEntry
+	forward []navEntry
+	// The query view is kept so SQL mode reopens with its state
+	lastQueryView *QueryModel
+}
+
+// Title returns the tab label: the name it was given, otherwise a
+// description of its current view
+func (t *tab) Title() string {
+	if t.title != "" {
+		return t.title
+	}
+	switch v := t.currentView.(type) {
+	case *TableListModel:
+		return "Tables"
+	case *QueryModel:
+		return "Query"
+	case *MessageLogModel:
+		return "Messages"
+	case *ExportModel:
+		return "Export"
+	case *ImportModel:
+		return "Import"
+	case *StatusModel:
+		return "Status"
+	case *TableDataModel:
+		return v.Shared.currentTableName()
+	case *RowDetailModel:
+		if v.FromQuery {
+			return "Query"
+		}
+		return v.Shared.currentTableName()
+	case *EditCellModel:
+		return v.Shared.currentTableName()
+	}
+	return "Tab"
+}
+
+// currentTableName returns the name of the selected table, if any
+func (s *SharedData) currentTableName() string {
+	if s.IsQueryResult || s.SelectedTable >= len(s.FilteredTables) {
+		return "Query"
+	}
+	return s.FilteredTables[s.SelectedTable]
+}
+
+// newTab opens a tab on the table list with its own shared data
+func (m *Model) newTab() tea.Cmd {
+	shared := m.newSharedData()
+	if err := shared.LoadTables(); err != nil {
+		return NotifyError(fmt.Errorf("failed to open tab: %w", err))
+	}
+	t := &tab{currentView: NewTableListModel(shared)}
+	m.tabs = append(m.tabs, t)
+	m.selectTab(len(m.tabs) - 1)
+	return t.currentView.Init()
+}
+
+// closeTab closes the active tab; the last tab cannot be closed
+func (m *Model) closeTab() tea.Cmd {
+	if len(m.tabs) == 1 {
+		return Notify(SeverityInfo, "cannot close the last tab")
+	}
+	i := m.activeTab
+	m.tabs = append(m.tabs[:i], m.tabs[i+1:]...)
+	m.selectTab(Min(i, len(m.tabs)-1))
+	return nil
+}
+
+// selectTab makes the tab at index i active
+func (m *Model) selectTab(i int) {
+	if i < 0 || i >= len(m.tabs) {
+		return
+	}
+	m.renaming = false
+	m.activeTab = i
+	m.tab = m.tabs[i]
+	m.resize()
+}
+
+// resize gives the current view the terminal size minus the tab bar, or
+// its pane when the layout is split
+func (m *Model) resize() {
+	shared := m.getSharedData()
+	if m.split {
+		sidebar, main, _, height := m.paneSizes()
+		shared.Width, shared.Height = main-2, height-2
+		m.sidebar.Shared.Width, m.sidebar.Shared.Height = sidebar-2, height-2
+		return
+	}
+	shared.Width = m.width
+	shared.Height = m.height - m.tabBarHeight()
+}
+
+// startRename shows the rename input for the active tab
+func (m *Model) startRename() tea.Cmd {
+	m.renaming = true
+	m.renameInput.SetValue(m.tab.title)
+	m.renameInput.CursorEnd()
+	return m.renameInput.Focus()
+}
+
+// handleRename updates the rename input. Enter applies the name, an empty
+// name restores the automatic title and esc cancels. The quit key still
+// quits.
+func (m *Model) handleRename(msg tea.KeyMsg) tea.Cmd {
+	if key.Matches(msg, m.keyMap.Quit) {
+		return tea.Quit
+	}
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.tab.title = strings.TrimSpace(m.renameInput.Value())
+		m.renaming = false
+		m.renameInput.Blur()
+		return nil
+	case tea.KeyEsc:
+		m.renaming = false
+		m.renameInput.Blur()
+		return nil
+	}
+	var cmd tea.Cmd
+	m.renameInput, cmd = m.renameInput.Update(msg)
+	return cmd
+}
+
+// tabIndexFor returns the tab selected by an alt+1..alt+9 key
+func tabIndexFor(msg tea.KeyMsg) int {
+	s := msg.String()
+	if len(s) != len("alt+1") || !strings.HasPrefix(s, "alt+") {
+		return -1
+	}
+	return int(s[4] - '1')
+}
+
+func newRenameInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "Tab name"
+	input.CharLimit = 30
+	input.Width = 20
+	input.Prompt = ""
+	return input
+}
+
+// tabBarView renders the tab bar with the mode badges on the right, or
+// nothing when only one tab is open and no badge is shown
+func (m *Model) tabBarView() string {
+	tabs := ""
+	if len(m.tabs) > 1 || m.renaming {
+		tabs = lipgloss.JoinHorizontal(lipgloss.Top, m.tabLabels()...)
+	}
+	badges := m.badgesView()
+	if badges == "" {
+		return tabs
+	}
+	gap := Max(1, m.width-lipgloss.Width(tabs)-lipgloss.Width(badges))
+	return tabs + strings.Repeat(" ", gap) + badges
+}
+
+// tabBarHeight returns the number of lines taken by the tab bar
+func (m *Model) tabBarHeight() int {
+	if m.tabBarView() == "" {
+		return 0
+	}
+	return 1
+}
+
+// badgesView renders a badge for each active mode that guards the database
+func (m *Model) badgesView() string {
+	var badges []string
+	if m.settings.ReadOnly {
+		badges = append(badges, m.settings.Theme.Badge.Render("READ-ONLY"))
+	}
+	if m.settings.SafeMode && !m.settings.ReadOnly {
+		badges = append(badges, m.settings.Theme.Badge.Render("SAFE"))
+	}
+	return strings.Join(badges, " ")
+}
+
+// tabLabels renders the label of each tab
+func (m *Model) tabLabels() []string {
+	var tabs []string
+	for i, t := range m.tabs {
+		label := fmt.Sprintf("%d %s", i+1, TruncateString(t.Title(), 20))
+		if i == m.activeTab {
+			if m.renaming {
+				label = fmt.Sprintf("%d %s", i+1, m.renameInput.View())
+			}
+			tabs = append(tabs, m.settings.Theme.ActiveTab.Render(label))
+		} else {
+			tabs = append(tabs, m.settings.Theme.Tab.Render(label))
+		}
+	}
+	return tabs
+}
+
+// tabAt returns the tab drawn at column x of the tab bar, or -1
+func (m *Model) tabAt(x int) int {
+	start := 0
+	for i, label := range m.tabLabels() {
+		start += lipgloss.Width(label)
+		if x < start {
+			return i
+		}
+	}
+	return -1
+}
+
+// handleTabKeys runs the tab key bindings, reporting whether msg was one
+func (m *Model) handleTabKeys(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, m.keyMap.NewTab):
+		return m.newTab(), true
+	case key.Matches(msg, m.keyMap.CloseTab):
+		return m.closeTab(), true
+	case key.Matches(msg, m.keyMap.NextTab):
+		m.selectTab((m.activeTab + 1) % len(m.tabs))
+		return nil, true
+	case key.Matches(msg, m.keyMap.PrevTab):
+		m.selectTab((m.activeTab - 1 + len(m.tabs)) % len(m.tabs))
+		return nil, true
+	case key.Matches(msg, m.keyMap.RenameTab):
+		return m.startRename(), true
+	case key.Matches(msg, m.keyMap.GoToTab):
+		m.selectTab(tabIndexFor(msg))
+		return nil, true
+	}
+	return nil, false
+}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRenameKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      tea.KeyMsg
+		quits    bool
+		renaming bool
+	}{
+		{"quit", tea.KeyMsg{Type: tea.KeyCtrlC}, true, true},
+		{"text", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}, false, true},
+		{"cancel", tea.KeyMsg{Type: tea.KeyEsc}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := InitialModel(openTestDatabase(t, ""))
+			m.startRename()
+			_, cmd := m.Update(tt.key)
+			if tt.quits && (cmd == nil || cmd() != tea.QuitMsg{}) {
+				t.Error("the key does not quit")
+			}
+			if m.renaming != tt.renaming {
+				t.Errorf("renaming is %v, want %v", m.renaming, tt.renaming)
+			}
+		})
+	}
+}
@@ -0,0 +1,306 @@
+package app
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme holds the styles used to render every view
+type Theme struct {
+	Name string
+
+	Title        lipgloss.Style
+	Selected     lipgloss.Style
+	Normal       lipgloss.Style
+	SelectedCell lipgloss.Style
+	Error        lipgloss.Style
+	Help         lipgloss.Style
+	HelpKey      lipgloss.Style
+
+	// Split layout, tabs and the mode badges next to them
+	Pane        lipgloss.Style
+	FocusedPane lipgloss.Style
+	Tab         lipgloss.Style
+	ActiveTab   lipgloss.Style
+	Badge       lipgloss.Style
+
+	// Notifications
+	InfoToast    lipgloss.Style
+	WarningToast lipgloss.Style
+	ErrorToast   lipgloss.Style
+
+	// Grid cells
+	Null     lipgloss.Style
+	Number   lipgloss.Style
+	Key      lipgloss.Style // primary key values
+	Modified lipgloss.Style // cells edited in this session
+	Zebra    lipgloss.Style // every other row
+	Marked   lipgloss.Style // rows marked for copying
+
+	// SQL editor
+	SQLKeyword    lipgloss.Style
+	SQLString     lipgloss.Style
+	SQLNumber     lipgloss.Style
+	SQLComment    lipgloss.Style
+	SQLIdentifier lipgloss.Style
+	LineNumber    lipgloss.Style
+	MatchedParen  lipgloss.Style
+	ErrorMark     lipgloss.Style
+	Cursor        lipgloss.Style
+}
+
+// Built-in theme names; ThemeAuto picks dark or light from the terminal
+const (
+	ThemeAuto         = "auto"
+	ThemeDark         = "dark"
+	ThemeLight        = "light"
+	ThemeHighContrast = "high-contrast"
+)
+
+// fallbackTheme is used by views created without settings
+var fallbackTheme = DarkTheme()
+
+// DarkTheme returns the theme for dark terminal backgrounds
+func DarkTheme() *Theme {
+	s := lipgloss.NewStyle
+	return &Theme{
+		Name:         ThemeDark,
+		Title:        s().Bold(true).Foreground(lipgloss.Color("#FAFAFA")).Background(lipgloss.Color("#7D56F4")).Padding(0, 1),
+		Selected:     s().Bold(true).Foreground(lipgloss.Color("#FAFAFA")).Background(lipgloss.Color("#F25D94")),
+		Normal:       s().Foreground(lipgloss.Color("#FAFAFA")),
+		SelectedCell: s().Bold(true).Underline(true).Foreground(lipgloss.Color("#1A1A1A")).Background(lipgloss.Color("#FAFAFA")),
+		Error:        s().Foreground(lipgloss.Color("#FF0000")).Bold(true),
+		Help:         s().Foreground(lipgloss.Color("#626262")),
+		HelpKey:      s().Foreground(lipgloss.Color("#909090")),
+
+		Pane:        s().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#626262")),
+		FocusedPane: s().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#7D56F4")),
+		Tab:         s().Foreground(lipgloss.Color("#A0A0A0")).Padding(0, 1),
+		ActiveTab:   s().Bold(true).Foreground(lipgloss.Color("#FAFAFA")).Background(lipgloss.Color("#7D56F4")).Padding(0, 1),
+		Badge:       s().Bold(true).Foreground(lipgloss.Color("#1A1A1A")).Background(lipgloss.Color("#F2C94C")).Padding(0, 1),
+
+		InfoToast:    s().Foreground(lipgloss.Color("#FAFAFA")).Background(lipgloss.Color("#3C7DD9")).Padding(0, 1),
+		WarningToast: s().Foreground(lipgloss.Color("#1A1A1A")).Background(lipgloss.Color("#F2C94C")).Padding(0, 1),
+		ErrorToast:   s().Foreground(lipgloss.Color("#FAFAFA")).Background(lipgloss.Color("#D7263D")).Bold(true).Padding(0, 1),
+
+		Null:     s().Foreground(lipgloss.Color("#767676")).Italic(true),
+		Number:   s().Foreground(lipgloss.Color("#F78C6C")),
+		Key:      s().Foreground(lipgloss.Color("#82AAFF")).Bold(true),
+		Modified: s().Foreground(lipgloss.Color("#C3E88D")).Underline(true),
+		Zebra:    s().Foreground(lipgloss.Color("#FAFAFA")).Background(lipgloss.Color("#262626")),
+		Marked:   s().Foreground(lipgloss.Color("#FAFAFA")).Background(lipgloss.Color("#3A2E5C")),
+
+		SQLKeyword:    s().Foreground(lipgloss.Color("#C792EA")).Bold(true),
+		SQLString:     s().Foreground(lipgloss.Color("#C3E88D")),
+		SQLNumber:     s().Foreground(lipgloss.Color("#F78C6C")),
+		SQLComment:    s().Foreground(lipgloss.Color("#626262")).Italic(true),
+		SQLIdentifier: s().Foreground(lipgloss.Color("#82AAFF")),
+		LineNumber:    s().Foreground(lipgloss.Color("#4E4E4E")),
+		MatchedParen:  s().Bold(true).Foreground(lipgloss.Color("#FAFAFA")).Background(lipgloss.Color("#4E4E4E")),
+		ErrorMark:     s().Foreground(lipgloss.Color("#FF0000")).Underline(true).Bold(true),
+		Cursor:        s().Reverse(true),
+	}
+}
+
+// LightTheme returns the theme for light terminal backgrounds
+func LightTheme() *Theme {
+	s := lipgloss.NewStyle
+	return &Theme{
+		Name:         ThemeLight,
+		Title:        s().Bold(true).Foreground(lipgloss.Color("#FFFFFF")).Background(lipgloss.Color("#5A3FC0")).Padding(0, 1),
+		Selected:     s().Bold(true).Foreground(lipgloss.Color("#FFFFFF")).Background(lipgloss.Color("#C2185B")),
+		Normal:       s().Foreground(lipgloss.Color("#1A1A1A")),
+		SelectedCell: s().Bold(true).Underline(true).Foreground(lipgloss.Color("#FFFFFF")).Background(lipgloss.Color("#1A1A1A")),
+		Error:        s().Foreground(lipgloss.Color("#C00000")).Bold(true),
+		Help:         s().Foreground(lipgloss.Color("#6E6E6E")),
+		HelpKey:      s().Foreground(lipgloss.Color("#3A3A3A")),
+
+		Pane:        s().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#A0A0A0")),
+		FocusedPane: s().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#5A3FC0")),
+		Tab:         s().Foreground(lipgloss.Color("#6E6E6E")).Padding(0, 1),
+		ActiveTab:   s().Bold(true).Foreground(lipgloss.Color("#FFFFFF")).Background(lipgloss.Color("#5A3FC0")).Padding(0, 1),
+		Badge:       s().Bold(true).Foreground(lipgloss.Color("#1A1A1A")).Background(lipgloss.Color("#F2C94C")).Padding(0, 1),
+
+		InfoToast:    s().Foreground(lipgloss.Color("#FFFFFF")).Background(lipgloss.Color("#1F5FAF")).Padding(0, 1),
+		WarningToast: s().Foreground(lipgloss.Color("#1A1A1A")).Background(lipgloss.Color("#F2C94C")).Padding(0, 1),
+		ErrorToast:   s().Foreground(lipgloss.Color("#FFFFFF")).Background(lipgloss.Color("#B00020")).Bold(true).Padding(0, 1),
+
+		Null:     s().Foreground(lipgloss.Color("#8A8A8A")).Italic(true),
+		Number:   s().Foreground(lipgloss.Color("#B34700")),
+		Key:      s().Foreground(lipgloss.Color("#1565C0")).Bold(true),
+		Modified: s().Foreground(lipgloss.Color("#2E7D32")).Underline(true),
+		Zebra:    s().Foreground(lipgloss.Color("#1A1A1A")).Background(lipgloss.Color("#ECECEC")),
+		Marked:   s().Foreground(lipgloss.Color("#1A1A1A")).Background(lipgloss.Color("#D9CFF5")),
+
+		SQLKeyword:    s().Foreground(lipgloss.Color("#7B1FA2")).Bold(true),
+		SQLString:     s().Foreground(lipgloss.Color("#2E7D32")),
+		SQLNumber:     s().Foreground(lipgloss.Color("#B34700")),
+		SQLComment:    s().Foreground(lipgloss.Color("#8A8A8A")).Italic(true),
+		SQLIdentifier: s().Foreground(lipgloss.Color("#1565C0")),
+		LineNumber:    s().Foreground(lipgloss.Color("#A0A0A0")),
+		MatchedParen:  s().Bold(true).Foreground(lipgloss.Color("#1A1A1A")).Background(lipgloss.Color("#D0D0D0")),
+		ErrorMark:     s().Foreground(lipgloss.Color("#C00000")).Underline(true).Bold(true),
+		Cursor:        s().Reverse(true),
+	}
+}
+
+// HighContrastTheme returns a theme using only the 16 basic terminal
+// colors, with bold and underline marking state
+func HighContrastTheme() *Theme {
+	s := lipgloss.NewStyle
+	return &Theme{
+		Name:         ThemeHighContrast,
+		Title:        s().Bold(true).Foreground(lipgloss.Color("0")).Background(lipgloss.Color("11")).Padding(0, 1),
+		Selected:     s().Bold(true).Foreground(lipgloss.Color("0")).Background(lipgloss.Color("14")),
+		Normal:       s().Foreground(lipgloss.Color("15")),
+		SelectedCell: s().Bold(true).Underline(true).Foreground(lipgloss.Color("0")).Background(lipgloss.Color("15")),
+		Error:        s().Foreground(lipgloss.Color("9")).Bold(true),
+		Help:         s().Foreground(lipgloss.Color("7")),
+		HelpKey:      s().Foreground(lipgloss.Color("15")).Bold(true),
+
+		Pane:        s().Border(lipgloss.NormalBorder()).BorderForeground(lipgloss.Color("7")),
+		FocusedPane: s().Border(lipgloss.ThickBorder()).BorderForeground(lipgloss.Color("11")),
+		Tab:         s().Foreground(lipgloss.Color("7")).Padding(0, 1),
+		ActiveTab:   s().Bold(true).Foreground(lipgloss.Color("0")).Background(lipgloss.Color("11")).Padding(0, 1),
+		Badge:       s().Bold(true).Foreground(lipgloss.Color("15")).Background(lipgloss.Color("1")).Padding(0, 1),
+
+		InfoToast:    s().Foreground(lipgloss.Color("0")).Background(lipgloss.Color("14")).Padding(0, 1),
+		WarningToast: s().Foreground(lipgloss.Color("0")).Background(lipgloss.Color("11")).Padding(0, 1),
+		ErrorToast:   s().Foreground(lipgloss.Color("15")).Background(lipgloss.Color("1")).Bold(true).Padding(0, 1),
+
+		Null:     s().Foreground(lipgloss.Color("13")).Italic(true),
+		Number:   s().Foreground(lipgloss.Color("11")),
+		Key:      s().Foreground(lipgloss.Color("14")).Bold(true),
+		Modified: s().Foreground(lipgloss.Color("10")).Bold(true).Underline(true),
+		Zebra:    s().Foreground(lipgloss.Color("15")).Background(lipgloss.Color("8")),
+		Marked:   s().Bold(true).Foreground(lipgloss.Color("0")).Background(lipgloss.Color("14")),
+
+		SQLKeyword:    s().Foreground(lipgloss.Color("14")).Bold(true),
+		SQLString:     s().Foreground(lipgloss.Color("10")),
+		SQLNumber:     s().Foreground(lipgloss.Color("11")),
+		SQLComment:    s().Foreground(lipgloss.Color("7")).Italic(true),
+		SQLIdentifier: s().Foreground(lipgloss.Color("15")),
+		LineNumber:    s().Foreground(lipgloss.Color("7")),
+		MatchedParen:  s().Bold(true).Foreground(lipgloss.Color("0")).Background(lipgloss.Color("15")),
+		ErrorMark:     s().Foreground(lipgloss.Color("9")).Underline(true).Bold(true),
+		Cursor:        s().Reverse(true),
+	}
+}
+
+// builtinThemes maps the built-in theme names to their constructors
+var builtinThemes = map[string]func() *Theme{
+	ThemeDark:         DarkTheme,
+	ThemeLight:        LightTheme,
+	ThemeHighContrast: HighContrastTheme,
+}
+
+// BuiltinTheme returns a built-in theme by name. ThemeAuto asks the
+// terminal for its background color, so it must be resolved before the
+// program starts.
+func BuiltinTheme(name string) (*Theme, bool) {
+	if name == ThemeAuto {
+		if lipgloss.HasDarkBackground() {
+			return DarkTheme(), true
+		}
+		return LightTheme(), true
+	}
+	newTheme, ok := builtinThemes[name]
+	if !ok {
+		return nil, false
+	}
+	return newTheme(), true
+}
+
+// BuiltinThemeNames returns the names accepted by BuiltinTheme
+func BuiltinThemeNames() []string {
+	names := []string{ThemeAuto}
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// theme returns the theme of the settings
+func (s *SharedData) theme() *Theme {
+	if s.Settings == nil || s.Settings.Theme == nil {
+		return fallbackTheme
+	}
+	return s.Settings.Theme
+}
+
+// newHelp returns a help model using the theme
+func (t *Theme) newHelp() help.Model {
+	h := help.New()
+	h.Styles.ShortKey = t.HelpKey
+	h.Styles.FullKey = t.HelpKey
+	h.Styles.ShortDesc = t.Help
+	h.Styles.FullDesc = t.Help
+	h.Styles.ShortSeparator = t.Help
+	h.Styles.FullSeparator = t.Help
+	h.Styles.Ellipsis = t.Help
+	return h
+}
+
+var numberPattern = regexp.MustCompile(`^[-+]?(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?$`)
+
+// rowMarker returns the gutter of a grid row: the cursor, if any, followed
+// by a star for marked rows
+func rowMarker(cursor string, marked bool) string {
+	if marked {
+		return cursor + "*"
+	}
+	return cursor + " "
+}
+
+// cellStyle returns the style for a cell value, if it has one
+func (t *Theme) cellStyle(value string, key, modified bool) (lipgloss.Style, bool) {
+	switch {
+	case modified:
+		return t.Modified, true
+	case value == NullValue:
+		return t.Null, true
+	case key:
+		return t.Key, true
+	case numberPattern.MatchString(value):
+		return t.Number, true
+	}
+	return lipgloss.Style{}, false
+}
+
+// renderRow renders an unselected grid row with styled cells, striping
+// every other row. display returns the text shown for a cell. flags
+// reports whether a column is part of the primary key and whether its cell
+// was modified; it may be nil.
+func (t *Theme) renderRow(index int, row []string, widths columnWidths, marked bool, display func(col int, v string) string, flags func(col int) (key, modified bool)) string {
+	base := t.Normal
+	if index%2 == 1 {
+		base = t.Zebra
+	}
+	if marked {
+		base = t.Marked
+	}
+
+	var b strings.Builder
+	b.WriteString(base.Render(rowMarker(" ", marked)))
+	for j, cell := range row {
+		if j > 0 {
+			b.WriteString(base.Render(" | "))
+		}
+		text := fitCell(display(j, cell), widths.width(j))
+		var key, modified bool
+		if flags != nil {
+			key, modified = flags(j)
+		}
+		if style, ok := t.cellStyle(cell, key, modified); ok {
+			b.WriteString(style.Inherit(base).Render(text))
+		} else {
+			b.WriteString(base.Render(text))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,409 @@
+package app
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Row formats for the yank command
+const (
+	yankTSV    = "tsv"
+	yankJSON   = "json"
+	yankInsert = "insert"
+)
+
+// Yank targets
+const (
+	yankCell  = "cell"
+	yankRow   = "row"
+	yankRows  = "rows"
+	yankQuery = "query"
+	yankSQL   = "sql"
+)
+
+var errNothingToYank = errors.New("nothing to copy here")
+
+// Output is the terminal the program renders to; run the program with
+// tea.WithOutput(Output). Copying writes OSC 52 sequences through it while
+// the program runs, and its writes are serialized, so a sequence never lands
+// in the middle of a frame.
+var Output = &Terminal{File: os.Stdout}
+
+// Terminal serializes the writes to a terminal file. It is still a file
+// with a descriptor, so the program recognizes the terminal.
+type Terminal struct {
+	*os.File
+	mu sync.Mutex
+}
+
+func (t *Terminal) Write(b []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.File.Write(b)
+}
+
+func (t *Terminal) WriteString(s string) (int, error) {
+	return t.Write([]byte(s))
+}
+
+// copyToClipboard copies text with an OSC 52 escape sequence, which the
+// terminal hands to the system clipboard even over SSH. Outside of SSH
+// sessions the local clipboard is written as well, for terminals that
+// ignore OSC 52.
+func copyToClipboard(text, what string) tea.Cmd {
+	return func() tea.Msg {
+		seq := osc52.New(text)
+		switch {
+		case os.Getenv("TMUX") != "":
+			seq = seq.Tmux()
+		case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+			seq = seq.Screen()
+		}
+		_, oscErr := seq.WriteTo(Output)
+
+		if !overSSH() && !clipboard.Unsupported {
+			if err := clipboard.WriteAll(text); err != nil && oscErr != nil {
+				return NotifyMsg{Severity: SeverityError, Message: fmt.Sprintf("failed to copy %s: %v", what, err)}
+			}
+		}
+		return NotifyMsg{Severity: SeverityInfo, Message: "copied " + what}
+	}
+}
+
+// overSSH reports whether teaqlite runs in an SSH session, where the local
+// clipboard belongs to the remote machine
+func overSSH() bool {
+	return os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != ""
+}
+
+// rowMarks holds the rows marked for copying, in the order they were
+// marked. Copies of the rows are kept so marks survive paging.
+type rowMarks struct {
+	keys map[string]bool
+	rows [][]string
+}
+
+func rowMarkKey(row []string) string {
+	return strings.Join(row, "\x1f")
+}
+
+// toggle marks or unmarks a row
+func (r *rowMarks) toggle(row []string) {
+	k := rowMarkKey(row)
+	if r.keys == nil {
+		r.keys = map[string]bool{}
+	}
+	if !r.keys[k] {
+		r.keys[k] = true
+		r.rows = append(r.rows, append([]string(nil), row...))
+		return
+	}
+	delete(r.keys, k)
+	for i, marked := range r.rows {
+		if rowMarkKey(marked) == k {
+			r.rows = append(r.rows[:i], r.rows[i+1:]...)
+			break
+		}
+	}
+}
+
+func (r *rowMarks) has(row []string) bool {
+	return r.keys[rowMarkKey(row)]
+}
+
+func (r *rowMarks) clear() {
+	r.keys, r.rows = nil, nil
+}
+
+// gridData is the table or query result rows are copied from
+type gridData struct {
+	table   string // empty for query results without a known source table
+	columns []ColumnInfo
+}
+
+// newGridData describes the columns of the current table or query result
+func newGridData(s *SharedData) gridData {
+	g := gridData{columns: make([]ColumnInfo, len(s.Columns))}
+	for i, name := range s.Columns {
+		g.columns[i] = s.ColumnInfoFor(i)
+		g.columns[i].Name = name
+	}
+	if s.IsQueryResult {
+		g.table = s.QueryTableName
+	} else {
+		g.table = s.currentTableName()
+	}
+	return g
+}
+
+// format renders rows as TSV, a JSON array of objects or INSERT
+// statements. A single TSV row has no header line.
+func (g gridData) format(rows [][]string, format string) (string, error) {
+	switch format {
+	case yankTSV:
+		var lines []string
+		if len(rows) > 1 {
+			var header []string
+			for _, col := range g.columns {
+				header = append(header, tsvField(col.Name))
+			}
+			lines = append(lines, strings.Join(header, "\t"))
+		}
+		for _, row := range rows {
+			fields := make([]string, len(row))
+			for i, value := range row {
+				fields[i] = tsvField(value)
+			}
+			lines = append(lines, strings.Join(fields, "\t"))
+		}
+		return strings.Join(lines, "\n"), nil
+
+	case yankJSON:
+		objects := make([]string, len(rows))
+		for i, row := range rows {
+			objects[i] = g.jsonObject(row)
+		}
+		if len(rows) == 1 {
+			return objects[0], nil
+		}
+		return "[\n  " + strings.Join(objects, ",\n  ") + "\n]", nil
+
+	case yankInsert:
+		if g.table == "" {
+			return "", errors.New("the source table of this query result is unknown")
+		}
+		var names []string
+		for _, col := range g.columns {
+			names = append(names, quoteIdent(col.Name))
+		}
+		statements := make([]string, len(rows))
+		for i, row := range rows {
+			values := make([]string, len(row))
+			for j, value := range row {
+				values[j] = cellLiteral(g.column(j), value)
+			}
+			statements[i] = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);",
+				quoteTable(g.table), strings.Join(names, ", "), strings.Join(values, ", "))
+		}
+		return strings.Join(statements, "\n"), nil
+	}
+	return "", fmt.Errorf("unknown format %q, use %s, %s or %s", format, yankTSV, yankJSON, yankInsert)
+}
+
+func (g gridData) column(i int) ColumnInfo {
+	if i < len(g.columns) {
+		return g.columns[i]
+	}
+	return ColumnInfo{}
+}
+
+// jsonObject renders a row as a JSON object with the columns in order
+func (g gridData) jsonObject(row []string) string {
+	fields := make([]string, len(row))
+	for i, value := range row {
+		col := g.column(i)
+		name, _ := json.Marshal(col.Name)
+		var v string
+		switch {
+		case value == NullValue:
+			v = "null"
+		case numericCell(col, value):
+			v = value
+		default:
+			b, _ := json.Marshal(value)
+			v = string(b)
+		}
+		fields[i] = string(name) + ": " + v
+	}
+	return "{" + strings.Join(fields, ", ") + "}"
+}
+
+// tsvField escapes the characters that would break a TSV line; NULL
+// becomes an empty field
+func tsvField(value string) string {
+	if value == NullValue {
+		return ""
+	}
+	return tsvEscaper.Replace(value)
+}
+
+// numericCell reports whether a cell holds a number that can be written
+// without quotes. Columns without a declared type, like most query result
+// columns, are judged by the value alone.
+func numericCell(col ColumnInfo, value string) bool {
+	switch col.Affinity() {
+	case AffinityInteger, AffinityReal, AffinityNumeric:
+	case AffinityBlob:
+		if col.Type != "" {
+			return false
+		}
+	default:
+		return false
+	}
+	return numberPattern.MatchString(value)
+}
+
+// cellLiteral writes a cell value as an SQL literal
+func cellLiteral(col ColumnInfo, value string) string {
+	switch {
+	case value == NullValue:
+		return "NULL"
+	case numericCell(col, value):
+		return value
+	}
+	return sqlLiteral(value)
+}
+
+// sqlLiteral writes a statement argument as an SQL literal
+func sqlLiteral(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			return "NULL"
+		case math.IsInf(v, 1):
+			return "9e999"
+		case math.IsInf(v, -1):
+			return "-9e999"
+		}
+		return formatReal(v)
+	case json.Number:
+		return string(v)
+	case []byte:
+		return "X'" + strings.ToUpper(hex.EncodeToString(v)) + "'"
+	case time.Time:
+		return sqlLiteral(v.Format(storedTimeFormat))
+	case string:
+		if v == NullValue {
+			return "NULL"
+		}
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	}
+	return sqlLiteral(fmt.Sprint(v))
+}
+
+// yank copies part of the current view. Without a target it copies the
+// selected cell, the query being edited or the statement of the cell
+// being edited, depending on the view.
+func (m *Model) yank(args []string) tea.Cmd {
+	target, format := "", yankTSV
+	if len(args) > 0 {
+		target = args[0]
+	}
+	if len(args) > 1 {
+		format = args[1]
+	}
+
+	shared := m.getSharedData()
+	var row []string
+	selectedCol := -1
+	var marks *rowMarks
+	switch v := m.currentView.(type) {
+	case *TableDataModel:
+		if v.selectedRow < len(shared.FilteredData) {
+			row = shared.FilteredData[v.selectedRow]
+		}
+		selectedCol, marks = v.selectedCol, &v.marks
+	case *RowDetailModel:
+		if v.rowIndex < len(shared.FilteredData) {
+			row = shared.FilteredData[v.rowIndex]
+		}
+		if v.selectedCol < len(shared.Columns) {
+			selectedCol = v.selectedCol
+		}
+	case *QueryModel:
+		if target == "" {
+			target = yankQuery
+			if !v.FocusOnInput && len(v.results) > 0 {
+				target = yankRow
+			}
+		}
+		if v.selectedRow < len(v.results) {
+			row = v.results[v.selectedRow]
+		}
+		marks = &v.marks
+	case *EditCellModel:
+		if target == "" {
+			target = yankSQL
+		}
+		if target == yankSQL {
+			update, err := shared.prepareUpdate(v.rowIndex, v.colIndex, v.value())
+			if err != nil {
+				return NotifyError(fmt.Errorf("cannot build the UPDATE statement: %w", err))
+			}
+			return copyToClipboard(update.SQL(), "UPDATE statement")
+		}
+	}
+	if target == "" {
+		target = yankCell
+	}
+
+	switch target {
+	case yankCell:
+		if row == nil || selectedCol < 0 || selectedCol >= len(row) {
+			return NotifyError(errNothingToYank)
+		}
+		value := row[selectedCol]
+		if value == NullValue {
+			value = ""
+		}
+		return copyToClipboard(value, shared.Columns[selectedCol])
+
+	case yankRow, yankRows:
+		rows := [][]string{row}
+		what := "row"
+		if target == yankRows {
+			if marks == nil || len(marks.rows) == 0 {
+				return Notify(SeverityWarning, "no rows marked; mark rows with space")
+			}
+			rows = marks.rows
+			what = fmt.Sprintf("%d rows", len(rows))
+		}
+		if rows[0] == nil {
+			return NotifyError(errNothingToYank)
+		}
+		text, err := newGridData(shared).format(rows, format)
+		if err != nil {
+			return NotifyError(err)
+		}
+		return copyToClipboard(text, what+" as "+format)
+
+	case yankQuery:
+		query := m.currentQueryText()
+		if strings.TrimSpace(query) == "" {
+			return NotifyError(errNothingToYank)
+		}
+		return copyToClipboard(query, "query")
+
+	case yankSQL:
+		return Notify(SeverityWarning, "the UPDATE statement can be copied while editing a cell")
+	}
+	return Notify(SeverityError, "usage: yank [cell|row|rows|query|sql] [tsv|json|insert]")
+}
+
+// completeYank completes the target and format of the yank command
+func completeYank(_ *Model, i int) []string {
+	switch i {
+	case 0:
+		return []string{yankCell, yankRow, yankRows, yankQuery, yankSQL}
+	case 1:
+		return []string{yankTSV, yankJSON, yankInsert}
+	}
+	return nil
+}
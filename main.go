@@ -1,13 +1,14 @@
 package main
 
 import (
-	"log"
+	"os"
 
 	"github.com/taigrr/teaqlite/cmd"
 )
 
 func main() {
+	// Execute prints the error itself
 	if err := cmd.Execute(); err != nil {
-		log.Fatal(err)
+		os.Exit(1)
 	}
 }